@@ -0,0 +1,51 @@
+package roastedbot
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// waitFor polls until cond returns true or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+}
+
+func newConsoleController() *Controller {
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	c := NewController(&Config{Username: "bot", OAuth: "oauth:abc123", Channels: []string{"foo"}}, logger)
+	c.Console(ioutil.Discard)
+	c.loadChannels()
+	return c
+}
+
+func TestAdmin_Disable(t *testing.T) {
+	c := newConsoleController()
+	foo, _ := c.Client.Channel("foo")
+	general, _ := foo.Module("general")
+
+	c.Client.Receive("foo", "roastedb", "@bot disable -m general -c uptime")
+	waitFor(t, func() bool { return !general.IsCommandEnabled("uptime") })
+	if !foo.IsModuleEnabled("general") {
+		t.Error("expected only the command to be disabled")
+	}
+}
+
+func TestAdmin_Enable(t *testing.T) {
+	c := newConsoleController()
+	foo, _ := c.Client.Channel("foo")
+	c.Client.DisableModule("foo", "general")
+
+	c.Client.ReceiveWhisper("roastedb", "#foo enable -m general")
+	waitFor(t, func() bool { return foo.IsModuleEnabled("general") })
+}
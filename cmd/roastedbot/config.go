@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// Exit codes of the commands.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// configCommand runs "roastedbot config <subcommand>" and returns its exit code.
+func configCommand(args []string, stdout, stderr io.Writer) int {
+	flags, configPath := newFlagSet("config check", "", stderr)
+	if len(args) == 0 || args[0] != "check" {
+		flags.Usage()
+		return exitUsage
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	if _, err := configPath.load(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	fmt.Fprintln(stdout, "configuration is valid")
+	return exitOK
+}
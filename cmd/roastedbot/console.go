@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brattonross/roastedbot"
+	"github.com/brattonross/roastedbot/pkg/store"
+	"github.com/sirupsen/logrus"
+)
+
+const consoleHelp = `Lines are sent to the channel as chat messages. Console commands:
+  /channel <name>    Chat in another channel, adding it if it is not configured
+  /user <name>       Chat as another user
+  /whisper <text>    Whisper the bot
+  /quit              Stop the console
+`
+
+// consoleIdle is how long the console waits for the bot to stop responding before it exits.
+const consoleIdle = 250 * time.Millisecond
+
+// consoleCommand runs "roastedbot console", which runs the bot without
+// connecting to twitch. Chat messages are read from stdin and the bot's
+// messages are written to stdout. Changes to the channels are not stored.
+func consoleCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags, configPath := newFlagSet("console", "", stderr)
+	storeOverride := storeFlag(flags)
+	channel := flags.String("channel", "", "Channel to chat in. Defaults to the first configured channel")
+	username := flags.String("user", "console", "User to chat as")
+	logOverrides := newLogFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	config, err := configPath.load()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	logOverrides.apply(config)
+	if config.Log.Output == "stdout" {
+		// Logs would be mixed up with the chat.
+		config.Log.Output = "stderr"
+	}
+	log := newLogger(config, logrus.New())
+
+	state := store.New()
+	if config.Store.Enabled || *storeOverride != "" {
+		path := *storeOverride
+		if path == "" {
+			path = storePath(config)
+		}
+		if state, err = store.Load(path); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+	}
+
+	out := &activityWriter{last: time.Now(), mutex: &sync.Mutex{}, w: stdout}
+	controller := roastedbot.NewController(config, log)
+	controller.Console(out)
+	if err := controller.Restore(state); err != nil {
+		log.WithField("error", err).Error("failed to restore some channel state")
+	}
+	if *channel == "" && len(config.Channels) > 0 {
+		*channel = config.Channels[0]
+	}
+	if *channel == "" {
+		fmt.Fprintln(stderr, "no channels are configured, choose one with -channel")
+		return exitUsage
+	}
+	c := &console{controller: controller, out: out, user: strings.ToLower(*username)}
+	if err := c.setChannel(*channel); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+
+	fmt.Fprintf(out, "chatting in #%s as %s, type /help for console commands\n", c.channel, c.user)
+	s := bufio.NewScanner(stdin)
+	for s.Scan() {
+		if !c.handle(strings.TrimSpace(s.Text())) {
+			break
+		}
+	}
+	if err := s.Err(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	out.waitIdle(consoleIdle)
+	return exitOK
+}
+
+// console sends the lines read by the console command to the bot.
+type console struct {
+	controller *roastedbot.Controller
+	out        io.Writer
+	channel    string
+	user       string
+}
+
+// handle handles a line, returning false if the console should stop.
+func (c *console) handle(line string) bool {
+	if line == "" {
+		return true
+	}
+	if !strings.HasPrefix(line, "/") {
+		c.controller.Client.Receive(c.channel, c.user, line)
+		return true
+	}
+
+	command, arg := line, ""
+	if i := strings.IndexByte(line, ' '); i >= 0 {
+		command, arg = line[:i], strings.TrimSpace(line[i+1:])
+	}
+	switch {
+	case command == "/quit":
+		return false
+	case command == "/help":
+		fmt.Fprint(c.out, consoleHelp)
+	case command == "/channel" && arg != "":
+		if err := c.setChannel(arg); err != nil {
+			fmt.Fprintln(c.out, err)
+		}
+	case command == "/user" && arg != "":
+		c.user = strings.ToLower(arg)
+	case command == "/whisper" && arg != "":
+		c.controller.Client.ReceiveWhisper(c.user, arg)
+	default:
+		fmt.Fprintf(c.out, "unknown console command '%s', type /help for console commands\n", line)
+	}
+	return true
+}
+
+// setChannel chats in the channel, adding it to the bot if it is not configured.
+func (c *console) setChannel(name string) error {
+	name = strings.ToLower(strings.TrimPrefix(name, "#"))
+	if _, err := c.controller.Client.Channel(name); err != nil {
+		if err := c.controller.Client.AddChannel(name); err != nil {
+			return err
+		}
+	}
+	c.channel = name
+	return nil
+}
+
+// activityWriter records when it was last written to, so that the
+// console can wait for the bot to finish responding before it exits.
+type activityWriter struct {
+	last  time.Time
+	mutex *sync.Mutex
+	w     io.Writer
+}
+
+func (a *activityWriter) Write(p []byte) (int, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.last = time.Now()
+	return a.w.Write(p)
+}
+
+// waitIdle waits until nothing has been written for the duration.
+func (a *activityWriter) waitIdle(d time.Duration) {
+	for {
+		a.mutex.Lock()
+		wait := d - time.Since(a.last)
+		a.mutex.Unlock()
+		if wait <= 0 {
+			return
+		}
+		time.Sleep(wait)
+	}
+}
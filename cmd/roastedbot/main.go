@@ -2,56 +2,278 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"flag"
-	"io/ioutil"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/brattonross/roastedbot"
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/chatlog"
+	"github.com/brattonross/roastedbot/pkg/metrics"
+	"github.com/brattonross/roastedbot/pkg/store"
+	"github.com/brattonross/roastedbot/pkg/token"
+	grpcservice "github.com/brattonross/roastedbot/pkg/twitch/service/grpc"
 	service "github.com/brattonross/roastedbot/pkg/twitch/service/http"
+	"github.com/brattonross/roastedbot/web"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
+const usage = `Usage: roastedbot [command] [flags] [arguments]
+
+Commands:
+  run              Run the bot, the default if no command is given
+  config check     Check that the configuration is valid
+  store migrate    Check that the stored channel state is in the current version
+  export [file]    Write the stored channel state as JSON to stdout or a file
+  import [file]    Replace the stored channel state with JSON from stdin or a file
+  console          Try the bot locally, reading chat messages from stdin
+
+Every command takes -config to choose the configuration, and the commands
+that use the stored channel state take -store to override its path.
+Run "roastedbot <command> -h" for the flags of a command.
+
+Exit codes:
+  0  success
+  1  failure, such as an invalid configuration
+  2  invalid command, flags or arguments
+`
+
 func main() {
-	configPath := flag.String("config", "bot.config.json", "Path of the bot configuration")
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command named by the first argument and returns its exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || (strings.HasPrefix(args[0], "-") && !isHelp(args[0])) {
+		return runCommand(args, stdout, stderr)
+	}
+	switch args[0] {
+	case "run":
+		return runCommand(args[1:], stdout, stderr)
+	case "config":
+		return configCommand(args[1:], stdout, stderr)
+	case "store":
+		return storeCommand(args[1:], stdout, stderr)
+	case "export":
+		return exportCommand(args[1:], stdout, stderr)
+	case "import":
+		return importCommand(args[1:], stdin, stdout, stderr)
+	case "console":
+		return consoleCommand(args[1:], stdin, stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command '%s'\n\n%s", args[0], usage)
+		return exitUsage
+	}
+}
+
+func isHelp(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+// configFlag is the -config flag shared by every command. The default
+// configuration file is optional, so that the bot can be configured by
+// environment variables alone, but a file that is named must exist.
+type configFlag struct {
+	path string
+	set  bool
+}
+
+func (f *configFlag) String() string {
+	return f.path
+}
+
+func (f *configFlag) Set(path string) error {
+	f.path, f.set = path, true
+	return nil
+}
+
+// load reads the configuration.
+func (f *configFlag) load() (*roastedbot.Config, error) {
+	return loadConfig(f.path, !f.set)
+}
+
+// newFlagSet creates the flags of a command, including the -config flag.
+func newFlagSet(command, arguments string, stderr io.Writer) (*flag.FlagSet, *configFlag) {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: roastedbot %s [flags] %s\n\nFlags:\n", command, arguments)
+		flags.PrintDefaults()
+	}
+	config := &configFlag{path: "bot.config.json"}
+	flags.Var(config, "config", "Path of the bot configuration")
+	return flags, config
+}
+
+// logFlags are the flags that override the configured logging.
+type logFlags struct {
+	level, format, output *string
+}
 
-	flag.Parse()
+func newLogFlags(flags *flag.FlagSet) *logFlags {
+	return &logFlags{
+		level:  flags.String("log-level", "", "Lowest level to log, overriding the configuration"),
+		format: flags.String("log-format", "", "Log format, text or json, overriding the configuration"),
+		output: flags.String("log-output", "", "Log to stdout, stderr or a file, overriding the configuration"),
+	}
+}
+
+// apply overrides the logging of the configuration with the flags that are set.
+func (f *logFlags) apply(config *roastedbot.Config) {
+	if *f.level != "" {
+		config.Log.Level = *f.level
+	}
+	if *f.format != "" {
+		config.Log.Format = *f.format
+	}
+	if *f.output != "" {
+		config.Log.Output = *f.output
+	}
+}
+
+// runCommand runs "roastedbot run", which runs the bot until it is interrupted.
+func runCommand(args []string, stdout, stderr io.Writer) int {
+	flags, configPath := newFlagSet("run", "", stderr)
+	hashToken := flags.String("hash-token", "", "Print the hash of an API token for the configuration and exit")
+	logOverrides := newLogFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	if *hashToken != "" {
+		fmt.Fprintln(stdout, auth.HashToken(*hashToken))
+		return exitOK
+	}
 
 	log := logrus.New()
 
 	// Read config
-	b, err := ioutil.ReadFile(*configPath)
-	if err != nil {
-		log.WithFields(logrus.Fields{
-			"configPath": *configPath,
-			"error":      err,
-		}).Fatal("failed to read configuration file")
+	load := func() (*roastedbot.Config, error) {
+		config, err := configPath.load()
+		if err != nil {
+			return nil, err
+		}
+		logOverrides.apply(config)
+		return config, nil
 	}
-	config := &roastedbot.Config{}
-	err = json.Unmarshal(b, config)
+	config, err := load()
 	if err != nil {
-		log.WithField("error", err).Fatal("failed to unmarshal configuration file")
+		logConfigErrors(log, err)
+		log.WithField("configPath", configPath.path).Fatal("failed to load configuration")
 	}
-	
+	log = newLogger(config, log)
+
+	tokens := openTokens(config, log)
 	controller := roastedbot.NewController(config, log)
+	if tokens != nil {
+		// A refreshed token from an earlier run takes the place of the configured one.
+		if t := tokens.Token(); t.Chat() != string(config.OAuth) {
+			roastedbot.RedactSecrets(log, t.AccessToken, t.RefreshToken)
+			controller.Client.Reauthenticate(t.Chat())
+		}
+		tokens.OnRefresh(func(t token.Token) {
+			roastedbot.RedactSecrets(log, t.AccessToken, t.RefreshToken)
+			log.Info("refreshed chat token, reconnecting to twitch")
+			controller.Client.Reauthenticate(t.Chat())
+		})
+		defer tokens.Start()()
+	}
+	if config.Store.Enabled {
+		state, err := store.Load(storePath(config))
+		if err != nil {
+			log.WithField("error", err).Fatal("failed to load channel state")
+		}
+		if err := controller.Restore(state); err != nil {
+			log.WithField("error", err).Error("failed to restore some channel state")
+		}
+		defer controller.StartSavingState(storePath(config), stateInterval)()
+	}
+	controller.Client.Reload = func() ([]string, error) {
+		next, err := load()
+		if err != nil {
+			return nil, err
+		}
+		return controller.Reload(next)
+	}
+	auditPath := config.Audit.Path
+	if auditPath == "" {
+		auditPath = "audit.jsonl"
+	}
+	auditLog, err := audit.Open(auditPath)
+	if err != nil {
+		log.WithField("error", err).Fatal("failed to open audit log")
+	}
+	defer auditLog.Close()
+	controller.Client.Audit = auditLog
+
+	if config.ChatLog.Enabled {
+		archive := openChatLog(config.ChatLog, controller, log)
+		defer archive.Close()
+		defer archive.Start()()
+		controller.Client.ChatLog = archive
+	}
+
+	// The bot stops if the connection fails, after the deferred closes have run.
+	failed := make(chan error, 1)
 	go func() {
-		if err = controller.Connect(); err != nil {
-			log.Fatalf("fatal error occurred while bot was running: %v", err)
+		if err := controller.Connect(); err != nil {
+			failed <- err
 		}
 	}()
 
-	handler := service.NewHandler(controller.Client)
-	server := &http.Server{
-		Addr: ":9001",
-		Handler: handler,
+	var server *service.Server
+	if config.HTTP.Disabled {
+		log.Info("http server is disabled")
+	} else {
+		server = newServer(config, controller, log)
+		go func() {
+			c := server.Config()
+			log.WithFields(logrus.Fields{
+				"address": c.Address,
+				"tls":     c.TLS(),
+			}).Info("starting http server")
+			if err := server.ListenAndServe(); err != nil {
+				log.Errorf("http server encountered an error: %v", err)
+			}
+		}()
+	}
+
+	var grpcServer *grpc.Server
+	if config.GRPC.Address != "" {
+		var l net.Listener
+		grpcServer, l = newGRPCServer(config, controller, log)
+		go func() {
+			log.WithField("address", config.GRPC.Address).Info("starting grpc server")
+			if err := grpcServer.Serve(l); err != nil {
+				log.Errorf("grpc server encountered an error: %v", err)
+			}
+		}()
 	}
+
+	// Reload the configuration on SIGHUP
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
 	go func() {
-		log.Infof("starting http server on port 9001")
-		if err := server.ListenAndServe(); err != nil {
-			log.Errorf("http server encountered an error: %v", err)
+		for range sighup {
+			reloadConfig(controller, log)
 		}
 	}()
 
@@ -59,14 +281,28 @@ func main() {
 	sigquit := make(chan os.Signal, 1)
 	signal.Notify(sigquit, os.Interrupt, syscall.SIGTERM)
 
-	sig := <-sigquit
-	log.Infof("caught sig: %+v", sig)
-	
-	log.Infof("gracefully shutting down controller...")
-	if err := server.Shutdown(context.Background()); err != nil {
-		log.Errorf("unable to shut down server: %v", err)
-	} else {
-		log.Info("server stopped")
+	code := exitOK
+	select {
+	case sig := <-sigquit:
+		log.Infof("caught sig: %+v", sig)
+	case err := <-failed:
+		log.Errorf("fatal error occurred while bot was running: %v", err)
+		code = exitFailure
+	}
+
+	if server != nil {
+		log.Infof("gracefully shutting down server...")
+		if err := server.Shutdown(); err != nil {
+			log.Errorf("unable to shut down server gracefully: %v", err)
+		} else {
+			log.Info("server stopped")
+		}
+	}
+
+	if grpcServer != nil {
+		log.Infof("gracefully shutting down grpc server...")
+		stopGRPCServer(grpcServer, time.Duration(config.GRPC.ShutdownGrace))
+		log.Info("grpc server stopped")
 	}
 
 	log.Infof("gracefully shutting down client...")
@@ -75,4 +311,235 @@ func main() {
 	} else {
 		log.Infof("client stopped")
 	}
+	return code
+}
+
+// newLogger creates the logger of the configuration, which hides the configured
+// secrets. It exits if the logging configuration is invalid.
+func newLogger(config *roastedbot.Config, log *logrus.Logger) *logrus.Logger {
+	var secrets []string
+	if config.API.Login != nil {
+		secrets = append(secrets, config.API.Login.ClientSecret)
+	}
+	secrets = append(secrets, string(config.OAuth), string(config.Token.RefreshToken), string(config.Token.ClientSecret))
+	logger, err := roastedbot.NewLogger(config.Log, secrets...)
+	if err != nil {
+		log.WithField("error", err).Fatal("invalid log configuration")
+	}
+	return logger
+}
+
+// loadConfig reads the configuration file and applies the environment variables
+// on top of it. The file is optional if it is the default, so that the bot can
+// be configured by environment variables alone.
+func loadConfig(path string, optional bool) (*roastedbot.Config, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) && optional {
+		path = ""
+	}
+	return roastedbot.LoadConfig(path, os.LookupEnv)
+}
+
+// logConfigErrors logs each problem with the configuration on its own line.
+func logConfigErrors(log *logrus.Logger, err error) {
+	var problems roastedbot.ConfigErrors
+	if !errors.As(err, &problems) {
+		log.WithField("error", err).Error("invalid configuration")
+		return
+	}
+	for _, p := range problems {
+		log.WithField("error", p).Error("invalid configuration")
+	}
+}
+
+// openTokens validates the chat token, refreshing it if it has expired, and
+// returns the manager that keeps it valid. It returns nil if validation is disabled.
+func openTokens(config *roastedbot.Config, log *logrus.Logger) *token.Manager {
+	c := config.Token
+	if c.Disabled {
+		log.Info("chat token validation is disabled")
+		return nil
+	}
+	path := c.Path
+	if path == "" {
+		path = "token.json"
+	}
+	m, err := token.New(token.Token{
+		AccessToken:  string(config.OAuth),
+		RefreshToken: string(c.RefreshToken),
+	}, token.Options{
+		ClientID:     c.ClientID,
+		ClientSecret: string(c.ClientSecret),
+		ValidateURL:  c.ValidateURL,
+		TokenURL:     c.TokenURL,
+		Path:         path,
+		Interval:     time.Duration(c.Interval),
+		Log:          log,
+	})
+	if err != nil {
+		log.WithField("error", err).Fatal("failed to load chat token")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	v, err := m.Check(ctx)
+	switch {
+	case errors.Is(err, token.ErrInvalid):
+		log.WithField("error", err).Fatal("chat token is invalid")
+	case err != nil:
+		log.WithField("error", err).Warn("unable to validate chat token, it will be checked again later")
+	case !strings.EqualFold(v.Login, config.Username):
+		log.WithField("login", v.Login).Fatal("chat token belongs to a different account than the username")
+	default:
+		log.WithField("expires", m.Token().Expires).Info("validated chat token")
+	}
+	if !m.CanRefresh() {
+		log.Warn("chat token cannot be refreshed without token.refreshToken, token.clientId and token.clientSecret")
+	}
+	return m
+}
+
+// reloadConfig reloads the configuration and logs what changed.
+func reloadConfig(controller *roastedbot.Controller, log *logrus.Logger) {
+	log.Info("reloading configuration")
+	changes, err := controller.Client.Reload()
+	if changes == nil && err != nil {
+		logConfigErrors(log, err)
+		log.Error("failed to reload configuration, keeping the current configuration")
+		return
+	}
+	for _, change := range changes {
+		log.WithField("change", change).Info("configuration changed")
+	}
+	if err != nil {
+		log.WithField("error", err).Error("failed to apply some configuration changes")
+	}
+	log.WithField("changes", len(changes)).Info("reloaded configuration")
+}
+
+// openChatLog opens the chat log archive and starts archiving the chat
+// of the channels that have the chat log module enabled.
+func openChatLog(c roastedbot.ChatLogConfig, controller *roastedbot.Controller, log *logrus.Logger) *chatlog.Archive {
+	if c.Dir == "" {
+		c.Dir = "logs"
+	}
+	archive, err := chatlog.Open(chatlog.Options{
+		Dir:           c.Dir,
+		RetentionDays: c.RetentionDays,
+		Compress:      c.Compress,
+		Search:        c.Search,
+		Log:           log,
+	})
+	if err != nil {
+		log.WithField("error", err).Fatal("failed to open chat log")
+	}
+	controller.Client.OnChatLine(func(channel, line string) {
+		if ch, err := controller.Client.Channel(channel); err == nil && ch.IsModuleEnabled(chatlog.ModuleName) {
+			archive.Write(line)
+		}
+	})
+	return archive
+}
+
+// newServer creates the HTTP server that serves the API and the dashboard.
+func newServer(config *roastedbot.Config, controller *roastedbot.Controller, log *logrus.Logger) *service.Server {
+	tokens, err := auth.NewTokens(config.API.Tokens)
+	if err != nil {
+		log.WithField("error", err).Fatal("invalid api tokens in configuration file")
+	}
+	var login *auth.OAuth
+	if config.API.Login != nil {
+		if login, err = auth.NewOAuth(*config.API.Login); err != nil {
+			log.WithField("error", err).Fatal("invalid login in configuration file")
+		}
+	}
+	if len(config.API.Tokens) == 0 && login == nil {
+		log.Warn("no api tokens or login are configured, every api request will be rejected")
+	}
+	dashboard := web.Dashboard()
+	if dashboard == nil {
+		log.Info("the dashboard was not built into this binary and will not be served")
+	}
+	var metricsHandler http.Handler
+	if !config.Metrics.Disabled {
+		m := metrics.New(controller.Client)
+		m.Subscribe()
+		metricsHandler = m.Handler()
+	}
+	handler := service.NewHandler(controller.Client, service.Options{
+		AllowedOrigins: config.API.AllowedOrigins,
+		BaseURL:        config.API.BaseURL,
+		Dashboard:      dashboard,
+		Log:            log,
+		Login:          login,
+		Metrics:        metricsHandler,
+		Tokens:         tokens,
+	})
+
+	c := config.HTTP
+	serverConfig := service.ServerConfig{
+		Address:       c.Address,
+		ReadTimeout:   time.Duration(c.ReadTimeout),
+		WriteTimeout:  time.Duration(c.WriteTimeout),
+		IdleTimeout:   time.Duration(c.IdleTimeout),
+		ShutdownGrace: time.Duration(c.ShutdownGrace),
+	}
+	if c.TLS != nil {
+		serverConfig.CertFile = c.TLS.CertFile
+		serverConfig.KeyFile = c.TLS.KeyFile
+		serverConfig.SelfSigned = c.TLS.SelfSigned
+		if !serverConfig.TLS() {
+			log.Fatal("tls in configuration file requires a certificate and key file or selfSigned")
+		}
+	}
+	server, err := service.NewServer(handler, serverConfig)
+	if err != nil {
+		log.WithField("error", err).Fatal("invalid http server in configuration file")
+	}
+	if serverConfig.SelfSigned {
+		log.Warn("serving https with a self-signed certificate, which should only be used for development")
+	}
+	return server
+}
+
+// newGRPCServer creates the gRPC server that serves the BotAdmin service
+// and the listener that it should serve on.
+func newGRPCServer(config *roastedbot.Config, controller *roastedbot.Controller, log *logrus.Logger) (*grpc.Server, net.Listener) {
+	tokens, err := auth.NewTokens(config.API.Tokens)
+	if err != nil {
+		log.WithField("error", err).Fatal("invalid api tokens in configuration file")
+	}
+	c := config.GRPC
+	var opts []grpc.ServerOption
+	if c.CertFile != "" || c.KeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(c.CertFile, c.KeyFile)
+		if err != nil {
+			log.WithField("error", err).Fatal("invalid grpc tls in configuration file")
+		}
+		opts = append(opts, grpc.Creds(creds))
+	} else {
+		log.Warn("serving grpc without tls, api tokens will be sent in plain text")
+	}
+	l, err := net.Listen("tcp", c.Address)
+	if err != nil {
+		log.WithField("error", err).Fatal("unable to listen for grpc")
+	}
+	return grpcservice.NewServer(controller.Client, tokens, log, opts...), l
+}
+
+// stopGRPCServer waits for calls to finish, then stops the server
+// once the grace period has passed. Event streams only end when stopped.
+func stopGRPCServer(s *grpc.Server, grace time.Duration) {
+	if grace == 0 {
+		grace = service.DefaultShutdownGrace
+	}
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(grace):
+		s.Stop()
+	}
 }
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brattonross/roastedbot/pkg/store"
+)
+
+func runBot(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bot.config.json")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := [][]string{
+		{"unknown"},
+		{"config"},
+		{"config", "lint"},
+		{"config", "check", "extra"},
+		{"store", "migrate", "-unknown"},
+		{"export", "one", "two"},
+		{"run", "-unknown"},
+	}
+	for _, args := range tests {
+		if code, _, _ := runBot("", args...); code != exitUsage {
+			t.Errorf("%q: expected exit code %d, got %d", args, exitUsage, code)
+		}
+	}
+	if code, out, _ := runBot("", "help"); code != exitOK || !strings.Contains(out, "store migrate") {
+		t.Errorf("expected the usage, got %d and %q", code, out)
+	}
+}
+
+func TestConfigCheck(t *testing.T) {
+	valid := writeTestConfig(t, `{"username": "bot", "oauth": "oauth:abc123", "channels": ["foo"]}`)
+	if code, out, errOut := runBot("", "config", "check", "-config", valid); code != exitOK || out != "configuration is valid\n" {
+		t.Errorf("expected a valid configuration, got %d, %q and %q", code, out, errOut)
+	}
+	invalid := writeTestConfig(t, `{"username": "bot"}`)
+	if code, _, errOut := runBot("", "config", "check", "-config", invalid); code != exitFailure || !strings.Contains(errOut, "oauth") {
+		t.Errorf("expected an invalid configuration, got %d and %q", code, errOut)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.json")
+	input := `{"version": 1, "channels": [{"name": "foo", "replyMode": "action"}]}`
+
+	code, out, errOut := runBot(input, "import", "-store", path)
+	if code != exitOK || !strings.Contains(out, "imported the state of 1 channels") {
+		t.Fatalf("expected the state to be imported, got %d, %q and %q", code, out, errOut)
+	}
+	code, out, errOut = runBot("", "export", "-store", path)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	s, err := store.Read(strings.NewReader(out))
+	if err != nil || len(s.Channels) != 1 || s.Channels[0].ReplyMode != "action" {
+		t.Errorf("expected the imported state, got %+v and %v", s, err)
+	}
+
+	// The store is left alone if the imported state is invalid.
+	if code, _, _ := runBot(`{"version": 1, "channels": [{"name": ""}]}`, "import", "-store", path); code != exitFailure {
+		t.Errorf("expected exit code %d for invalid state, got %d", exitFailure, code)
+	}
+	if s, err := store.Load(path); err != nil || len(s.Channels) != 1 {
+		t.Errorf("expected the stored state to be unchanged, got %+v and %v", s, err)
+	}
+}
+
+func TestStoreMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if code, out, _ := runBot("", "store", "migrate", "-store", path); code != exitOK || !strings.Contains(out, "nothing to migrate") {
+		t.Errorf("expected nothing to migrate, got %d and %q", code, out)
+	}
+	ioutil.WriteFile(path, []byte(`{"version": 1, "channels": []}`), 0600)
+	if code, out, _ := runBot("", "store", "migrate", "-store", path); code != exitOK || !strings.Contains(out, "already version 1") {
+		t.Errorf("expected the state to be current, got %d and %q", code, out)
+	}
+	ioutil.WriteFile(path, []byte(`{"version": 99, "channels": []}`), 0600)
+	if code, _, errOut := runBot("", "store", "migrate", "-store", path); code != exitFailure || !strings.Contains(errOut, "newer version") {
+		t.Errorf("expected state from a newer version to fail, got %d and %q", code, errOut)
+	}
+}
+
+func TestConsole(t *testing.T) {
+	config := writeTestConfig(t, `{"username": "bot", "oauth": "oauth:abc123", "channels": ["foo"]}`)
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	code, out, errOut := runBot("!xd\n/channel bar\n/user someone\n@bot\n/nope\n",
+		"console", "-config", config, "-store", statePath, "-log-level", "error")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	for _, expected := range []string{
+		"chatting in #foo as console",
+		"#foo <bot> xD",
+		"#bar <bot> hi someone :)",
+		"unknown console command '/nope'",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("expected output to contain %q, got %q", expected, out)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/brattonross/roastedbot"
+	"github.com/brattonross/roastedbot/pkg/store"
+)
+
+// stateInterval is how often the running bot stores the state of its channels.
+const stateInterval = time.Minute
+
+// storePath returns the path of the configuration's store.
+func storePath(config *roastedbot.Config) string {
+	if config.Store.Path == "" {
+		return "state.json"
+	}
+	return config.Store.Path
+}
+
+// storeFlag adds the -store flag, which overrides the configured path of the store.
+func storeFlag(flags *flag.FlagSet) *string {
+	return flags.String("store", "", "Path of the stored channel state, overriding the configuration")
+}
+
+// resolveStore returns the path of the store, which is read from the
+// configuration unless it is given with the -store flag.
+func resolveStore(path string, configPath *configFlag) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	config, err := configPath.load()
+	if err != nil {
+		return "", err
+	}
+	return storePath(config), nil
+}
+
+// storeCommand runs "roastedbot store <subcommand>" and returns its exit code.
+func storeCommand(args []string, stdout, stderr io.Writer) int {
+	flags, configPath := newFlagSet("store migrate", "", stderr)
+	storeOverride := storeFlag(flags)
+	if len(args) == 0 || args[0] != "migrate" {
+		flags.Usage()
+		return exitUsage
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	path, err := resolveStore(*storeOverride, configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Fprintf(stdout, "no state is stored at %s, nothing to migrate\n", path)
+		return exitOK
+	}
+	// There are no older versions yet, so the state only has to be readable.
+	s, err := store.Load(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	fmt.Fprintf(stdout, "state at %s is already version %d\n", path, s.Version)
+	return exitOK
+}
+
+// exportCommand runs "roastedbot export", which writes the stored state
+// of every channel to stdout or the file given as an argument.
+func exportCommand(args []string, stdout, stderr io.Writer) int {
+	flags, configPath := newFlagSet("export", "[file]", stderr)
+	storeOverride := storeFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	path, err := resolveStore(*storeOverride, configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	s, err := store.Load(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	if file := flags.Arg(0); file != "" && file != "-" {
+		err = store.Save(file, s)
+	} else {
+		err = s.Write(stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// importCommand runs "roastedbot import", which replaces the stored state
+// with the state read from stdin or the file given as an argument. The bot
+// should be stopped first, as it stores its own state as it runs.
+func importCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags, configPath := newFlagSet("import", "[file]", stderr)
+	storeOverride := storeFlag(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	path, err := resolveStore(*storeOverride, configPath)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	in := stdin
+	if file := flags.Arg(0); file != "" && file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailure
+		}
+		defer f.Close()
+		in = f
+	}
+	s, err := store.Read(in)
+	if err == nil {
+		err = store.Save(path, s)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailure
+	}
+	fmt.Fprintf(stdout, "imported the state of %d channels to %s\n", len(s.Channels), path)
+	return exitOK
+}
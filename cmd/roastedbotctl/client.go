@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// client calls the bot's management API.
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newClient(baseURL, token string) *client {
+	return &client{
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/api/v1",
+		token:   token,
+		http:    &http.Client{},
+	}
+}
+
+// apiError is an error response from the API.
+type apiError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (%d %s)", e.Message, e.Status, e.Code)
+}
+
+type channel struct {
+	Name      string `json:"name"`
+	RoomState struct {
+		EmoteOnly     bool `json:"emoteOnly"`
+		FollowersOnly int  `json:"followersOnly"`
+		R9K           bool `json:"r9k"`
+		Slow          int  `json:"slow"`
+		SubsOnly      bool `json:"subsOnly"`
+	} `json:"roomState"`
+	UserState struct {
+		Broadcaster bool `json:"broadcaster"`
+		Moderator   bool `json:"moderator"`
+		Subscriber  bool `json:"subscriber"`
+		VIP         bool `json:"vip"`
+	} `json:"userState"`
+	ReplyMode      string `json:"replyMode"`
+	RestrictedMode string `json:"restrictedMode"`
+	Restriction    *struct {
+		Reason string    `json:"reason"`
+		Until  time.Time `json:"until"`
+	} `json:"restriction,omitempty"`
+	QueueLength int      `json:"queueLength"`
+	Modules     []module `json:"modules"`
+}
+
+type module struct {
+	Name     string    `json:"name"`
+	Enabled  bool      `json:"enabled"`
+	Commands []command `json:"commands"`
+}
+
+type command struct {
+	Name     string     `json:"name"`
+	Use      string     `json:"use"`
+	Enabled  bool       `json:"enabled"`
+	Cooldown string     `json:"cooldown"`
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+	Response string     `json:"response,omitempty"`
+}
+
+type principal struct {
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+	Channels []string `json:"channels"`
+}
+
+// event is an event received from the event stream.
+type event struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+func (c *client) me(ctx context.Context) (*principal, error) {
+	p := &principal{}
+	return p, c.do(ctx, http.MethodGet, "/me", nil, p)
+}
+
+func (c *client) channels(ctx context.Context) ([]channel, error) {
+	var channels []channel
+	return channels, c.do(ctx, http.MethodGet, "/channels", nil, &channels)
+}
+
+func (c *client) channel(ctx context.Context, name string) (*channel, error) {
+	ch := &channel{}
+	return ch, c.do(ctx, http.MethodGet, "/channels/"+url.PathEscape(name), nil, ch)
+}
+
+func (c *client) join(ctx context.Context, name string) (*channel, error) {
+	ch := &channel{}
+	return ch, c.do(ctx, http.MethodPost, "/channels", map[string]string{"name": name}, ch)
+}
+
+func (c *client) part(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/channels/"+url.PathEscape(name), nil, nil)
+}
+
+func (c *client) say(ctx context.Context, channel, text string) error {
+	return c.do(ctx, http.MethodPost, "/channels/"+url.PathEscape(channel)+"/messages", map[string]string{"text": text}, nil)
+}
+
+func (c *client) setModuleEnabled(ctx context.Context, channel, name string, enabled bool) (*module, error) {
+	m := &module{}
+	path := "/channels/" + url.PathEscape(channel) + "/modules/" + url.PathEscape(name)
+	return m, c.do(ctx, http.MethodPatch, path, map[string]bool{"enabled": enabled}, m)
+}
+
+func (c *client) setCommandEnabled(ctx context.Context, channel, module, name string, enabled bool) (*command, error) {
+	cmd := &command{}
+	path := "/channels/" + url.PathEscape(channel) + "/modules/" + url.PathEscape(module) + "/commands/" + url.PathEscape(name)
+	return cmd, c.do(ctx, http.MethodPatch, path, map[string]bool{"enabled": enabled}, cmd)
+}
+
+// events streams events until the context is cancelled or the stream ends,
+// calling fn with each event.
+func (c *client) events(ctx context.Context, channels, types []string, fn func(event)) error {
+	q := url.Values{}
+	for _, ch := range channels {
+		q.Add("channel", ch)
+	}
+	for _, t := range types {
+		q.Add("type", t)
+	}
+	path := "/events"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	resp, err := c.request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	e := event{}
+	s := bufio.NewScanner(resp.Body)
+	for s.Scan() {
+		line := s.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			e.Type = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			e.Data = json.RawMessage(strings.TrimPrefix(line, "data: "))
+		case line == "" && e.Type != "":
+			fn(e)
+			e = event{}
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("the event stream was closed by the server")
+}
+
+// do sends a request with body encoded as JSON and decodes the response into v.
+func (c *client) do(ctx context.Context, method, path string, body, v interface{}) error {
+	resp, err := c.request(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("unable to decode response: %v", err)
+	}
+	return nil
+}
+
+// request sends a request, returning an error if the response is not successful.
+func (c *client) request(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, r)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	var e struct {
+		Error *apiError `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == nil {
+		return nil, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return nil, e.Error
+}
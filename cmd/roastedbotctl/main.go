@@ -0,0 +1,231 @@
+// Command roastedbotctl manages a running bot through its management API.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+const usage = `Usage: roastedbotctl [flags] <command> [arguments]
+
+Commands:
+  status                                          Show the token's access and a summary of the bot
+  channels                                        List the joined channels
+  modules <channel>                               List the modules of a channel
+  commands <channel> <module>                     List the commands of a module
+  enable module <channel> <module>                Enable a module
+  enable command <channel> <module> <command>     Enable a command
+  disable module <channel> <module>               Disable a module
+  disable command <channel> <module> <command>    Disable a command
+  join <channel>                                  Join a channel
+  part <channel>                                  Leave a channel
+  say <channel> <message>                         Send a message to a channel
+  events [-channel name] [-type type]             Print live events until interrupted
+
+Flags:
+`
+
+// errUsage is returned when a command is called with the wrong arguments.
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sig
+		cancel()
+	}()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs roastedbotctl with the given arguments and returns its exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("roastedbotctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	url := flags.String("url", envOr("ROASTEDBOTCTL_URL", "http://localhost:9001"), "URL of the bot's HTTP server (env ROASTEDBOTCTL_URL)")
+	token := flags.String("token", os.Getenv("ROASTEDBOTCTL_TOKEN"), "API token (env ROASTEDBOTCTL_TOKEN)")
+	output := flags.String("output", "table", "Output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(stderr, "unknown output format '%s'\n", *output)
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	c := newClient(*url, *token)
+	p := &printer{w: stdout, json: *output == "json"}
+	err := runCommand(ctx, c, p, flags.Arg(0), flags.Args()[1:])
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "%v\n\n", err)
+		flags.Usage()
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return exitError
+	}
+}
+
+func runCommand(ctx context.Context, c *client, p *printer, name string, args []string) error {
+	switch name {
+	case "status":
+		if len(args) != 0 {
+			return usageError(name)
+		}
+		me, err := c.me(ctx)
+		if err != nil {
+			return err
+		}
+		channels, err := c.channels(ctx)
+		if err != nil {
+			return err
+		}
+		return p.status(status{URL: strings.TrimSuffix(c.baseURL, "/api/v1"), Principal: *me, Channels: channels})
+
+	case "channels":
+		if len(args) != 0 {
+			return usageError(name)
+		}
+		channels, err := c.channels(ctx)
+		if err != nil {
+			return err
+		}
+		return p.channels(channels)
+
+	case "modules":
+		if len(args) != 1 {
+			return usageError(name)
+		}
+		ch, err := c.channel(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return p.modules(ch.Modules)
+
+	case "commands":
+		if len(args) != 2 {
+			return usageError(name)
+		}
+		ch, err := c.channel(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		for _, m := range ch.Modules {
+			if strings.EqualFold(m.Name, args[1]) {
+				return p.commands(m.Commands)
+			}
+		}
+		return fmt.Errorf("module '%s' was not found in channel '%s'", args[1], ch.Name)
+
+	case "enable", "disable":
+		enabled := name == "enable"
+		switch {
+		case len(args) == 3 && args[0] == "module":
+			m, err := c.setModuleEnabled(ctx, args[1], args[2], enabled)
+			if err != nil {
+				return err
+			}
+			return p.modules([]module{*m})
+		case len(args) == 4 && args[0] == "command":
+			cmd, err := c.setCommandEnabled(ctx, args[1], args[2], args[3], enabled)
+			if err != nil {
+				return err
+			}
+			return p.commands([]command{*cmd})
+		}
+		return usageError(name)
+
+	case "join":
+		if len(args) != 1 {
+			return usageError(name)
+		}
+		ch, err := c.join(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		return p.channels([]channel{*ch})
+
+	case "part":
+		if len(args) != 1 {
+			return usageError(name)
+		}
+		return c.part(ctx, args[0])
+
+	case "say":
+		if len(args) < 2 {
+			return usageError(name)
+		}
+		return c.say(ctx, args[0], strings.Join(args[1:], " "))
+
+	case "events":
+		flags := flag.NewFlagSet("events", flag.ContinueOnError)
+		flags.SetOutput(ioutil.Discard)
+		var channels, types listFlag
+		flags.Var(&channels, "channel", "")
+		flags.Var(&types, "type", "")
+		if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+			return usageError(name)
+		}
+		var printErr error
+		err := c.events(ctx, channels, types, func(e event) {
+			if printErr == nil {
+				printErr = p.event(e)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return printErr
+	}
+	return fmt.Errorf("%w: unknown command '%s'", errUsage, name)
+}
+
+func usageError(command string) error {
+	return fmt.Errorf("%w for '%s'", errUsage, command)
+}
+
+// listFlag is a flag that may be given more than once.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tirc "github.com/gempir/go-twitch-irc"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+	botevent "github.com/brattonross/roastedbot/pkg/event"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+	service "github.com/brattonross/roastedbot/pkg/twitch/service/http"
+)
+
+func newTestServer(t *testing.T) (*twitch.Client, *httptest.Server) {
+	client := twitch.NewClient("bot", tirc.NewClient("bot", "oauth:token"))
+	if err := client.AddChannel("test"); err != nil {
+		t.Fatal(err)
+	}
+	client.AddCommand("test", "general", &twitch.Command{Name: "ping", Use: "ping"})
+	tokens, err := auth.NewTokens([]auth.Token{
+		{Name: "admin", Hash: auth.HashToken("admin"), Scopes: []auth.Scope{auth.ScopeGlobalAdmin}},
+		{Name: "reader", Hash: auth.HashToken("reader"), Scopes: []auth.Scope{auth.ScopeRead}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := log.New()
+	logger.Out = ioutil.Discard
+
+	s := httptest.NewServer(service.NewHandler(client, service.Options{Log: logger, Tokens: tokens}))
+	t.Cleanup(s.Close)
+	return client, s
+}
+
+func runCtl(s *httptest.Server, token string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-url", s.URL, "-token", token}, args...)
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestChannels(t *testing.T) {
+	_, s := newTestServer(t)
+
+	code, out, errOut := runCtl(s, "reader", "channels")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	if !strings.HasPrefix(out, "CHANNEL") || !strings.Contains(out, "test") {
+		t.Errorf("expected a table of channels, got %q", out)
+	}
+
+	code, out, _ = runCtl(s, "reader", "-output", "json", "channels")
+	var channels []channel
+	if err := json.Unmarshal([]byte(out), &channels); err != nil || code != exitOK {
+		t.Fatalf("expected JSON output, got %q (%v)", out, err)
+	}
+	if len(channels) != 1 || channels[0].Name != "test" || channels[0].Modules[0].Name != "general" {
+		t.Errorf("unexpected channels %+v", channels)
+	}
+}
+
+func TestEnable(t *testing.T) {
+	client, s := newTestServer(t)
+
+	code, _, errOut := runCtl(s, "admin", "enable", "module", "test", "general")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	ch, _ := client.Channel("test")
+	if !ch.IsModuleEnabled("general") {
+		t.Error("expected module to be enabled")
+	}
+
+	code, _, errOut = runCtl(s, "admin", "disable", "command", "test", "general", "ping")
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, errOut)
+	}
+	m, _ := ch.Module("general")
+	if m.IsCommandEnabled("ping") {
+		t.Error("expected command to be disabled")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	_, s := newTestServer(t)
+
+	code, _, errOut := runCtl(s, "reader", "part", "test")
+	if code != exitError || !strings.Contains(errOut, "403") {
+		t.Errorf("expected exit code %d with a forbidden error, got %d: %s", exitError, code, errOut)
+	}
+	code, _, errOut = runCtl(s, "admin", "modules", "missing")
+	if code != exitError || !strings.Contains(errOut, "not_found") {
+		t.Errorf("expected exit code %d with a not found error, got %d: %s", exitError, code, errOut)
+	}
+	code, _, _ = runCtl(s, "admin", "enable", "module", "test")
+	if code != exitUsage {
+		t.Errorf("expected exit code %d for missing arguments, got %d", exitUsage, code)
+	}
+	code, _, _ = runCtl(s, "admin", "unknown")
+	if code != exitUsage {
+		t.Errorf("expected exit code %d for an unknown command, got %d", exitUsage, code)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	client, s := newTestServer(t)
+	c := newClient(s.URL, "reader")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan event, 1)
+	done := make(chan error)
+	go func() {
+		done <- c.events(ctx, []string{"test"}, nil, func(e event) {
+			select {
+			case received <- e:
+			default:
+			}
+		})
+	}()
+
+	// The stream subscribes once the request is handled,
+	// so keep publishing until the first event arrives.
+	for e := (event{}); e.Type == ""; {
+		client.Events.Publish(botevent.MessageReceived{Channel: "test", Username: "user", Text: "hello"})
+		select {
+		case e = <-received:
+			if e.Type != "message_received" || !strings.Contains(string(e.Data), `"hello"`) {
+				t.Errorf("unexpected event %+v", e)
+			}
+		case err := <-done:
+			t.Fatalf("stream ended early: %v", err)
+		case <-time.After(time.Millisecond * 20):
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("expected no error once cancelled, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// printer writes results as tables or JSON.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+// print writes v as indented JSON, or calls table to write it as a table.
+func (p *printer) print(v interface{}, table func(w io.Writer)) error {
+	if p.json {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "%s\n", b)
+		return err
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+func (p *printer) channels(channels []channel) error {
+	return p.print(channels, func(w io.Writer) {
+		fmt.Fprintln(w, "CHANNEL\tMODULES\tQUEUE\tREPLY MODE\tRESTRICTION")
+		for _, ch := range channels {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", ch.Name, enabledCount(ch.Modules), ch.QueueLength, ch.ReplyMode, restriction(ch))
+		}
+	})
+}
+
+func (p *printer) modules(modules []module) error {
+	return p.print(modules, func(w io.Writer) {
+		fmt.Fprintln(w, "MODULE\tENABLED\tCOMMANDS")
+		for _, m := range modules {
+			fmt.Fprintf(w, "%s\t%t\t%d\n", m.Name, m.Enabled, len(m.Commands))
+		}
+	})
+}
+
+func (p *printer) commands(commands []command) error {
+	return p.print(commands, func(w io.Writer) {
+		fmt.Fprintln(w, "COMMAND\tENABLED\tCOOLDOWN\tLAST USED\tUSE")
+		for _, c := range commands {
+			lastUsed := "-"
+			if c.LastUsed != nil {
+				lastUsed = c.LastUsed.Local().Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%t\t%s\t%s\t%s\n", c.Name, c.Enabled, c.Cooldown, lastUsed, c.Use)
+		}
+	})
+}
+
+// status is the output of the status command.
+type status struct {
+	URL       string    `json:"url"`
+	Principal principal `json:"principal"`
+	Channels  []channel `json:"channels"`
+}
+
+func (p *printer) status(s status) error {
+	return p.print(s, func(w io.Writer) {
+		channels := "all"
+		if len(s.Principal.Channels) > 0 {
+			channels = strings.Join(s.Principal.Channels, ", ")
+		}
+		queued := 0
+		restricted := 0
+		for _, ch := range s.Channels {
+			queued += ch.QueueLength
+			if ch.Restriction != nil {
+				restricted++
+			}
+		}
+		fmt.Fprintf(w, "API:\t%s\n", s.URL)
+		fmt.Fprintf(w, "Token:\t%s\n", s.Principal.Name)
+		fmt.Fprintf(w, "Scopes:\t%s\n", strings.Join(s.Principal.Scopes, ", "))
+		fmt.Fprintf(w, "Access:\t%s\n", channels)
+		fmt.Fprintf(w, "Channels:\t%d joined, %d restricted\n", len(s.Channels), restricted)
+		fmt.Fprintf(w, "Queued messages:\t%d\n", queued)
+	})
+}
+
+// event writes a single event. JSON output writes one event per line
+// so that the output can be piped to other tools.
+func (p *printer) event(e event) error {
+	if p.json {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "%s\n", b)
+		return err
+	}
+	var data struct {
+		Channel string `json:"channel"`
+	}
+	json.Unmarshal(e.Data, &data)
+	if data.Channel == "" {
+		data.Channel = "-"
+	}
+	_, err := fmt.Fprintf(p.w, "%s  %-24s  %-16s  %s\n", time.Now().Format("15:04:05"), e.Type, data.Channel, e.Data)
+	return err
+}
+
+func enabledCount(modules []module) string {
+	enabled := 0
+	for _, m := range modules {
+		if m.Enabled {
+			enabled++
+		}
+	}
+	return fmt.Sprintf("%d/%d", enabled, len(modules))
+}
+
+func restriction(ch channel) string {
+	r := ch.Restriction
+	if r == nil {
+		return "-"
+	}
+	if r.Until.IsZero() {
+		return r.Reason
+	}
+	return fmt.Sprintf("%s until %s", r.Reason, r.Until.Local().Format(time.RFC3339))
+}
@@ -0,0 +1,534 @@
+package roastedbot
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	toml "github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is a problem with a configuration value.
+type ConfigError struct {
+	// File and Line locate the value, if it was read from a file.
+	File string
+	Line int
+	// Path is the JSON path of the value, such as "api.tokens[0].scopes".
+	Path    string
+	Message string
+}
+
+func (e ConfigError) Error() string {
+	s := e.Message
+	if e.Path != "" {
+		s = e.Path + ": " + s
+	}
+	switch {
+	case e.File != "" && e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, s)
+	case e.File != "":
+		return e.File + ": " + s
+	}
+	return s
+}
+
+// ConfigErrors are all of the problems found in a configuration.
+type ConfigErrors []ConfigError
+
+func (e ConfigErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LoadConfig reads the configuration file at path, applies the environment
+// variables found with lookup, and validates the result. The file's format is
+// chosen by its extension: ".json", ".yaml", ".yml" or ".toml". If path is
+// empty, the configuration comes from the environment variables alone.
+//
+// Problems with the configuration are returned as ConfigErrors, which
+// give the line of each problem that comes from the file.
+func LoadConfig(path string, lookup func(key string) (string, bool)) (*Config, error) {
+	c := &Config{}
+	var lines map[string]int
+	if path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if lines, err = c.parse(b, filepath.Ext(path)); err != nil {
+			return nil, withFile(err, path)
+		}
+	}
+	if err := c.ApplyEnv(lookup); err != nil {
+		return nil, err
+	}
+
+	err := c.Validate()
+	var problems ConfigErrors
+	if !errors.As(err, &problems) {
+		return c, err
+	}
+	for i, p := range problems {
+		problems[i].File = path
+		problems[i].Line = lineOf(lines, p.Path)
+	}
+	return c, problems
+}
+
+// ParseConfig parses a configuration in the format of the file extension,
+// rejecting unknown keys. It does not validate the values.
+func ParseConfig(b []byte, ext string) (*Config, error) {
+	c := &Config{}
+	if _, err := c.parse(b, ext); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// parse decodes the configuration, returning the line of each path that was set.
+func (c *Config) parse(b []byte, ext string) (map[string]int, error) {
+	var n *configNode
+	var err error
+	switch strings.ToLower(ext) {
+	case ".json":
+		n, err = parseJSON(b)
+	case ".yaml", ".yml":
+		n, err = parseYAML(b)
+	case ".toml":
+		n, err = parseTOML(b)
+	default:
+		return nil, fmt.Errorf("unknown configuration format '%s': expected .json, .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := &configDecoder{lines: make(map[string]int)}
+	if n != nil {
+		d.decode(reflect.ValueOf(c).Elem(), n, "")
+	}
+	if len(d.errs) > 0 {
+		return nil, d.errs
+	}
+	return d.lines, nil
+}
+
+func withFile(err error, path string) error {
+	var problems ConfigErrors
+	if errors.As(err, &problems) {
+		for i := range problems {
+			problems[i].File = path
+		}
+		return problems
+	}
+	return fmt.Errorf("%s: %w", path, err)
+}
+
+// lineOf finds the line of a path, or of the closest parent that has a line.
+func lineOf(lines map[string]int, path string) int {
+	for path != "" {
+		if line, ok := lines[path]; ok {
+			return line
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return 0
+}
+
+type nodeKind int
+
+const (
+	scalarNode nodeKind = iota
+	mappingNode
+	sequenceNode
+)
+
+// configNode is a value read from a configuration file, in any format.
+type configNode struct {
+	kind nodeKind
+	line int
+	// value is a scalar's string, bool, int64, float64 or nil.
+	value  interface{}
+	fields []configField
+	items  []*configNode
+}
+
+type configField struct {
+	key   string
+	line  int
+	value *configNode
+}
+
+// parseJSON reads JSON a token at a time to keep the line of each value.
+func parseJSON(b []byte) (*configNode, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	p := &jsonParser{b: b, d: d}
+	n, err := p.value()
+	if err == nil {
+		if _, extra := d.Token(); extra != io.EOF {
+			err = ConfigErrors{{Line: p.line(), Message: "unexpected data after the configuration"}}
+		}
+	}
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		return nil, ConfigErrors{{Line: lineAt(b, int(syntax.Offset)), Message: syntax.Error()}}
+	}
+	if err == io.EOF {
+		return nil, nil
+	}
+	return n, err
+}
+
+type jsonParser struct {
+	b []byte
+	d *json.Decoder
+}
+
+// line returns the line of the next token.
+func (p *jsonParser) line() int {
+	offset := int(p.d.InputOffset())
+	for offset < len(p.b) && strings.ContainsRune(" \t\r\n,:", rune(p.b[offset])) {
+		offset++
+	}
+	return lineAt(p.b, offset)
+}
+
+func (p *jsonParser) value() (*configNode, error) {
+	line := p.line()
+	tok, err := p.d.Token()
+	if err != nil {
+		return nil, err
+	}
+	n := &configNode{line: line}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '[' {
+			n.kind = sequenceNode
+			for p.d.More() {
+				item, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, item)
+			}
+		} else {
+			n.kind = mappingNode
+			for p.d.More() {
+				line := p.line()
+				key, err := p.d.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := p.value()
+				if err != nil {
+					return nil, err
+				}
+				n.fields = append(n.fields, configField{key: key.(string), line: line, value: value})
+			}
+		}
+		// Consume the closing delimiter.
+		if _, err := p.d.Token(); err != nil {
+			return nil, err
+		}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			n.value = i
+		} else {
+			n.value, _ = t.Float64()
+		}
+	default:
+		n.value = t
+	}
+	return n, nil
+}
+
+// The syntax errors of the YAML and TOML parsers start with their line.
+var (
+	yamlErrorPattern = regexp.MustCompile(`^line (\d+): (.*)$`)
+	tomlErrorPattern = regexp.MustCompile(`^\((\d+), \d+\): (.*)$`)
+)
+
+// syntaxError moves the line at the start of a parser's error to the ConfigError.
+func syntaxError(pattern *regexp.Regexp, message string) error {
+	m := pattern.FindStringSubmatch(message)
+	if m == nil {
+		return ConfigErrors{{Message: message}}
+	}
+	line, _ := strconv.Atoi(m[1])
+	return ConfigErrors{{Line: line, Message: m[2]}}
+}
+
+func lineAt(b []byte, offset int) int {
+	if offset > len(b) {
+		offset = len(b)
+	}
+	return bytes.Count(b[:offset], []byte("\n")) + 1
+}
+
+func parseYAML(b []byte) (*configNode, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, syntaxError(yamlErrorPattern, strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return fromYAML(doc.Content[0])
+}
+
+func fromYAML(y *yaml.Node) (*configNode, error) {
+	n := &configNode{line: y.Line}
+	switch y.Kind {
+	case yaml.AliasNode:
+		return fromYAML(y.Alias)
+	case yaml.MappingNode:
+		n.kind = mappingNode
+		for i := 0; i+1 < len(y.Content); i += 2 {
+			value, err := fromYAML(y.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			n.fields = append(n.fields, configField{key: y.Content[i].Value, line: y.Content[i].Line, value: value})
+		}
+	case yaml.SequenceNode:
+		n.kind = sequenceNode
+		for _, c := range y.Content {
+			item, err := fromYAML(c)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+	case yaml.ScalarNode:
+		var err error
+		switch y.ShortTag() {
+		case "!!null":
+		case "!!bool":
+			var v bool
+			err = y.Decode(&v)
+			n.value = v
+		case "!!int":
+			var v int64
+			err = y.Decode(&v)
+			n.value = v
+		case "!!float":
+			var v float64
+			err = y.Decode(&v)
+			n.value = v
+		default:
+			n.value = y.Value
+		}
+		if err != nil {
+			return nil, ConfigErrors{{Line: y.Line, Message: err.Error()}}
+		}
+	default:
+		return nil, ConfigErrors{{Line: y.Line, Message: "unsupported yaml value"}}
+	}
+	return n, nil
+}
+
+func parseTOML(b []byte) (*configNode, error) {
+	tree, err := toml.LoadBytes(b)
+	if err != nil {
+		return nil, syntaxError(tomlErrorPattern, err.Error())
+	}
+	return fromTOML(tree, tree.Position().Line)
+}
+
+func fromTOML(v interface{}, line int) (*configNode, error) {
+	n := &configNode{line: line}
+	switch v := v.(type) {
+	case *toml.Tree:
+		n.kind = mappingNode
+		for _, key := range v.Keys() {
+			keyLine := v.GetPositionPath([]string{key}).Line
+			value, err := fromTOML(v.GetPath([]string{key}), keyLine)
+			if err != nil {
+				return nil, err
+			}
+			n.fields = append(n.fields, configField{key: key, line: keyLine, value: value})
+		}
+		// Keys are not kept in order.
+		sort.SliceStable(n.fields, func(i, j int) bool { return n.fields[i].line < n.fields[j].line })
+	case []*toml.Tree:
+		n.kind = sequenceNode
+		for _, t := range v {
+			item, err := fromTOML(t, t.Position().Line)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+	case []interface{}:
+		n.kind = sequenceNode
+		for _, value := range v {
+			item, err := fromTOML(value, line)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, item)
+		}
+	case string, bool, int64, float64:
+		n.value = v
+	case time.Time:
+		return nil, ConfigErrors{{Line: line, Message: "dates are not supported, use a string"}}
+	default:
+		return nil, ConfigErrors{{Line: line, Message: fmt.Sprintf("unsupported toml value %v", v)}}
+	}
+	return n, nil
+}
+
+// configDecoder decodes configNodes into the fields with matching JSON names.
+type configDecoder struct {
+	errs  ConfigErrors
+	lines map[string]int
+}
+
+func (d *configDecoder) fail(n *configNode, path, format string, args ...interface{}) {
+	d.errs = append(d.errs, ConfigError{Line: n.line, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (d *configDecoder) decode(v reflect.Value, n *configNode, path string) {
+	if _, ok := d.lines[path]; !ok {
+		d.lines[path] = n.line
+	}
+	if n.kind == scalarNode && n.value == nil {
+		return
+	}
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		s, ok := n.value.(string)
+		if n.kind != scalarNode || !ok {
+			d.fail(n, path, "expected a string")
+			return
+		}
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			d.fail(n, path, "%v", err)
+		}
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		d.decode(v.Elem(), n, path)
+	case reflect.Struct:
+		if n.kind != mappingNode {
+			d.fail(n, path, "expected a table of keys")
+			return
+		}
+		fields := jsonFields(v.Type())
+		seen := make(map[string]bool)
+		for _, f := range n.fields {
+			p := join(path, f.key)
+			i, ok := fields[f.key]
+			switch {
+			case !ok:
+				d.fail(&configNode{line: f.line}, p, "unknown key")
+			case seen[f.key]:
+				d.fail(&configNode{line: f.line}, p, "duplicate key")
+			default:
+				seen[f.key] = true
+				d.lines[p] = f.line
+				d.decode(v.Field(i), f.value, p)
+			}
+		}
+	case reflect.Map:
+		if n.kind != mappingNode {
+			d.fail(n, path, "expected a table of keys")
+			return
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		for _, f := range n.fields {
+			p := join(path, f.key)
+			key := reflect.ValueOf(f.key).Convert(v.Type().Key())
+			if v.MapIndex(key).IsValid() {
+				d.fail(&configNode{line: f.line}, p, "duplicate key")
+				continue
+			}
+			d.lines[p] = f.line
+			elem := reflect.New(v.Type().Elem()).Elem()
+			d.decode(elem, f.value, p)
+			v.SetMapIndex(key, elem)
+		}
+	case reflect.Slice:
+		if n.kind != sequenceNode {
+			d.fail(n, path, "expected a list")
+			return
+		}
+		list := reflect.MakeSlice(v.Type(), len(n.items), len(n.items))
+		for i, item := range n.items {
+			d.decode(list.Index(i), item, fmt.Sprintf("%s[%d]", path, i))
+		}
+		v.Set(list)
+	case reflect.String:
+		s, ok := n.value.(string)
+		if n.kind != scalarNode || !ok {
+			d.fail(n, path, "expected a string")
+			return
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		b, ok := n.value.(bool)
+		if n.kind != scalarNode || !ok {
+			d.fail(n, path, "expected true or false")
+			return
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := n.value.(int64)
+		if n.kind != scalarNode || !ok || v.OverflowInt(i) {
+			d.fail(n, path, "expected a whole number")
+			return
+		}
+		v.SetInt(i)
+	default:
+		d.fail(n, path, "unsupported value")
+	}
+}
+
+// jsonFields returns the index of each field by its JSON name.
+func jsonFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if f.PkgPath != "" || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = i
+	}
+	return fields
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,219 @@
+package roastedbot
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+)
+
+const testHash = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+var testConfigs = map[string]string{
+	".json": `{
+  "username": "bot",
+  "oauth": "oauth:abc123",
+  "channels": ["foo", "bar"],
+  "settings": {
+    "foo": {"replyMode": "action"}
+  },
+  "http": {"readTimeout": "30s"},
+  "api": {
+    "tokens": [
+      {"name": "admin", "hash": "` + testHash + `", "scopes": ["global-admin"]}
+    ]
+  }
+}`,
+	".yaml": `username: bot
+oauth: oauth:abc123
+channels:
+  - foo
+  - bar
+settings:
+  foo:
+    replyMode: action
+http:
+  readTimeout: 30s
+api:
+  tokens:
+    - name: admin
+      hash: ` + testHash + `
+      scopes: [global-admin]
+`,
+	".toml": `username = "bot"
+oauth = "oauth:abc123"
+channels = ["foo", "bar"]
+
+[settings.foo]
+replyMode = "action"
+
+[http]
+readTimeout = "30s"
+
+[[api.tokens]]
+name = "admin"
+hash = "` + testHash + `"
+scopes = ["global-admin"]
+`,
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig_Formats(t *testing.T) {
+	expected := &Config{
+		Username: "bot",
+		OAuth:    "oauth:abc123",
+		Channels: []string{"foo", "bar"},
+		Settings: map[string]*ChannelSettings{"foo": {ReplyMode: "action"}},
+		HTTP:     HTTPConfig{ReadTimeout: Duration(30 * time.Second)},
+		API: APIConfig{Tokens: []auth.Token{
+			{Name: "admin", Hash: testHash, Scopes: []auth.Scope{auth.ScopeGlobalAdmin}},
+		}},
+	}
+	for ext, content := range testConfigs {
+		c, err := LoadConfig(writeConfig(t, "bot"+ext, content), lookupIn(nil))
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", ext, err)
+			continue
+		}
+		if !reflect.DeepEqual(c, expected) {
+			t.Errorf("%s: expected %+v, got %+v", ext, expected, c)
+		}
+	}
+}
+
+func TestLoadConfig_UnknownFormat(t *testing.T) {
+	if _, err := LoadConfig(writeConfig(t, "bot.ini", "username=bot"), lookupIn(nil)); err == nil {
+		t.Error("expected an error for an unknown format, got nil")
+	}
+}
+
+func TestLoadConfig_UnknownKeys(t *testing.T) {
+	tests := map[string]string{
+		".json": "{\n  \"username\": \"bot\",\n  \"http\": {\n    \"adress\": \":80\"\n  }\n}",
+		".yaml": "username: bot\nhttp:\n  readTimeout: 1s\n  adress: \":80\"\n",
+		".toml": "username = \"bot\"\n\n[http]\nadress = \":80\"\n",
+	}
+	for ext, content := range tests {
+		path := writeConfig(t, "bot"+ext, content)
+		_, err := LoadConfig(path, lookupIn(nil))
+		var problems ConfigErrors
+		if !errors.As(err, &problems) || len(problems) != 1 {
+			t.Errorf("%s: expected one ConfigError, got %v", ext, err)
+			continue
+		}
+		expected := ConfigError{File: path, Line: 4, Path: "http.adress", Message: "unknown key"}
+		if problems[0] != expected {
+			t.Errorf("%s: expected %v, got %v", ext, expected, problems[0])
+		}
+	}
+}
+
+func TestLoadConfig_TypeErrors(t *testing.T) {
+	path := writeConfig(t, "bot.yaml", "username: bot\nchannels: foo\nhttp:\n  readTimeout: soon\n")
+	_, err := LoadConfig(path, lookupIn(nil))
+	var problems ConfigErrors
+	if !errors.As(err, &problems) || len(problems) != 2 {
+		t.Fatalf("expected two ConfigErrors, got %v", err)
+	}
+	if problems[0].Line != 2 || problems[0].Path != "channels" {
+		t.Errorf("expected an error for channels on line 2, got %v", problems[0])
+	}
+	if problems[1].Line != 4 || problems[1].Path != "http.readTimeout" || !strings.Contains(problems[1].Message, "invalid duration") {
+		t.Errorf("expected an invalid duration on line 4, got %v", problems[1])
+	}
+}
+
+func TestLoadConfig_SyntaxErrors(t *testing.T) {
+	tests := map[string]string{
+		".json": "{\n  \"username\": \"bot\"\n  \"oauth\": \"oauth:abc\"\n}",
+		".yaml": "username: bot\noauth: abc\n  channels: foo\n",
+		".toml": "username = \"bot\"\nchannels = [\"foo\"\n",
+	}
+	for ext, content := range tests {
+		_, err := LoadConfig(writeConfig(t, "bot"+ext, content), lookupIn(nil))
+		var problems ConfigErrors
+		if !errors.As(err, &problems) || len(problems) != 1 {
+			t.Errorf("%s: expected one ConfigError, got %v", ext, err)
+			continue
+		}
+		if problems[0].Line < 2 {
+			t.Errorf("%s: expected the error's line, got %v", ext, problems[0])
+		}
+	}
+}
+
+func TestLoadConfig_Validation(t *testing.T) {
+	path := writeConfig(t, "bot.yaml", `username: bot
+oauth: oauth:abc123
+channels:
+  - Foo
+settings:
+  bar:
+    restrictedMode: hold
+    modules:
+      chat:
+        enabled: false
+api:
+  tokens:
+    - name: admin
+      hash: `+testHash+`
+      scopes: [admin]
+`)
+	_, err := LoadConfig(path, lookupIn(nil))
+	var problems ConfigErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("expected ConfigErrors, got %v", err)
+	}
+	expected := map[string]int{
+		"channels[0]":                 4,
+		"settings.bar.restrictedMode": 7,
+		"settings.bar.modules.chat":   9,
+		"api.tokens[0].scopes[0]":     15,
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), problems)
+	}
+	for _, p := range problems {
+		if line, ok := expected[p.Path]; !ok || p.Line != line {
+			t.Errorf("unexpected error %v", p)
+		}
+		if p.File != path {
+			t.Errorf("expected file %s, got %s", path, p.File)
+		}
+	}
+}
+
+func TestLoadConfig_Env(t *testing.T) {
+	c, err := LoadConfig("", lookupIn(map[string]string{
+		"ROASTEDBOT_USERNAME": "bot",
+		"ROASTEDBOT_OAUTH":    "oauth:abc123",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Username != "bot" {
+		t.Errorf("expected username bot, got %s", c.Username)
+	}
+
+	_, err = LoadConfig("", lookupIn(nil))
+	var problems ConfigErrors
+	if !errors.As(err, &problems) || len(problems) != 2 {
+		t.Fatalf("expected errors for username and oauth, got %v", err)
+	}
+	if problems[0].Error() != "username: is not set" {
+		t.Errorf("expected 'username: is not set', got '%s'", problems[0].Error())
+	}
+}
@@ -0,0 +1,27 @@
+package roastedbot
+
+import (
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration that is configured as a string such as "30s" or "1m30s".
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(b []byte) error {
+	parsed, err := time.ParseDuration(string(b))
+	if err != nil {
+		return fmt.Errorf("invalid duration '%s': expected a value such as \"30s\" or \"1m30s\"", b)
+	}
+	if parsed < 0 {
+		return fmt.Errorf("invalid duration '%s': must not be negative", b)
+	}
+	*d = Duration(parsed)
+	return nil
+}
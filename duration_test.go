@@ -0,0 +1,25 @@
+package roastedbot
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDuration_UnmarshalJSON(t *testing.T) {
+	var v struct {
+		Timeout Duration `json:"timeout"`
+	}
+	if err := json.Unmarshal([]byte(`{"timeout":"1m30s"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(v.Timeout) != time.Second*90 {
+		t.Errorf("expected 1m30s, got %s", time.Duration(v.Timeout))
+	}
+
+	for _, s := range []string{`{"timeout":"soon"}`, `{"timeout":"-1s"}`, `{"timeout":30}`} {
+		if err := json.Unmarshal([]byte(s), &v); err == nil {
+			t.Errorf("expected error for %s", s)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package roastedbot
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EnvPrefix is the prefix of the environment variables that configure the bot.
+const EnvPrefix = "ROASTEDBOT"
+
+// ApplyEnv overrides the configuration with environment variables, which are
+// looked up with lookup, such as os.LookupEnv. Every field has a variable named
+// after its JSON path, such as ROASTEDBOT_OAUTH or ROASTEDBOT_HTTP_ADDRESS.
+// Lists of strings are separated by commas, and other lists and maps are JSON.
+//
+// A variable with the _FILE suffix, such as ROASTEDBOT_OAUTH_FILE, reads the
+// value from a file instead, which is how Docker and Kubernetes provide secrets.
+func (c *Config) ApplyEnv(lookup func(key string) (string, bool)) error {
+	_, err := applyEnv(reflect.ValueOf(c).Elem(), EnvPrefix, lookup)
+	return err
+}
+
+// applyEnv sets v and its fields from the variable called name and
+// the variables that it prefixes. It reports whether any were set.
+func applyEnv(v reflect.Value, name string, lookup func(string) (string, bool)) (bool, error) {
+	if _, ok := v.Addr().Interface().(encoding.TextUnmarshaler); !ok {
+		switch {
+		case v.Kind() == reflect.Struct:
+			applied := false
+			for i := 0; i < v.NumField(); i++ {
+				f := v.Type().Field(i)
+				tag := strings.Split(f.Tag.Get("json"), ",")[0]
+				if f.PkgPath != "" || tag == "" || tag == "-" {
+					continue
+				}
+				ok, err := applyEnv(v.Field(i), name+"_"+envName(tag), lookup)
+				if err != nil {
+					return false, err
+				}
+				applied = applied || ok
+			}
+			return applied, nil
+		case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Struct:
+			// The struct is only created if one of its fields is set.
+			elem := reflect.New(v.Type().Elem())
+			if !v.IsNil() {
+				elem.Elem().Set(v.Elem())
+			}
+			ok, err := applyEnv(elem.Elem(), name, lookup)
+			if ok {
+				v.Set(elem)
+			}
+			return ok, err
+		}
+	}
+
+	value, ok, err := lookupEnv(name, lookup)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := setValue(v, value); err != nil {
+		return false, fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return true, nil
+}
+
+// lookupEnv looks up a variable, or reads it from the file named by the variable with the _FILE suffix.
+func lookupEnv(name string, lookup func(string) (string, bool)) (string, bool, error) {
+	value, ok := lookup(name)
+	path, fromFile := lookup(name + "_FILE")
+	switch {
+	case ok && fromFile:
+		return "", false, fmt.Errorf("only one of %s and %s_FILE can be set", name, name)
+	case fromFile:
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("unable to read %s_FILE: %w", name, err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), true, nil
+	}
+	return value, ok, nil
+}
+
+func setValue(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("expected true or false")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("expected a whole number")
+		}
+		v.SetInt(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return json.Unmarshal([]byte(s), v.Addr().Interface())
+		}
+		list := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = reflect.Append(list, reflect.ValueOf(item).Convert(v.Type().Elem()))
+			}
+		}
+		v.Set(list)
+	default:
+		return json.Unmarshal([]byte(s), v.Addr().Interface())
+	}
+	return nil
+}
+
+// envName converts a JSON field name such as "autoPartBanned" to AUTO_PART_BANNED.
+func envName(field string) string {
+	var b strings.Builder
+	runes := []rune(field)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(runes[i-1]) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package roastedbot
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+)
+
+func lookupIn(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "oauth")
+	if err := ioutil.WriteFile(secret, []byte("oauth:fromfile\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := &Config{Username: "bot", Channels: []string{"old"}, HTTP: HTTPConfig{Address: ":9001"}}
+	err := c.ApplyEnv(lookupIn(map[string]string{
+		"ROASTEDBOT_OAUTH_FILE":              secret,
+		"ROASTEDBOT_CHANNELS":                "foo, bar",
+		"ROASTEDBOT_AUTO_PART_BANNED":        "true",
+		"ROASTEDBOT_HTTP_READ_TIMEOUT":       "30s",
+		"ROASTEDBOT_CHAT_LOG_RETENTION_DAYS": "7",
+		"ROASTEDBOT_API_BASE_URL":            "https://bot.example.com",
+		"ROASTEDBOT_API_LOGIN_CLIENT_ID":     "client",
+		"ROASTEDBOT_API_TOKENS":              `[{"name":"admin","hash":"abc","scopes":["global-admin"]}]`,
+		"ROASTEDBOT_SETTINGS":                `{"foo":{"replyMode":"action"}}`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Username != "bot" || c.HTTP.Address != ":9001" {
+		t.Errorf("expected fields without variables to be kept, got %+v", c)
+	}
+	if c.OAuth != "oauth:fromfile" {
+		t.Errorf("expected the oauth to be read from the file, got %q", string(c.OAuth))
+	}
+	if !reflect.DeepEqual(c.Channels, []string{"foo", "bar"}) {
+		t.Errorf("expected channels [foo bar], got %v", c.Channels)
+	}
+	if !c.AutoPartBanned || c.HTTP.ReadTimeout != Duration(30*time.Second) || c.ChatLog.RetentionDays != 7 {
+		t.Errorf("unexpected values %+v", c)
+	}
+	if c.API.BaseURL != "https://bot.example.com" || c.API.Login == nil || c.API.Login.ClientID != "client" {
+		t.Errorf("unexpected api config %+v", c.API)
+	}
+	if len(c.API.Tokens) != 1 || c.API.Tokens[0].Scopes[0] != auth.ScopeGlobalAdmin {
+		t.Errorf("unexpected tokens %+v", c.API.Tokens)
+	}
+	if s := c.Settings["foo"]; s == nil || s.ReplyMode != "action" {
+		t.Errorf("unexpected settings %+v", c.Settings)
+	}
+	if c.HTTP.TLS != nil {
+		t.Error("expected structs without variables to stay nil")
+	}
+}
+
+func TestApplyEnv_Invalid(t *testing.T) {
+	for _, env := range []map[string]string{
+		{"ROASTEDBOT_OAUTH": "oauth:a", "ROASTEDBOT_OAUTH_FILE": "oauth"},
+		{"ROASTEDBOT_OAUTH_FILE": filepath.Join(t.TempDir(), "missing")},
+		{"ROASTEDBOT_AUTO_PART_BANNED": "sometimes"},
+		{"ROASTEDBOT_HTTP_IDLE_TIMEOUT": "forever"},
+		{"ROASTEDBOT_API_TOKENS": "admin"},
+	} {
+		if err := (&Config{}).ApplyEnv(lookupIn(env)); err == nil {
+			t.Errorf("expected an error for %v", env)
+		}
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	for field, expected := range map[string]string{
+		"oauth":          "OAUTH",
+		"autoPartBanned": "AUTO_PART_BANNED",
+		"baseUrl":        "BASE_URL",
+		"grpc":           "GRPC",
+	} {
+		if name := envName(field); name != expected {
+			t.Errorf("expected %s, got %s", expected, name)
+		}
+	}
+}
@@ -1,12 +1,33 @@
 module github.com/brattonross/roastedbot
 
+go 1.21
+
 require (
 	github.com/gempir/go-twitch-irc v0.0.0-20181021181504-9689c9ed6f07
 	github.com/golang/protobuf v1.2.0
 	github.com/gorilla/mux v1.6.2
-	github.com/pkg/errors v0.8.0
+	github.com/pelletier/go-toml v1.9.5
+	github.com/prometheus/client_golang v0.9.2
 	github.com/sirupsen/logrus v1.1.1
-	golang.org/x/net v0.0.0-20181029044818-c44066c5c816
-	google.golang.org/genproto v0.0.0-20181029155118-b69ba1387ce2 // indirect
+	golang.org/x/net v0.0.0-20181201002055-351d144fa1fc
 	google.golang.org/grpc v1.16.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+// The 2018 go-twitch-irc cannot send tagged messages, so a copy with upstream's
+// Client.Reply backported is used until the bot moves to a newer major version.
+replace github.com/gempir/go-twitch-irc => ./third_party/go-twitch-irc
+
+require (
+	github.com/beorn7/perks v0.0.0-20180321164747-3a771d992973 // indirect
+	github.com/gorilla/context v1.1.2 // indirect
+	github.com/konsorten/go-windows-terminal-sequences v0.0.0-20180402223658-b729f2633dfe // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
+	github.com/prometheus/client_model v0.0.0-20180712105110-5c3871d89910 // indirect
+	github.com/prometheus/common v0.0.0-20181126121408-4724e9255275 // indirect
+	github.com/prometheus/procfs v0.0.0-20181204211112-1dc9a6cbc91a // indirect
+	golang.org/x/crypto v0.0.0-20180904163835-0709b304e793 // indirect
+	golang.org/x/sys v0.0.0-20180905080454-ebe1bf3edb33 // indirect
+	golang.org/x/text v0.3.0 // indirect
+	google.golang.org/genproto v0.0.0-20181029155118-b69ba1387ce2 // indirect
 )
@@ -0,0 +1,131 @@
+package roastedbot
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LogConfig configures the bot's logger. Zero values log
+// at the info level as text to standard error.
+type LogConfig struct {
+	// Level is the lowest level that is logged, such as "debug" or "warn".
+	Level string `json:"level"`
+	// Format is "text" or "json".
+	Format string `json:"format"`
+	// Output is "stdout", "stderr" or the path of a file to append to.
+	Output string `json:"output"`
+}
+
+// NewLogger creates the logger that is used throughout the bot. Chat tokens
+// and the given secrets are redacted from every message and field.
+func NewLogger(c LogConfig, secrets ...string) (*log.Logger, error) {
+	logger := log.New()
+	logger.AddHook(newRedactHook(secrets))
+
+	if c.Level != "" {
+		level, err := log.ParseLevel(c.Level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level '%s'", c.Level)
+		}
+		logger.SetLevel(level)
+	}
+
+	switch strings.ToLower(c.Format) {
+	case "", "text":
+	case "json":
+		logger.Formatter = &log.JSONFormatter{}
+	default:
+		return nil, fmt.Errorf("invalid log format '%s': expected \"text\" or \"json\"", c.Format)
+	}
+
+	var out io.Writer
+	switch c.Output {
+	case "", "stderr":
+		out = os.Stderr
+	case "stdout":
+		out = os.Stdout
+	default:
+		f, err := os.OpenFile(c.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open log output: %w", err)
+		}
+		out = f
+	}
+	logger.Out = out
+	return logger, nil
+}
+
+// RedactSecrets adds secrets to those that a logger created by NewLogger
+// redacts, such as tokens that are refreshed while the bot runs.
+func RedactSecrets(logger *log.Logger, secrets ...string) {
+	for _, hook := range logger.Hooks[log.PanicLevel] {
+		if h, ok := hook.(*redactHook); ok {
+			h.add(secrets)
+		}
+	}
+}
+
+// redactHook replaces secrets in the messages and fields of log entries.
+type redactHook struct {
+	mutex   *sync.Mutex
+	secrets []string
+}
+
+func newRedactHook(secrets []string) *redactHook {
+	h := &redactHook{mutex: &sync.Mutex{}}
+	h.add(secrets)
+	return h
+}
+
+func (h *redactHook) add(secrets []string) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for _, s := range secrets {
+		if s != "" {
+			h.secrets = append(h.secrets, s)
+		}
+	}
+}
+
+func (h *redactHook) redact(s string) string {
+	h.mutex.Lock()
+	secrets := h.secrets
+	h.mutex.Unlock()
+	for _, secret := range secrets {
+		s = strings.Replace(s, secret, redacted, -1)
+	}
+	return oauthPattern.ReplaceAllString(s, redacted)
+}
+
+// Levels implements log.Hook.
+func (h *redactHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire implements log.Hook.
+func (h *redactHook) Fire(e *log.Entry) error {
+	e.Message = h.redact(e.Message)
+	// The fields can be shared with other entries, so they are copied rather than changed.
+	data := make(log.Fields, len(e.Data))
+	for k, v := range e.Data {
+		switch v := v.(type) {
+		case string:
+			data[k] = h.redact(v)
+		case error:
+			if s := h.redact(v.Error()); s != v.Error() {
+				data[k] = s
+			} else {
+				data[k] = v
+			}
+		default:
+			data[k] = v
+		}
+	}
+	e.Data = data
+	return nil
+}
@@ -0,0 +1,95 @@
+package roastedbot
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestNewLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	logger, err := NewLogger(LogConfig{Level: "warn", Format: "json", Output: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logger.Level != log.WarnLevel {
+		t.Errorf("expected level warn, got %s", logger.Level)
+	}
+
+	logger.Info("ignored")
+	logger.WithField("channel", "test").Warn("logged")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var line map[string]string
+	if err := json.Unmarshal(b, &line); err != nil {
+		t.Fatalf("expected a single JSON line, got %q", b)
+	}
+	if line["msg"] != "logged" || line["channel"] != "test" {
+		t.Errorf("unexpected log line %v", line)
+	}
+}
+
+func TestNewLogger_Invalid(t *testing.T) {
+	for _, c := range []LogConfig{
+		{Level: "loud"},
+		{Format: "xml"},
+		{Output: filepath.Join(t.TempDir(), "missing", "bot.log")},
+	} {
+		if _, err := NewLogger(c); err == nil {
+			t.Errorf("expected an error for %+v", c)
+		}
+	}
+}
+
+func TestNewLogger_Redact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	logger, err := NewLogger(LogConfig{Format: "json", Output: path}, "clientsecret", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := logger.WithField("token", "oauth:abc123")
+	entry.WithField("error", errors.New("bad secret clientsecret")).Error("sent PASS oauth:abc123")
+	if entry.Data["token"] != "oauth:abc123" {
+		t.Error("expected the entry's own fields to be left alone")
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var line map[string]string
+	if err := json.Unmarshal(b, &line); err != nil {
+		t.Fatal(err)
+	}
+	if line["msg"] != "sent PASS [redacted]" || line["token"] != "[redacted]" || line["error"] != "bad secret [redacted]" {
+		t.Errorf("expected secrets to be redacted, got %v", line)
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	logger, err := NewLogger(LogConfig{Format: "json", Output: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	RedactSecrets(logger, "refreshed", "")
+	logger.WithField("token", "refreshed").Info("validated refreshed")
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var line map[string]string
+	if err := json.Unmarshal(b, &line); err != nil {
+		t.Fatalf("expected a single JSON line, got %q", b)
+	}
+	if line["msg"] != "validated "+redacted || line["token"] != redacted {
+		t.Errorf("expected the added secret to be redacted, got %v", line)
+	}
+}
@@ -0,0 +1,39 @@
+package admin
+
+import (
+	"strings"
+
+	tirc "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// Commands are all of the commands in the admin module.
+var Commands = []*twitch.Command{
+	EnableCommand,
+	DisableCommand,
+	JoinCommand,
+	PartCommand,
+	StatusCommand,
+	AuditCommand,
+	SearchCommand,
+	ReloadCommand,
+}
+
+// isAdmin determines if the user is allowed to use admin commands.
+func isAdmin(user tirc.User) bool {
+	return strings.ToLower(user.Username) == "roastedb"
+}
+
+// record adds an action taken by an admin command to the audit log.
+func record(cl *twitch.Client, user tirc.User, message tirc.Message, e audit.Entry) {
+	e.Actor = strings.ToLower(user.Username)
+	e.Source = audit.SourceChat
+	if message.Type == tirc.WHISPER {
+		e.Source = audit.SourceWhisper
+	}
+	if err := cl.Audit.Record(e); err != nil {
+		cl.Logger(message).WithField("error", err).Error("failed to record audit entry")
+	}
+}
@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tirc "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+const (
+	// defaultAuditEntries is the number of entries shown when no number is given.
+	defaultAuditEntries = 5
+	// maxAuditEntries is the most entries that fit in a single chat message.
+	maxAuditEntries  = 10
+	maxMessageLength = 500
+)
+
+// AuditCommand shows the most recent entries of the audit log.
+var AuditCommand = &twitch.Command{
+	Cooldown:  time.Second * 5,
+	Name:      "audit",
+	Permitted: isAdmin,
+	Respond:   twitch.RespondBoth,
+	Run:       executeAudit,
+	Use:       "audit",
+}
+
+func executeAudit(cl *twitch.Client, args []string, channel string, user tirc.User, message tirc.Message) {
+	invalidSyntax := fmt.Sprintf("Invalid command syntax. Usage: audit last [1-%d] [#channel]", maxAuditEntries)
+	q := audit.Query{Limit: defaultAuditEntries}
+	args = args[1:]
+	if len(args) > 0 && strings.ToLower(args[0]) == "last" {
+		args = args[1:]
+		if len(args) > 0 && !strings.HasPrefix(args[0], "#") {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 || n > maxAuditEntries {
+				cl.Reply(channel, message, invalidSyntax)
+				return
+			}
+			q.Limit = n
+			args = args[1:]
+		}
+	}
+	if len(args) > 0 && strings.HasPrefix(args[0], "#") {
+		q.Channel = strings.ToLower(args[0][1:])
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		cl.Reply(channel, message, invalidSyntax)
+		return
+	}
+
+	entries := cl.Audit.Query(q)
+	if len(entries) == 0 {
+		cl.Reply(channel, message, "No actions have been recorded")
+		return
+	}
+	now := time.Now()
+	lines := []string{}
+	for _, e := range entries {
+		lines = append(lines, formatEntry(e, now))
+	}
+	resp := strings.Join(lines, " | ")
+	if len(resp) > maxMessageLength {
+		resp = resp[:maxMessageLength-3] + "..."
+	}
+	cl.Reply(channel, message, resp)
+}
+
+// formatEntry describes an audit entry in a single line.
+func formatEntry(e audit.Entry, now time.Time) string {
+	s := fmt.Sprintf("%s ago %s (%s) %s #%s", now.Sub(e.Time).Truncate(time.Second), e.Actor, e.Source, e.Action, e.Channel)
+	if e.Target != "" {
+		s += " " + e.Target
+	}
+	if e.Before != "" || e.After != "" {
+		s += fmt.Sprintf(": %s -> %s", orNone(e.Before), orNone(e.After))
+	}
+	return s
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -5,35 +5,45 @@ import (
 	"strings"
 	"time"
 
-	"github.com/brattonross/roastedbot/pkg/twitch"
 	tirc "github.com/gempir/go-twitch-irc"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/twitch"
 )
 
 // EnableCommand allows modules and commands to be enabled.
 var EnableCommand = &twitch.Command{
-	Cooldown: time.Second * 1,
-	Name:     "enable",
-	Run:      executeEnable,
-	Use:      "enable",
+	Cooldown:  time.Second * 1,
+	Name:      "enable",
+	Permitted: isAdmin,
+	Respond:   twitch.RespondBoth,
+	Run:       executeEnable,
+	Use:       "enable",
 }
 
-func executeEnable(cl *twitch.Client, args []string, channel string, user tirc.User, message tirc.Message) {
-	if strings.ToLower(user.Username) != "roastedb" {
-		return
-	}
+// DisableCommand allows modules and commands to be disabled.
+var DisableCommand = &twitch.Command{
+	Cooldown:  time.Second * 1,
+	Name:      "disable",
+	Permitted: isAdmin,
+	Respond:   twitch.RespondBoth,
+	Run:       executeEnable,
+	Use:       "disable",
+}
 
+func executeEnable(cl *twitch.Client, args []string, channel string, user tirc.User, message tirc.Message) {
 	invalidSyntax := "Invalid command syntax. Usage: enable|disable -m module_name [-c command_name]"
 
 	if len(args) < 3 {
-		cl.Say(channel, invalidSyntax)
+		cl.Reply(channel, message, invalidSyntax)
 		return
 	}
 
 	enable := false
 	first := strings.ToLower(args[0])
 	if first != "enable" && first != "disable" {
-		cl.Say(channel, invalidSyntax)
+		cl.Reply(channel, message, invalidSyntax)
 		return
 	}
 	if first == "enable" {
@@ -54,7 +64,7 @@ func executeEnable(cl *twitch.Client, args []string, channel string, user tirc.U
 	}
 
 	if module == "" {
-		cl.Say(channel, invalidSyntax)
+		cl.Reply(channel, message, invalidSyntax)
 		return
 	}
 	if strings.ToLower(module) == "default" {
@@ -63,44 +73,73 @@ func executeEnable(cl *twitch.Client, args []string, channel string, user tirc.U
 
 	// No command specified - enable/disable module.
 	if command == "" {
+		before := moduleState(cl, channel, module)
 		if enable {
 			if err := cl.EnableModule(channel, module); err != nil {
-				log.WithField("module", module).Error(err)
-				cl.Say(channel, fmt.Sprintf("Module '%s' does not exist", module))
+				cl.Logger(message).WithField("module", module).Error(err)
+				cl.Reply(channel, message, fmt.Sprintf("Module '%s' does not exist", module))
 				return
 			}
-			cl.Say(channel, fmt.Sprintf("Enabled module '%s'", module))
+			record(cl, user, message, audit.Entry{Action: audit.ActionModuleEnable, Channel: channel, Target: module, Before: before, After: audit.StateEnabled})
+			cl.Reply(channel, message, fmt.Sprintf("Enabled module '%s'", module))
 			return
 		}
 		if err := cl.DisableModule(channel, module); err != nil {
-			log.WithField("module", module).Error(err)
-			cl.Say(channel, fmt.Sprintf("Module '%s' does not exist", module))
+			cl.Logger(message).WithField("module", module).Error(err)
+			cl.Reply(channel, message, fmt.Sprintf("Module '%s' does not exist", module))
 			return
 		}
-		cl.Say(channel, fmt.Sprintf("Disabled module '%s'", module))
+		record(cl, user, message, audit.Entry{Action: audit.ActionModuleDisable, Channel: channel, Target: module, Before: before, After: audit.StateDisabled})
+		cl.Reply(channel, message, fmt.Sprintf("Disabled module '%s'", module))
 		return
 	}
 
 	// Command specified - enable/disable command.
+	before := commandState(cl, channel, module, command)
+	target := module + "/" + strings.ToLower(command)
 	if enable {
 		if err := cl.EnableCommand(channel, module, command); err != nil {
-			log.WithFields(log.Fields{
-				module:  module,
-				command: command,
+			cl.Logger(message).WithFields(log.Fields{
+				"module":  module,
+				"command": command,
 			}).Error(err)
-			cl.Say(channel, fmt.Sprintf("Command '%s' does not exist in module '%s'", command, module))
+			cl.Reply(channel, message, fmt.Sprintf("Command '%s' does not exist in module '%s'", command, module))
 			return
 		}
-		cl.Say(channel, fmt.Sprintf("Enabled command '%s' in module '%s'", command, module))
+		record(cl, user, message, audit.Entry{Action: audit.ActionCommandEnable, Channel: channel, Target: target, Before: before, After: audit.StateEnabled})
+		cl.Reply(channel, message, fmt.Sprintf("Enabled command '%s' in module '%s'", command, module))
 		return
 	}
 	if err := cl.DisableCommand(channel, module, command); err != nil {
-		log.WithFields(log.Fields{
-			module:  module,
-			command: command,
+		cl.Logger(message).WithFields(log.Fields{
+			"module":  module,
+			"command": command,
 		}).Error(err)
-		cl.Say(channel, fmt.Sprintf("Command '%s' does not exist in module '%s'", command, module))
+		cl.Reply(channel, message, fmt.Sprintf("Command '%s' does not exist in module '%s'", command, module))
 		return
 	}
-	cl.Say(channel, fmt.Sprintf("Disabled command '%s' in module '%s'", command, module))
+	record(cl, user, message, audit.Entry{Action: audit.ActionCommandDisable, Channel: channel, Target: target, Before: before, After: audit.StateDisabled})
+	cl.Reply(channel, message, fmt.Sprintf("Disabled command '%s' in module '%s'", command, module))
+}
+
+// moduleState describes whether a module is enabled, for the audit log.
+func moduleState(cl *twitch.Client, channel, module string) string {
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return ""
+	}
+	return audit.EnabledState(ch.IsModuleEnabled(module))
+}
+
+// commandState describes whether a command is enabled, for the audit log.
+func commandState(cl *twitch.Client, channel, module, command string) string {
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return ""
+	}
+	m, err := ch.Module(module)
+	if err != nil {
+		return ""
+	}
+	return audit.EnabledState(m.IsCommandEnabled(command))
 }
@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tirc "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// JoinCommand makes the bot join a channel.
+var JoinCommand = &twitch.Command{
+	Cooldown:  time.Second * 1,
+	Name:      "join",
+	Permitted: isAdmin,
+	Respond:   twitch.RespondBoth,
+	Run:       executeJoin,
+	Use:       "join",
+}
+
+// PartCommand makes the bot leave a channel.
+var PartCommand = &twitch.Command{
+	Cooldown:  time.Second * 1,
+	Name:      "part",
+	Permitted: isAdmin,
+	Respond:   twitch.RespondBoth,
+	Run:       executePart,
+	Use:       "part",
+}
+
+func executeJoin(cl *twitch.Client, args []string, channel string, user tirc.User, message tirc.Message) {
+	if len(args) < 2 {
+		cl.Reply(channel, message, "Invalid command syntax. Usage: join channel_name")
+		return
+	}
+
+	target, err := twitch.ChannelName(args[1])
+	if err != nil {
+		cl.Reply(channel, message, fmt.Sprintf("Invalid channel name '%s'", args[1]))
+		return
+	}
+	if err := cl.JoinChannel(target); err != nil {
+		cl.Logger(message).WithField("channel", target).Error(err)
+		cl.Reply(channel, message, fmt.Sprintf("Already in channel '%s'", target))
+		return
+	}
+	record(cl, user, message, audit.Entry{Action: audit.ActionChannelJoin, Channel: target, Before: "parted", After: "joined"})
+	cl.Reply(channel, message, fmt.Sprintf("Joined channel '%s'", target))
+}
+
+func executePart(cl *twitch.Client, args []string, channel string, user tirc.User, message tirc.Message) {
+	if len(args) < 2 {
+		cl.Reply(channel, message, "Invalid command syntax. Usage: part channel_name")
+		return
+	}
+
+	target := strings.ToLower(strings.TrimPrefix(args[1], "#"))
+	if err := cl.PartChannel(target); err != nil {
+		cl.Logger(message).WithField("channel", target).Error(err)
+		cl.Reply(channel, message, fmt.Sprintf("Not in channel '%s'", target))
+		return
+	}
+	record(cl, user, message, audit.Entry{Action: audit.ActionChannelPart, Channel: target, Before: "joined", After: "parted"})
+	// There is no one left to respond to when leaving the channel that the command was used in.
+	if target != channel || message.Type == tirc.WHISPER {
+		cl.Reply(channel, message, fmt.Sprintf("Left channel '%s'", target))
+	}
+}
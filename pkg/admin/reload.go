@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tirc "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// maxReloadReply is the longest list of changes that is sent in reply, which
+// keeps the reply within twitch's message length.
+const maxReloadReply = 400
+
+// ReloadCommand reloads the bot's configuration without reconnecting.
+var ReloadCommand = &twitch.Command{
+	Cooldown:  time.Second * 5,
+	Name:      "reload",
+	Permitted: isAdmin,
+	Respond:   twitch.RespondBoth,
+	Run:       executeReload,
+	Use:       "reload",
+}
+
+func executeReload(cl *twitch.Client, args []string, channel string, user tirc.User, message tirc.Message) {
+	if cl.Reload == nil {
+		cl.Reply(channel, message, "Reloading the configuration is not available")
+		return
+	}
+
+	changes, err := cl.Reload()
+	if err != nil {
+		cl.Logger(message).WithField("error", err).Error("failed to reload configuration")
+		if changes == nil {
+			cl.Reply(channel, message, "Failed to reload configuration: "+strings.SplitN(err.Error(), "\n", 2)[0])
+			return
+		}
+	}
+	record(cl, user, message, audit.Entry{Action: audit.ActionConfigReload, Channel: channel, After: fmt.Sprintf("%d changes", len(changes))})
+	cl.Reply(channel, message, reloadReply(changes, err))
+}
+
+// reloadReply summarises the changes made by a reload.
+func reloadReply(changes []string, err error) string {
+	if len(changes) == 0 && err == nil {
+		return "Reloaded configuration, nothing changed"
+	}
+	resp := fmt.Sprintf("Reloaded configuration with %d %s", len(changes), plural(len(changes), "change", "changes"))
+	if err != nil {
+		resp += " and some that failed, see the log"
+	}
+	list := strings.Join(changes, ", ")
+	if len(list) > maxReloadReply {
+		list = list[:maxReloadReply] + "..."
+	}
+	if list != "" {
+		resp += ": " + list
+	}
+	return resp
+}
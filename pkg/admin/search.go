@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tirc "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/chatlog"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// SearchCommand counts the archived messages that match a search and shows the latest.
+var SearchCommand = &twitch.Command{
+	Cooldown:  time.Second * 5,
+	Name:      "search",
+	Permitted: isAdmin,
+	Respond:   twitch.RespondBoth,
+	Run:       executeSearch,
+	Use:       "search",
+}
+
+const searchUsage = "Invalid command syntax. Usage: search [#channel] [@user] [since:7d] [words]"
+
+func executeSearch(cl *twitch.Client, args []string, channel string, user tirc.User, message tirc.Message) {
+	if cl.ChatLog == nil {
+		cl.Reply(channel, message, "Chat logs are not being archived")
+		return
+	}
+
+	q, ok := parseSearch(args[1:], time.Now())
+	if !ok {
+		cl.Reply(channel, message, searchUsage)
+		return
+	}
+	if q.Channel == "" && message.Type != tirc.WHISPER {
+		q.Channel = channel
+	}
+	q.Limit = 1
+
+	res, err := cl.ChatLog.Search(q)
+	if err != nil {
+		cl.Reply(channel, message, "Chat logs are not being searched")
+		return
+	}
+	if res.Count == 0 {
+		cl.Reply(channel, message, "No messages matched")
+		return
+	}
+	m := res.Matches[0]
+	resp := fmt.Sprintf("%d %s, latest %s ago in #%s %s: %s", res.Count, plural(res.Count, "match", "matches"),
+		time.Since(m.Time).Truncate(time.Second), m.Channel, m.Username, m.Text)
+	if len(resp) > maxMessageLength {
+		resp = resp[:maxMessageLength-3] + "..."
+	}
+	cl.Reply(channel, message, resp)
+}
+
+// parseSearch reads the arguments of the search command into a query.
+func parseSearch(args []string, now time.Time) (chatlog.Query, bool) {
+	q := chatlog.Query{}
+	var words []string
+	for _, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, "#") && q.Channel == "" && len(words) == 0:
+			q.Channel = strings.ToLower(arg[1:])
+		case strings.HasPrefix(arg, "@") && q.Username == "" && len(words) == 0:
+			q.Username = strings.ToLower(arg[1:])
+		case strings.HasPrefix(arg, "since:") && q.From.IsZero() && len(words) == 0:
+			d, err := parseSince(strings.TrimPrefix(arg, "since:"))
+			if err != nil {
+				return q, false
+			}
+			q.From = now.Add(-d)
+		default:
+			words = append(words, arg)
+		}
+	}
+	q.Text = strings.Join(words, " ")
+	return q, q.Channel != "" || q.Username != "" || q.Text != "" || !q.From.IsZero()
+}
+
+// parseSince parses a duration, which can also be a number of days such as "7d".
+func parseSince(s string) (time.Duration, error) {
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid number of days '%s'", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration '%s'", s)
+	}
+	return d, nil
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
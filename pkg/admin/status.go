@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tirc "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// StatusCommand prints a summary of the bot's state.
+var StatusCommand = &twitch.Command{
+	Cooldown:  time.Second * 5,
+	Name:      "status",
+	Permitted: isAdmin,
+	Respond:   twitch.RespondBoth,
+	Run:       executeStatus,
+	Use:       "status",
+}
+
+func executeStatus(cl *twitch.Client, args []string, channel string, user tirc.User, message tirc.Message) {
+	names := []string{}
+	queued := 0
+	restricted := []string{}
+	for _, ch := range cl.Channels() {
+		names = append(names, ch.Name)
+		queued += ch.QueueLength()
+		if r := ch.Restriction(); r.Reason != "" {
+			restricted = append(restricted, fmt.Sprintf("%s (%s)", ch.Name, r.Reason))
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(restricted)
+
+	resp := fmt.Sprintf(
+		"In %d channels: %s | Queued messages: %d | Uptime: %s",
+		len(names),
+		strings.Join(names, ", "),
+		queued,
+		cl.Uptime().Truncate(time.Second),
+	)
+	if len(restricted) > 0 {
+		resp += " | Restricted in: " + strings.Join(restricted, ", ")
+	}
+	cl.Reply(channel, message, resp)
+}
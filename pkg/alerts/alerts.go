@@ -0,0 +1,238 @@
+package alerts
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// ModuleName is the name of the module that controls the alerts in a channel.
+const ModuleName = "events"
+
+const anonymousGifter = "An anonymous gifter"
+
+var (
+	// giftWindow is how long gifted subs from the same gifter
+	// are collected before they are announced together.
+	giftWindow = time.Second * 2
+	// mysteryGiftTimeout is how long the individual gifts that follow
+	// a mystery gift are waited for before they are announced normally.
+	mysteryGiftTimeout = time.Minute
+)
+
+// Alerts sends thank-you messages for subscriptions,
+// gifted subscriptions, raids and cheers.
+type Alerts struct {
+	// afterFunc and say are replaced by tests.
+	afterFunc    func(d time.Duration, f func())
+	batches      map[string]*giftBatch
+	batchesMutex *sync.Mutex
+	client       *twitch.Client
+	defaults     templates
+	log          *log.Logger
+	say          func(channel, text string)
+	// settings and templates are replaced by SetSettings.
+	settings      map[string]*Settings
+	settingsMutex *sync.RWMutex
+	templates     map[string]templates
+}
+
+// giftBatch collects the gifted subs from a single gifter in a channel.
+type giftBatch struct {
+	// expected is the number of gifts still to come from a mystery gift.
+	expected int
+	gifts    []event.GiftSubscription
+}
+
+// New creates Alerts for the client using the given per-channel settings.
+// Channels with invalid settings fall back to the default messages.
+func New(client *twitch.Client, settings map[string]*Settings, logger *log.Logger) *Alerts {
+	defaults, _ := compile(nil)
+	a := &Alerts{
+		afterFunc:     func(d time.Duration, f func()) { time.AfterFunc(d, f) },
+		batches:       make(map[string]*giftBatch),
+		batchesMutex:  &sync.Mutex{},
+		client:        client,
+		defaults:      defaults,
+		log:           logger,
+		say:           client.Say,
+		settingsMutex: &sync.RWMutex{},
+	}
+	a.SetSettings(settings)
+	return a
+}
+
+// SetSettings replaces the per-channel settings.
+// Channels with invalid settings fall back to the default messages.
+func (a *Alerts) SetSettings(settings map[string]*Settings) {
+	t := make(map[string]templates)
+	for channel, s := range settings {
+		compiled, err := compile(s)
+		if err != nil {
+			a.log.WithField("channel", channel).Errorf("using default event messages: %v", err)
+			compiled = a.defaults
+		}
+		t[channel] = compiled
+	}
+	a.settingsMutex.Lock()
+	defer a.settingsMutex.Unlock()
+	a.settings = settings
+	a.templates = t
+}
+
+// Subscribe starts listening for events on the client's event bus.
+// The returned function stops the alerts.
+func (a *Alerts) Subscribe() (unsubscribe func()) {
+	return a.client.Events.Subscribe(
+		a.handle,
+		event.TypeSubscription,
+		event.TypeGiftSubscription,
+		event.TypeMysteryGift,
+		event.TypeRaid,
+		event.TypeCheer,
+	)
+}
+
+func (a *Alerts) handle(e event.Event) {
+	channel := e.ChannelName()
+	ch, err := a.client.Channel(channel)
+	if err != nil || !ch.IsModuleEnabled(ModuleName) {
+		return
+	}
+
+	switch e := e.(type) {
+	case event.Subscription:
+		if e.Resub {
+			a.announce(channel, Resubscription, e)
+		} else {
+			a.announce(channel, Subscription, e)
+		}
+	case event.GiftSubscription:
+		a.addGift(e)
+	case event.MysteryGift:
+		a.expectGifts(e)
+		if e.Anonymous {
+			e.GifterDisplayName = anonymousGifter
+		}
+		a.announce(channel, MysteryGift, e)
+	case event.Raid:
+		if e.Viewers < a.channelSettings(channel).MinimumViewers {
+			return
+		}
+		a.announce(channel, Raid, e)
+	case event.Cheer:
+		if e.Bits < a.channelSettings(channel).MinimumBits {
+			return
+		}
+		a.announce(channel, Cheer, e)
+	}
+}
+
+// addGift adds a gifted sub to the gifter's batch. Gifts that belong to
+// a mystery gift are dropped, as the mystery gift has already been announced.
+func (a *Alerts) addGift(e event.GiftSubscription) {
+	key := batchKey(e.Channel, e.Gifter)
+
+	a.batchesMutex.Lock()
+	defer a.batchesMutex.Unlock()
+	b, ok := a.batches[key]
+	if ok && b.expected > 0 {
+		b.expected--
+		if b.expected == 0 {
+			delete(a.batches, key)
+		}
+		return
+	}
+	if !ok {
+		b = &giftBatch{}
+		a.batches[key] = b
+		a.afterFunc(giftWindow, func() { a.flushGifts(key, b) })
+	}
+	b.gifts = append(b.gifts, e)
+}
+
+// expectGifts records that the individual gifts of a mystery gift are about to arrive.
+func (a *Alerts) expectGifts(e event.MysteryGift) {
+	key := batchKey(e.Channel, e.Gifter)
+	b := &giftBatch{expected: e.Count}
+
+	a.batchesMutex.Lock()
+	a.batches[key] = b
+	a.batchesMutex.Unlock()
+
+	a.afterFunc(mysteryGiftTimeout, func() {
+		a.batchesMutex.Lock()
+		defer a.batchesMutex.Unlock()
+		if a.batches[key] == b {
+			delete(a.batches, key)
+		}
+	})
+}
+
+func (a *Alerts) flushGifts(key string, b *giftBatch) {
+	a.batchesMutex.Lock()
+	if a.batches[key] == b {
+		delete(a.batches, key)
+	}
+	gifts := b.gifts
+	a.batchesMutex.Unlock()
+
+	if len(gifts) == 0 {
+		return
+	}
+	first := gifts[0]
+	if first.Anonymous {
+		first.GifterDisplayName = anonymousGifter
+	}
+	if len(gifts) == 1 {
+		a.announce(first.Channel, GiftSubscription, first)
+		return
+	}
+	a.announce(first.Channel, MysteryGift, event.MysteryGift{
+		Channel:           first.Channel,
+		Gifter:            first.Gifter,
+		GifterDisplayName: first.GifterDisplayName,
+		Count:             len(gifts),
+		Plan:              first.Plan,
+		Anonymous:         first.Anonymous,
+		Time:              first.Time,
+	})
+}
+
+func (a *Alerts) announce(channel, kind string, data interface{}) {
+	a.settingsMutex.RLock()
+	t, ok := a.templates[channel]
+	a.settingsMutex.RUnlock()
+	if !ok {
+		t = a.defaults
+	}
+	text, err := t.render(kind, data)
+	if err != nil {
+		a.log.WithFields(log.Fields{
+			"channel": channel,
+			"kind":    kind,
+		}).Errorf("failed to render event message: %v", err)
+		return
+	}
+	if text == "" {
+		return
+	}
+	a.say(channel, text)
+}
+
+func (a *Alerts) channelSettings(channel string) Settings {
+	a.settingsMutex.RLock()
+	defer a.settingsMutex.RUnlock()
+	if s, ok := a.settings[channel]; ok && s != nil {
+		return *s
+	}
+	return Settings{}
+}
+
+func batchKey(channel, gifter string) string {
+	return channel + "#" + gifter
+}
@@ -0,0 +1,142 @@
+package alerts
+
+import (
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	tirc "github.com/gempir/go-twitch-irc"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// fakeClock runs the timers of Alerts when it is advanced.
+type fakeClock struct {
+	mutex  *sync.Mutex
+	now    time.Duration
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Duration
+	f  func()
+}
+
+func (c *fakeClock) afterFunc(d time.Duration, f func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.timers = append(c.timers, fakeTimer{at: c.now + d, f: f})
+}
+
+// advance moves the clock forward, running the timers that are due in order.
+func (c *fakeClock) advance(d time.Duration) {
+	c.mutex.Lock()
+	c.now += d
+	sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].at < c.timers[j].at })
+	var due []fakeTimer
+	for len(c.timers) > 0 && c.timers[0].at <= c.now {
+		due = append(due, c.timers[0])
+		c.timers = c.timers[1:]
+	}
+	c.mutex.Unlock()
+	for _, t := range due {
+		t.f()
+	}
+}
+
+func newTestAlerts(t *testing.T) (*Alerts, *fakeClock, *[]string) {
+	t.Helper()
+	client := twitch.NewClient("bot", tirc.NewClient("bot", "oauth:token"))
+	if err := client.AddChannel("channel"); err != nil {
+		t.Fatal(err)
+	}
+	client.AddModule("channel", ModuleName)
+	client.EnableModule("channel", ModuleName)
+
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	a := New(client, nil, logger)
+	clock := &fakeClock{mutex: &sync.Mutex{}}
+	a.afterFunc = clock.afterFunc
+	said := &[]string{}
+	a.say = func(channel, text string) { *said = append(*said, text) }
+	return a, clock, said
+}
+
+func gift(gifter, recipient string) event.GiftSubscription {
+	return event.GiftSubscription{
+		Channel:              "channel",
+		Gifter:               gifter,
+		GifterDisplayName:    gifter,
+		Recipient:            recipient,
+		RecipientDisplayName: recipient,
+	}
+}
+
+func TestAlerts_MysteryGift(t *testing.T) {
+	a, clock, said := newTestAlerts(t)
+
+	a.handle(event.MysteryGift{Channel: "channel", Gifter: "gifter", GifterDisplayName: "Gifter", Count: 3})
+	for _, recipient := range []string{"one", "two", "three"} {
+		a.handle(gift("gifter", recipient))
+	}
+	clock.advance(mysteryGiftTimeout)
+
+	expected := []string{"Thank you for gifting 3 subs, Gifter! <3"}
+	if !reflect.DeepEqual(*said, expected) {
+		t.Errorf("expected only the mystery gift to be announced, got %q", *said)
+	}
+
+	// Gifts after the mystery gift's gifts have arrived are announced normally.
+	a.handle(gift("gifter", "four"))
+	clock.advance(giftWindow)
+	if len(*said) != 2 || (*said)[1] != "Thank you for gifting a sub to four, gifter! <3" {
+		t.Errorf("expected the later gift to be announced, got %q", *said)
+	}
+}
+
+func TestAlerts_StrayGifts(t *testing.T) {
+	a, clock, said := newTestAlerts(t)
+
+	a.handle(gift("someone", "one"))
+	a.handle(gift("someone", "two"))
+	a.handle(gift("other", "three"))
+	clock.advance(giftWindow - time.Millisecond)
+	if len(*said) != 0 {
+		t.Fatalf("expected gifts to be collected until the window ends, got %q", *said)
+	}
+
+	clock.advance(time.Millisecond)
+	sort.Strings(*said)
+	expected := []string{
+		"Thank you for gifting 2 subs, someone! <3",
+		"Thank you for gifting a sub to three, other! <3",
+	}
+	if !reflect.DeepEqual(*said, expected) {
+		t.Errorf("expected each gifter's gifts to be announced together, got %q", *said)
+	}
+}
+
+func TestAlerts_MysteryGiftTimeout(t *testing.T) {
+	a, clock, said := newTestAlerts(t)
+
+	// Only one of the three gifts arrives before the mystery gift times out.
+	a.handle(event.MysteryGift{Channel: "channel", Gifter: "gifter", GifterDisplayName: "Gifter", Count: 3})
+	a.handle(gift("gifter", "one"))
+	clock.advance(mysteryGiftTimeout)
+
+	a.handle(gift("gifter", "late"))
+	clock.advance(giftWindow)
+	expected := []string{
+		"Thank you for gifting 3 subs, Gifter! <3",
+		"Thank you for gifting a sub to late, gifter! <3",
+	}
+	if !reflect.DeepEqual(*said, expected) {
+		t.Errorf("expected gifts after the timeout to be announced, got %q", *said)
+	}
+}
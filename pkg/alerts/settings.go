@@ -0,0 +1,91 @@
+package alerts
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// Kinds of messages that the events module can send.
+const (
+	Subscription     = "subscription"
+	Resubscription   = "resubscription"
+	GiftSubscription = "giftSubscription"
+	MysteryGift      = "mysteryGift"
+	Raid             = "raid"
+	Cheer            = "cheer"
+)
+
+// DefaultMessages are the templates used for any kind
+// of message that a channel has not configured.
+var DefaultMessages = map[string]string{
+	Subscription:     "Thank you for subscribing, {{.DisplayName}}! <3",
+	Resubscription:   "Thank you for subscribing for {{.Months}} months, {{.DisplayName}}! <3",
+	GiftSubscription: "Thank you for gifting a sub to {{.RecipientDisplayName}}, {{.GifterDisplayName}}! <3",
+	MysteryGift:      "Thank you for gifting {{.Count}} subs, {{.GifterDisplayName}}! <3",
+	Raid:             "Welcome raiders from {{.DisplayName}}'s channel! Thank you for the raid of {{.Viewers}} <3",
+	Cheer:            "Thank you for the {{.Bits}} bits, {{.DisplayName}}! <3",
+}
+
+// Settings configures the events module for a channel.
+type Settings struct {
+	// Messages are text/template templates keyed by kind of message.
+	// Kinds that are missing use the default message,
+	// and kinds with an empty template are not announced.
+	Messages map[string]string `json:"messages"`
+	// MinimumBits is the smallest cheer that is announced.
+	MinimumBits int `json:"minimumBits"`
+	// MinimumViewers is the smallest raid that is announced.
+	MinimumViewers int `json:"minimumViewers"`
+}
+
+// Validate checks that every message is a known kind with a valid template.
+func (s *Settings) Validate() error {
+	for kind := range s.Messages {
+		if _, ok := DefaultMessages[kind]; !ok {
+			return fmt.Errorf("unknown message kind '%s'", kind)
+		}
+	}
+	if s.MinimumBits < 0 || s.MinimumViewers < 0 {
+		return fmt.Errorf("minimums cannot be negative")
+	}
+	_, err := compile(s)
+	return err
+}
+
+// templates is a set of compiled message templates keyed by kind.
+type templates map[string]*template.Template
+
+func compile(s *Settings) (templates, error) {
+	t := templates{}
+	for kind, text := range DefaultMessages {
+		if s != nil {
+			if custom, ok := s.Messages[kind]; ok {
+				text = custom
+			}
+		}
+		if text == "" {
+			continue
+		}
+		tmpl, err := template.New(kind).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s message: %v", kind, err)
+		}
+		t[kind] = tmpl
+	}
+	return t, nil
+}
+
+// render executes the template of the given kind.
+// It returns an empty string if the kind is not announced.
+func (t templates) render(kind string, data interface{}) (string, error) {
+	tmpl, ok := t[kind]
+	if !ok {
+		return "", nil
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
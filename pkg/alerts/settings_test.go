@@ -0,0 +1,79 @@
+package alerts
+
+import (
+	"testing"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+)
+
+func TestCompile_Defaults(t *testing.T) {
+	tmpl, err := compile(nil)
+	if err != nil {
+		t.Fatalf("compile returned unexpected error: %v", err)
+	}
+	for kind := range DefaultMessages {
+		if _, ok := tmpl[kind]; !ok {
+			t.Errorf("expected a default template for %s", kind)
+		}
+	}
+}
+
+func TestCompile_Custom(t *testing.T) {
+	tmpl, err := compile(&Settings{
+		Messages: map[string]string{
+			Cheer: "{{.DisplayName}} cheered {{.Bits}}",
+			Raid:  "",
+		},
+	})
+	if err != nil {
+		t.Fatalf("compile returned unexpected error: %v", err)
+	}
+
+	text, err := tmpl.render(Cheer, event.Cheer{DisplayName: "Someone", Bits: 50})
+	if err != nil {
+		t.Fatalf("render returned unexpected error: %v", err)
+	}
+	if text != "Someone cheered 50" {
+		t.Errorf("unexpected cheer message: %s", text)
+	}
+
+	text, err = tmpl.render(Raid, event.Raid{DisplayName: "Someone", Viewers: 10})
+	if err != nil {
+		t.Fatalf("render returned unexpected error: %v", err)
+	}
+	if text != "" {
+		t.Errorf("expected disabled raid message to be empty, got %s", text)
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	_, err := compile(&Settings{
+		Messages: map[string]string{Subscription: "{{.DisplayName"},
+	})
+	if err == nil {
+		t.Error("expected compile to return an error for an invalid template")
+	}
+}
+
+func TestSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		valid    bool
+	}{
+		{"empty", Settings{}, true},
+		{"custom", Settings{Messages: map[string]string{Cheer: "{{.Bits}} bits"}}, true},
+		{"unknown kind", Settings{Messages: map[string]string{"follow": "hi"}}, false},
+		{"invalid template", Settings{Messages: map[string]string{Cheer: "{{.Bits"}}, false},
+		{"negative minimum", Settings{MinimumBits: -1}, false},
+	}
+	for _, tt := range tests {
+		err := tt.settings.Validate()
+		if tt.valid && err != nil {
+			t.Errorf("%s: expected no error, got %v", tt.name, err)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected an error, got nil", tt.name)
+		}
+	}
+}
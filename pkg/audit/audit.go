@@ -0,0 +1,194 @@
+// Package audit records the administrative actions taken on the bot.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source is how an action was requested.
+type Source string
+
+// Sources of actions.
+const (
+	SourceChat    Source = "chat"
+	SourceWhisper Source = "whisper"
+	SourceHTTP    Source = "http"
+	SourceGRPC    Source = "grpc"
+	// SourceConfig is a change made by reloading the configuration.
+	SourceConfig Source = "config"
+)
+
+// Actions that are recorded.
+const (
+	ActionModuleEnable        = "module.enable"
+	ActionModuleDisable       = "module.disable"
+	ActionCommandEnable       = "command.enable"
+	ActionCommandDisable      = "command.disable"
+	ActionCommandCooldown     = "command.cooldown"
+	ActionChannelJoin         = "channel.join"
+	ActionChannelPart         = "channel.part"
+	ActionCustomCommandSet    = "custom_command.set"
+	ActionCustomCommandRemove = "custom_command.remove"
+	ActionConfigReload        = "config.reload"
+)
+
+// States recorded as the Before and After of enabling and disabling modules and commands.
+const (
+	StateEnabled  = "enabled"
+	StateDisabled = "disabled"
+)
+
+// EnabledState returns the state recorded for a module or command being enabled or not.
+func EnabledState(enabled bool) string {
+	if enabled {
+		return StateEnabled
+	}
+	return StateDisabled
+}
+
+// Entry is a single recorded action.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// Actor is the user or API token that took the action.
+	Actor  string `json:"actor"`
+	Source Source `json:"source"`
+	Action string `json:"action"`
+	// Channel is the channel that the action was taken in.
+	Channel string `json:"channel"`
+	// Target is what the action changed within the channel, such as a module,
+	// a command in the form "module/command" or a custom command.
+	Target string `json:"target,omitempty"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Query filters the entries returned by Log.Query. Empty fields match every entry.
+type Query struct {
+	Actor   string
+	Channel string
+	Source  Source
+	// Channels limits the entries to these channels, unless it is empty.
+	Channels []string
+	// Limit is the greatest number of entries returned. Zero returns every entry.
+	Limit int
+}
+
+func (q Query) match(e Entry) bool {
+	if q.Actor != "" && !strings.EqualFold(q.Actor, e.Actor) {
+		return false
+	}
+	if q.Channel != "" && !strings.EqualFold(q.Channel, e.Channel) {
+		return false
+	}
+	if q.Source != "" && q.Source != e.Source {
+		return false
+	}
+	if len(q.Channels) == 0 {
+		return true
+	}
+	for _, c := range q.Channels {
+		if strings.EqualFold(c, e.Channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Log is an audit log. Entries are kept in memory, and appended
+// to a file as JSON lines if the Log was opened with a path.
+type Log struct {
+	entries []Entry
+	file    *os.File
+	mutex   *sync.Mutex
+}
+
+// New creates a Log that is kept in memory only.
+func New() *Log {
+	return &Log{mutex: &sync.Mutex{}}
+}
+
+// Open opens the Log stored at path, creating it if it does not exist.
+func Open(path string) (*Log, error) {
+	l := New()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open audit log: %w", err)
+	}
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(s.Bytes(), &e); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("audit log %s line %d: %v", path, line, err)
+		}
+		l.entries = append(l.entries, e)
+	}
+	if err := s.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to read audit log: %w", err)
+	}
+	l.file = f
+	return l, nil
+}
+
+// Record adds an entry to the Log, setting its time if it is zero.
+// Recording to a nil Log is a no-op.
+func (l *Log) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.file != nil {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := l.file.Write(append(b, '\n')); err != nil {
+			return fmt.Errorf("unable to write audit log: %w", err)
+		}
+	}
+	l.entries = append(l.entries, e)
+	return nil
+}
+
+// Query returns the entries that match the query, newest first.
+func (l *Log) Query(q Query) []Entry {
+	entries := []Entry{}
+	if l == nil {
+		return entries
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(entries) == q.Limit {
+			break
+		}
+		if q.match(l.entries[i]) {
+			entries = append(entries, l.entries[i])
+		}
+	}
+	return entries
+}
+
+// Close closes the Log's file.
+func (l *Log) Close() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.file.Close()
+}
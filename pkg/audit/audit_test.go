@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	l := New()
+	l.Record(Entry{Actor: "a", Source: SourceChat, Action: ActionModuleEnable, Channel: "one"})
+	l.Record(Entry{Actor: "b", Source: SourceHTTP, Action: ActionModuleDisable, Channel: "two"})
+	l.Record(Entry{Actor: "a", Source: SourceGRPC, Action: ActionChannelPart, Channel: "two"})
+
+	entries := l.Query(Query{})
+	if len(entries) != 3 || entries[0].Action != ActionChannelPart || entries[0].Time.IsZero() {
+		t.Fatalf("expected all entries newest first with a time, got %+v", entries)
+	}
+	if entries := l.Query(Query{Limit: 1}); len(entries) != 1 || entries[0].Action != ActionChannelPart {
+		t.Errorf("expected only the newest entry, got %+v", entries)
+	}
+	if entries := l.Query(Query{Actor: "A"}); len(entries) != 2 {
+		t.Errorf("expected 2 entries by actor a, got %+v", entries)
+	}
+	if entries := l.Query(Query{Channel: "two", Source: SourceHTTP}); len(entries) != 1 || entries[0].Actor != "b" {
+		t.Errorf("expected the entry from http in channel two, got %+v", entries)
+	}
+	if entries := l.Query(Query{Channels: []string{"one"}}); len(entries) != 1 || entries[0].Channel != "one" {
+		t.Errorf("expected entries to be limited to channel one, got %+v", entries)
+	}
+
+	var nilLog *Log
+	if err := nilLog.Record(Entry{}); err != nil || len(nilLog.Query(Query{})) != 0 {
+		t.Error("expected a nil Log to record nothing")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Record(Entry{Actor: "a", Source: SourceWhisper, Action: ActionCommandCooldown, Channel: "one", Target: "general/ping", Before: "0s", After: "30s"})
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.Record(Entry{Actor: "b", Action: ActionChannelJoin, Channel: "two"})
+	entries := l.Query(Query{})
+	if len(entries) != 2 {
+		t.Fatalf("expected the entry to be reloaded, got %+v", entries)
+	}
+	if e := entries[1]; e.Target != "general/ping" || e.Before != "0s" || e.After != "30s" || e.Source != SourceWhisper {
+		t.Errorf("unexpected reloaded entry %+v", e)
+	}
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Scope is a level of access to the management API.
+type Scope string
+
+// Scopes are ordered, so each scope also grants the scopes before it.
+const (
+	// ScopeRead allows reading the state of channels.
+	ScopeRead Scope = "read"
+	// ScopeChannelAdmin allows changing the modules and commands of channels.
+	ScopeChannelAdmin Scope = "channel-admin"
+	// ScopeGlobalAdmin allows joining and parting channels.
+	ScopeGlobalAdmin Scope = "global-admin"
+)
+
+var scopeLevels = map[Scope]int{
+	ScopeRead:         1,
+	ScopeChannelAdmin: 2,
+	ScopeGlobalAdmin:  3,
+}
+
+// ValidScope determines if the scope is one of the known scopes.
+func ValidScope(scope Scope) bool {
+	_, ok := scopeLevels[scope]
+	return ok
+}
+
+// Token configures an API token. Only the SHA-256 hash of the token is stored.
+type Token struct {
+	// Name identifies the token in logs.
+	Name string `json:"name"`
+	// Hash is the hex encoded SHA-256 hash of the token, as returned by HashToken.
+	Hash   string  `json:"hash"`
+	Scopes []Scope `json:"scopes"`
+	// Channels restricts the token to the given channels.
+	// A token without channels can access every channel.
+	Channels []string `json:"channels"`
+}
+
+// Principal is an authenticated user of the API.
+type Principal struct {
+	Name     string
+	Scopes   []Scope
+	Channels []string
+}
+
+// Has determines if the principal has been granted the scope.
+func (p *Principal) Has(scope Scope) bool {
+	for _, s := range p.Scopes {
+		if scopeLevels[s] >= scopeLevels[scope] {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccess determines if the principal is allowed to access the channel.
+func (p *Principal) CanAccess(channel string) bool {
+	if len(p.Channels) == 0 {
+		return true
+	}
+	for _, c := range p.Channels {
+		if strings.EqualFold(c, channel) {
+			return true
+		}
+	}
+	return false
+}
+
+// Can determines if the principal has been granted the scope in the channel.
+func (p *Principal) Can(scope Scope, channel string) bool {
+	return p.Has(scope) && p.CanAccess(channel)
+}
+
+// HashToken returns the hex encoded SHA-256 hash of a token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Tokens authenticates requests using API tokens.
+type Tokens struct {
+	tokens []tokenHash
+}
+
+type tokenHash struct {
+	hash      []byte
+	principal *Principal
+}
+
+// NewTokens validates the configured tokens.
+func NewTokens(tokens []Token) (*Tokens, error) {
+	t := &Tokens{}
+	for i, token := range tokens {
+		if token.Name == "" {
+			return nil, fmt.Errorf("token %d must have a name", i)
+		}
+		hash, err := hex.DecodeString(token.Hash)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("token '%s' must have a hex encoded SHA-256 hash", token.Name)
+		}
+		if len(token.Scopes) == 0 {
+			return nil, fmt.Errorf("token '%s' must have at least one scope", token.Name)
+		}
+		for _, s := range token.Scopes {
+			if !ValidScope(s) {
+				return nil, fmt.Errorf("token '%s' has unknown scope '%s'", token.Name, s)
+			}
+		}
+		t.tokens = append(t.tokens, tokenHash{
+			hash: hash,
+			principal: &Principal{
+				Name:     token.Name,
+				Scopes:   token.Scopes,
+				Channels: token.Channels,
+			},
+		})
+	}
+	return t, nil
+}
+
+// Authenticate returns the principal that owns the token, if any.
+func (t *Tokens) Authenticate(token string) (*Principal, bool) {
+	if t == nil || token == "" {
+		return nil, false
+	}
+	sum := sha256.Sum256([]byte(token))
+	for _, th := range t.tokens {
+		if subtle.ConstantTimeCompare(sum[:], th.hash) == 1 {
+			return th.principal, true
+		}
+	}
+	return nil, false
+}
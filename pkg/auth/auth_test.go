@@ -0,0 +1,57 @@
+package auth
+
+import "testing"
+
+func TestPrincipal_Can(t *testing.T) {
+	p := &Principal{Scopes: []Scope{ScopeChannelAdmin}, Channels: []string{"Test"}}
+
+	tests := []struct {
+		scope   Scope
+		channel string
+		want    bool
+	}{
+		{ScopeRead, "test", true},
+		{ScopeChannelAdmin, "test", true},
+		{ScopeGlobalAdmin, "test", false},
+		{ScopeRead, "other", false},
+	}
+	for _, tt := range tests {
+		if got := p.Can(tt.scope, tt.channel); got != tt.want {
+			t.Errorf("Can(%s, %s): expected %v, got %v", tt.scope, tt.channel, tt.want, got)
+		}
+	}
+}
+
+func TestTokens_Authenticate(t *testing.T) {
+	tokens, err := NewTokens([]Token{
+		{Name: "dashboard", Hash: HashToken("secret"), Scopes: []Scope{ScopeRead}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, ok := tokens.Authenticate("secret")
+	if !ok || p.Name != "dashboard" {
+		t.Errorf("expected token to authenticate as 'dashboard', got %v", p)
+	}
+	if _, ok := tokens.Authenticate("wrong"); ok {
+		t.Error("expected wrong token to fail authentication")
+	}
+	if _, ok := tokens.Authenticate(""); ok {
+		t.Error("expected empty token to fail authentication")
+	}
+}
+
+func TestNewTokens_Invalid(t *testing.T) {
+	tests := []Token{
+		{Hash: HashToken("secret"), Scopes: []Scope{ScopeRead}},
+		{Name: "plain", Hash: "secret", Scopes: []Scope{ScopeRead}},
+		{Name: "none", Hash: HashToken("secret")},
+		{Name: "unknown", Hash: HashToken("secret"), Scopes: []Scope{"root"}},
+	}
+	for _, token := range tests {
+		if _, err := NewTokens([]Token{token}); err == nil {
+			t.Errorf("expected error for token %+v", token)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Twitch's OAuth2 endpoints.
+const (
+	TwitchAuthURL  = "https://id.twitch.tv/oauth2/authorize"
+	TwitchTokenURL = "https://id.twitch.tv/oauth2/token"
+	TwitchUsersURL = "https://api.twitch.tv/helix/users"
+)
+
+// LoginConfig configures logging in with a twitch account.
+// The URLs default to twitch's endpoints but can point at any
+// server that implements the same authorization code flow.
+type LoginConfig struct {
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+	// RedirectURL is the URL of the callback endpoint, as registered with twitch.
+	RedirectURL string `json:"redirectUrl"`
+	// AfterLoginURL is where users are sent after logging in. Defaults to "/".
+	AfterLoginURL string `json:"afterLoginUrl"`
+	AuthURL       string `json:"authUrl"`
+	TokenURL      string `json:"tokenUrl"`
+	UsersURL      string `json:"usersUrl"`
+}
+
+// User is a twitch account.
+type User struct {
+	ID          string `json:"id"`
+	Login       string `json:"login"`
+	DisplayName string `json:"display_name"`
+}
+
+// OAuth logs users in with their twitch account using the authorization code flow.
+type OAuth struct {
+	Config LoginConfig
+	client *http.Client
+}
+
+// NewOAuth validates the login configuration.
+func NewOAuth(config LoginConfig) (*OAuth, error) {
+	if config.ClientID == "" || config.ClientSecret == "" {
+		return nil, fmt.Errorf("login requires a client id and client secret")
+	}
+	if config.RedirectURL == "" {
+		return nil, fmt.Errorf("login requires a redirect url")
+	}
+	if config.AfterLoginURL == "" {
+		config.AfterLoginURL = "/"
+	}
+	if config.AuthURL == "" {
+		config.AuthURL = TwitchAuthURL
+	}
+	if config.TokenURL == "" {
+		config.TokenURL = TwitchTokenURL
+	}
+	if config.UsersURL == "" {
+		config.UsersURL = TwitchUsersURL
+	}
+	return &OAuth{
+		Config: config,
+		client: &http.Client{Timeout: time.Second * 10},
+	}, nil
+}
+
+// AuthCodeURL returns the URL that users are sent to in order to log in.
+func (o *OAuth) AuthCodeURL(state string) string {
+	v := url.Values{}
+	v.Set("client_id", o.Config.ClientID)
+	v.Set("redirect_uri", o.Config.RedirectURL)
+	v.Set("response_type", "code")
+	v.Set("scope", "")
+	v.Set("state", state)
+	sep := "?"
+	if strings.Contains(o.Config.AuthURL, "?") {
+		sep = "&"
+	}
+	return o.Config.AuthURL + sep + v.Encode()
+}
+
+// Exchange exchanges an authorization code for an access token.
+func (o *OAuth) Exchange(ctx context.Context, code string) (string, error) {
+	v := url.Values{}
+	v.Set("client_id", o.Config.ClientID)
+	v.Set("client_secret", o.Config.ClientSecret)
+	v.Set("code", code)
+	v.Set("grant_type", "authorization_code")
+	v.Set("redirect_uri", o.Config.RedirectURL)
+	req, err := http.NewRequest(http.MethodPost, o.Config.TokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := o.do(req.WithContext(ctx), &token); err != nil {
+		return "", fmt.Errorf("unable to exchange authorization code: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("unable to exchange authorization code: no access token in response")
+	}
+	return token.AccessToken, nil
+}
+
+// User gets the account that an access token belongs to.
+func (o *OAuth) User(ctx context.Context, accessToken string) (User, error) {
+	req, err := http.NewRequest(http.MethodGet, o.Config.UsersURL, nil)
+	if err != nil {
+		return User{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Client-Id", o.Config.ClientID)
+
+	var users struct {
+		Data []User `json:"data"`
+	}
+	if err := o.do(req.WithContext(ctx), &users); err != nil {
+		return User{}, fmt.Errorf("unable to get user: %w", err)
+	}
+	if len(users.Data) == 0 || users.Data[0].Login == "" {
+		return User{}, fmt.Errorf("unable to get user: no user in response")
+	}
+	u := users.Data[0]
+	u.Login = strings.ToLower(u.Login)
+	return u, nil
+}
+
+func (o *OAuth) do(req *http.Request, v interface{}) error {
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
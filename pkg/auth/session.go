@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// Session is a logged in dashboard user.
+type Session struct {
+	User    User
+	Expires time.Time
+}
+
+// Sessions stores the sessions of logged in users in memory.
+type Sessions struct {
+	duration      time.Duration
+	sessions      map[string]Session
+	sessionsMutex *sync.Mutex
+}
+
+// NewSessions creates a session store whose sessions last for the given duration.
+func NewSessions(duration time.Duration) *Sessions {
+	return &Sessions{
+		duration:      duration,
+		sessions:      make(map[string]Session),
+		sessionsMutex: &sync.Mutex{},
+	}
+}
+
+// Create starts a session for the user and returns its ID.
+func (s *Sessions) Create(user User) (string, Session, error) {
+	id, err := RandomString()
+	if err != nil {
+		return "", Session{}, err
+	}
+	session := Session{
+		User:    user,
+		Expires: time.Now().Add(s.duration),
+	}
+
+	s.sessionsMutex.Lock()
+	defer s.sessionsMutex.Unlock()
+	now := time.Now()
+	for id, existing := range s.sessions {
+		if now.After(existing.Expires) {
+			delete(s.sessions, id)
+		}
+	}
+	s.sessions[id] = session
+	return id, session, nil
+}
+
+// Get returns the session with the given ID if it has not expired.
+func (s *Sessions) Get(id string) (Session, bool) {
+	if s == nil {
+		return Session{}, false
+	}
+	s.sessionsMutex.Lock()
+	defer s.sessionsMutex.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return Session{}, false
+	}
+	if time.Now().After(session.Expires) {
+		delete(s.sessions, id)
+		return Session{}, false
+	}
+	return session, true
+}
+
+// Delete ends a session.
+func (s *Sessions) Delete(id string) {
+	s.sessionsMutex.Lock()
+	delete(s.sessions, id)
+	s.sessionsMutex.Unlock()
+}
+
+// RandomString returns a random URL safe string suitable for session IDs and tokens.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
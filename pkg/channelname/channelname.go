@@ -0,0 +1,13 @@
+// Package channelname validates the names of twitch channels, so that the
+// packages that store or send them agree on what a channel name is.
+package channelname
+
+import "regexp"
+
+var pattern = regexp.MustCompile(`^[a-z0-9_]{1,25}$`)
+
+// Valid determines if the name is a twitch channel name, which is up to 25
+// lower case letters, digits and underscores.
+func Valid(name string) bool {
+	return pattern.MatchString(name)
+}
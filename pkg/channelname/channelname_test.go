@@ -0,0 +1,22 @@
+package channelname
+
+import "testing"
+
+func TestValid(t *testing.T) {
+	tests := map[string]bool{
+		"foo":                        true,
+		"foo_bar123":                 true,
+		"":                           false,
+		"Foo":                        false,
+		"#foo":                       false,
+		"foo bar":                    false,
+		"../foo":                     false,
+		"abcdefghijklmnopqrstuvwxy":  true,
+		"abcdefghijklmnopqrstuvwxyz": false,
+	}
+	for name, expected := range tests {
+		if Valid(name) != expected {
+			t.Errorf("%q: expected %t, got %t", name, expected, !expected)
+		}
+	}
+}
@@ -0,0 +1,456 @@
+// Package chatlog archives the chat of the bot's channels.
+//
+// Each channel has a directory of daily logs named after their UTC date.
+// A day is stored as JSON Lines of Entry in "2006-01-02.jsonl", and rendered
+// as a plain text IRC log in "2006-01-02.txt". When the Archive is maintained,
+// days before today are gzipped, adding a ".gz" extension, if compression is
+// enabled, and days that are older than the retention are removed.
+package chatlog
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/channelname"
+)
+
+// Format is the format of a log, which is also its file extension.
+type Format string
+
+// Log formats.
+const (
+	FormatJSON Format = "jsonl"
+	FormatText Format = "txt"
+)
+
+// DateLayout is the layout of the dates that logs are named after.
+const DateLayout = "2006-01-02"
+
+// ModuleName is the name of the module that archives the chat of a channel.
+// Channels can turn archiving off by disabling it.
+const ModuleName = "chatlog"
+
+// maintainInterval is how often a started Archive compresses and removes old days.
+const maintainInterval = time.Hour
+
+// queueSize is the number of lines that can be waiting to be written.
+// Lines are dropped when the queue is full.
+const queueSize = 1024
+
+// ErrInvalidChannel is returned for channel names that cannot be archived.
+var ErrInvalidChannel = errors.New("invalid channel name")
+
+// Options configures an Archive.
+type Options struct {
+	// Dir is the directory that the logs are stored in.
+	Dir string
+	// RetentionDays is the number of days that logs are kept for, including
+	// today. Logs are kept forever if it is zero.
+	RetentionDays int
+	// Compress gzips the logs of days before today.
+	Compress bool
+	// Search indexes the messages so that the Archive can be searched.
+	// The messages already in the Archive are indexed when it is opened.
+	Search bool
+	Log    *log.Logger
+}
+
+// Archive writes the chat of channels to daily logs.
+type Archive struct {
+	days    map[string]*day
+	done    chan struct{}
+	flushes chan chan struct{}
+	index   *index
+	lines   chan string
+	mutex   *sync.Mutex
+	now     func() time.Time
+	opts    Options
+	stopped chan struct{}
+}
+
+// day is the open log files of a channel.
+type day struct {
+	date string
+	json *os.File
+	text *os.File
+}
+
+func (d *day) close() error {
+	err := d.json.Close()
+	if textErr := d.text.Close(); err == nil {
+		err = textErr
+	}
+	return err
+}
+
+// Open opens the Archive in the directory, creating it if it does not exist.
+func Open(opts Options) (*Archive, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("chat log directory is not set")
+	}
+	if opts.RetentionDays < 0 {
+		return nil, errors.New("chat log retention cannot be negative")
+	}
+	if opts.Log == nil {
+		opts.Log = log.StandardLogger()
+	}
+	if err := os.MkdirAll(opts.Dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create chat log directory: %w", err)
+	}
+	a := &Archive{
+		days:    make(map[string]*day),
+		done:    make(chan struct{}),
+		flushes: make(chan chan struct{}),
+		lines:   make(chan string, queueSize),
+		mutex:   &sync.Mutex{},
+		now:     time.Now,
+		opts:    opts,
+		stopped: make(chan struct{}),
+	}
+	if err := a.Maintain(); err != nil {
+		return nil, err
+	}
+	if opts.Search {
+		a.index = newIndex()
+		if err := a.load(); err != nil {
+			return nil, err
+		}
+	}
+	go a.run()
+	return a, nil
+}
+
+// Write queues a raw IRC line to be archived, so that the caller does not
+// wait for the logs to be written. Lines that are not PRIVMSG, USERNOTICE,
+// CLEARCHAT or CLEARMSG are ignored.
+func (a *Archive) Write(line string) {
+	select {
+	case a.lines <- line:
+	default:
+		a.opts.Log.Warn("chat log queue is full, dropped a chat message")
+	}
+}
+
+// Flush waits until the lines queued before it was called have been written.
+func (a *Archive) Flush() {
+	done := make(chan struct{})
+	select {
+	case a.flushes <- done:
+		<-done
+	case <-a.stopped:
+	}
+}
+
+// run writes the queued lines until the Archive is closed.
+func (a *Archive) run() {
+	defer close(a.stopped)
+	for {
+		select {
+		case line := <-a.lines:
+			a.writeLogged(line)
+		case done := <-a.flushes:
+			a.drain()
+			close(done)
+		case <-a.done:
+			a.drain()
+			return
+		}
+	}
+}
+
+// drain writes the lines that are currently queued.
+func (a *Archive) drain() {
+	for {
+		select {
+		case line := <-a.lines:
+			a.writeLogged(line)
+		default:
+			return
+		}
+	}
+}
+
+func (a *Archive) writeLogged(line string) {
+	if err := a.write(line); err != nil {
+		a.opts.Log.WithField("error", err).Error("failed to archive chat message")
+	}
+}
+
+// write archives a raw IRC line.
+func (a *Archive) write(line string) error {
+	e, ok := ParseEntry(line, a.now())
+	if !ok {
+		return nil
+	}
+	if !channelname.Valid(e.Channel) {
+		return fmt.Errorf("channel '%s': %w", e.Channel, ErrInvalidChannel)
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	d, err := a.open(e.Channel, a.now().UTC().Format(DateLayout))
+	if err != nil {
+		return err
+	}
+	if _, err := d.json.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("unable to write chat log: %w", err)
+	}
+	if _, err := d.text.WriteString(e.String() + "\n"); err != nil {
+		return fmt.Errorf("unable to write chat log: %w", err)
+	}
+	if a.index != nil {
+		a.index.add(e.match())
+	}
+	return nil
+}
+
+// open returns the channel's log files for the date, rotating them if the date has changed.
+func (a *Archive) open(channel, date string) (*day, error) {
+	d, ok := a.days[channel]
+	if ok && d.date == date {
+		return d, nil
+	}
+	if ok {
+		// The previous day is compressed when the Archive is next maintained.
+		delete(a.days, channel)
+		if err := d.close(); err != nil {
+			a.opts.Log.WithField("error", err).Error("failed to close chat log")
+		}
+	}
+
+	dir := filepath.Join(a.opts.Dir, channel)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create chat log directory: %w", err)
+	}
+	d = &day{date: date}
+	var err error
+	if d.json, err = openAppend(filepath.Join(dir, date+"."+string(FormatJSON))); err != nil {
+		return nil, err
+	}
+	if d.text, err = openAppend(filepath.Join(dir, date+"."+string(FormatText))); err != nil {
+		d.json.Close()
+		return nil, err
+	}
+	a.days[channel] = d
+	return d, nil
+}
+
+func openAppend(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open chat log: %w", err)
+	}
+	return f, nil
+}
+
+// Maintain compresses the logs of days before today, if compression is
+// enabled, and removes the logs that are older than the retention.
+func (a *Archive) Maintain() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.maintain(a.now())
+}
+
+func (a *Archive) maintain(now time.Time) error {
+	today := now.UTC().Format(DateLayout)
+	for channel, d := range a.days {
+		if d.date != today {
+			d.close()
+			delete(a.days, channel)
+		}
+	}
+	oldest := ""
+	if a.opts.RetentionDays > 0 {
+		oldest = now.UTC().AddDate(0, 0, 1-a.opts.RetentionDays).Format(DateLayout)
+		if a.index != nil {
+			a.index.prune(truncateDay(now).AddDate(0, 0, 1-a.opts.RetentionDays))
+		}
+	}
+
+	channels, err := ioutil.ReadDir(a.opts.Dir)
+	if err != nil {
+		return fmt.Errorf("unable to read chat log directory: %w", err)
+	}
+	for _, channel := range channels {
+		if !channel.IsDir() {
+			continue
+		}
+		dir := filepath.Join(a.opts.Dir, channel.Name())
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("unable to read chat log directory: %w", err)
+		}
+		for _, f := range files {
+			date := strings.SplitN(f.Name(), ".", 2)[0]
+			if _, err := time.Parse(DateLayout, date); err != nil {
+				continue
+			}
+			path := filepath.Join(dir, f.Name())
+			switch {
+			case oldest != "" && date < oldest:
+				if err := os.Remove(path); err != nil {
+					return fmt.Errorf("unable to remove chat log: %w", err)
+				}
+			case a.opts.Compress && date < today && !strings.HasSuffix(path, ".gz"):
+				if err := compress(path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// compress replaces the file with a gzipped copy.
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to compress chat log: %w", err)
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to compress chat log: %w", err)
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return fmt.Errorf("unable to compress chat log: %w", err)
+	}
+	return os.Remove(path)
+}
+
+// Export writes a channel's log in the format for every day from the
+// date of from to the date of to, inclusive. Days without a log are skipped.
+func (a *Archive) Export(w io.Writer, channel string, f Format, from, to time.Time) error {
+	if !channelname.Valid(channel) {
+		return fmt.Errorf("channel '%s': %w", channel, ErrInvalidChannel)
+	}
+	if f != FormatJSON && f != FormatText {
+		return fmt.Errorf("unknown chat log format '%s'", f)
+	}
+	a.Flush()
+	from, to = truncateDay(from), truncateDay(to)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		r, err := a.openDay(channel, d.Format(DateLayout), f)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			continue
+		}
+		_, err = io.Copy(w, r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openDay opens the log of a single day, or returns nil if there is none.
+func (a *Archive) openDay(channel, date string, f Format) (io.ReadCloser, error) {
+	path := filepath.Join(a.opts.Dir, channel, date+"."+string(f))
+	// The lock stops the file from being compressed or removed while it is
+	// opened. It can be read once it is open, even if it is removed.
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if file, err := os.Open(path); err == nil {
+		return file, nil
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to open chat log: %w", err)
+	}
+	file, err := os.Open(path + ".gz")
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open chat log: %w", err)
+	}
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("unable to read chat log %s: %w", path+".gz", err)
+	}
+	return &gzipFile{Reader: zr, file: file}, nil
+}
+
+// gzipFile reads a gzipped file, closing the file when it is closed.
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Start maintains the Archive every hour until the returned function is called.
+func (a *Archive) Start() (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(maintainInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := a.Maintain(); err != nil {
+					a.opts.Log.WithField("error", err).Error("failed to maintain chat logs")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// Close writes the queued lines and closes the open log files.
+func (a *Archive) Close() error {
+	select {
+	case <-a.done:
+	default:
+		close(a.done)
+	}
+	<-a.stopped
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	var err error
+	for channel, d := range a.days {
+		if closeErr := d.close(); err == nil {
+			err = closeErr
+		}
+		delete(a.days, channel)
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package chatlog
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openTest(t *testing.T, opts Options, now *time.Time) *Archive {
+	opts.Dir = t.TempDir()
+	a, err := Open(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.now = func() time.Time { return *now }
+	t.Cleanup(func() { a.Close() })
+	return a
+}
+
+func write(t *testing.T, a *Archive, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if err := a.write(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func files(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	return names
+}
+
+func TestArchive_Rotation(t *testing.T) {
+	now := time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)
+	a := openTest(t, Options{Compress: true, RetentionDays: 2}, &now)
+
+	write(t, a, ":foo!foo@foo.tmi.twitch.tv PRIVMSG #test :first")
+	now = now.Add(time.Minute * 2)
+	write(t, a,
+		":foo!foo@foo.tmi.twitch.tv PRIVMSG #test :second",
+		"@emote-only=0 :tmi.twitch.tv ROOMSTATE #test",
+	)
+
+	dir := filepath.Join(a.opts.Dir, "test")
+	expected := "2026-10-18.jsonl 2026-10-18.txt 2026-10-19.jsonl 2026-10-19.txt"
+	if names := strings.Join(files(t, dir), " "); names != expected {
+		t.Errorf("expected the previous day to be left for maintenance, got %s", names)
+	}
+	if err := a.Maintain(); err != nil {
+		t.Fatal(err)
+	}
+	expected = "2026-10-18.jsonl.gz 2026-10-18.txt.gz 2026-10-19.jsonl 2026-10-19.txt"
+	if names := strings.Join(files(t, dir), " "); names != expected {
+		t.Errorf("expected files %s, got %s", expected, names)
+	}
+
+	var b bytes.Buffer
+	if err := a.Export(&b, "test", FormatText, now.AddDate(0, 0, -1), now); err != nil {
+		t.Fatal(err)
+	}
+	expected = "[2026-10-18 23:59:00] #test foo: first\n[2026-10-19 00:01:00] #test foo: second\n"
+	if b.String() != expected {
+		t.Errorf("expected %q, got %q", expected, b.String())
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	now = now.AddDate(0, 0, 1)
+	if err := a.Maintain(); err != nil {
+		t.Fatal(err)
+	}
+	expected = "2026-10-19.jsonl.gz 2026-10-19.txt.gz notes.txt"
+	if names := strings.Join(files(t, dir), " "); names != expected {
+		t.Errorf("expected days outside the retention to be removed, got %s", names)
+	}
+}
+
+func TestArchive_Export(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	a := openTest(t, Options{}, &now)
+	write(t, a, "@id=1 :foo!foo@foo.tmi.twitch.tv PRIVMSG #test :hello")
+
+	var b bytes.Buffer
+	if err := a.Export(&b, "test", FormatJSON, now, now); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), `{"time":"2026-10-19T12:00:00Z","channel":"test","command":"PRIVMSG","id":"1","username":"foo","text":"hello"`) {
+		t.Errorf("unexpected export %s", b.String())
+	}
+
+	b.Reset()
+	if err := a.Export(&b, "other", FormatJSON, now.AddDate(0, 0, -7), now); err != nil || b.Len() != 0 {
+		t.Errorf("expected an empty export, got %q, %v", b.String(), err)
+	}
+	if err := a.Export(&b, "../test", FormatJSON, now, now); err == nil {
+		t.Error("expected an invalid channel to return an error")
+	}
+}
+
+func TestArchive_Queue(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	a := openTest(t, Options{}, &now)
+	a.Write(":foo!foo@foo.tmi.twitch.tv PRIVMSG #test :queued")
+	a.Write("@emote-only=0 :tmi.twitch.tv ROOMSTATE #test")
+	a.Write(":foo!foo@foo.tmi.twitch.tv PRIVMSG #Invalid :dropped")
+
+	var b bytes.Buffer
+	if err := a.Export(&b, "test", FormatText, now, now); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "[2026-10-19 12:00:00] #test foo: queued\n"; b.String() != expected {
+		t.Errorf("expected the queued line to be written before exporting, got %q", b.String())
+	}
+
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	a.Flush()
+}
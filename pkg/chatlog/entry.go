@@ -0,0 +1,201 @@
+package chatlog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commands that are archived.
+const (
+	CommandPrivmsg    = "PRIVMSG"
+	CommandUserNotice = "USERNOTICE"
+	CommandClearChat  = "CLEARCHAT"
+	CommandClearMsg   = "CLEARMSG"
+)
+
+// Entry is a single line of a channel's JSON Lines log.
+type Entry struct {
+	// Time is when twitch sent the message, or when it was received if twitch did not say.
+	Time    time.Time `json:"time"`
+	Channel string    `json:"channel"`
+	// Command is PRIVMSG, USERNOTICE, CLEARCHAT or CLEARMSG.
+	Command string `json:"command"`
+	// ID is the message's id tag.
+	ID string `json:"id,omitempty"`
+	// Username is the login of the user that sent the message, or that the USERNOTICE is about.
+	Username    string `json:"username,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Text        string `json:"text,omitempty"`
+	// Action is true for /me messages, whose Text does not include the ACTION wrapper.
+	Action bool `json:"action,omitempty"`
+	// Notice is the msg-id of a USERNOTICE, such as "sub" or "raid".
+	Notice string `json:"notice,omitempty"`
+	// Target is the login of the user whose messages were removed by a CLEARCHAT or CLEARMSG.
+	// A CLEARCHAT without a Target cleared the whole chat.
+	Target string `json:"target,omitempty"`
+	// TargetID is the id of the message removed by a CLEARMSG.
+	TargetID string `json:"targetId,omitempty"`
+	// Duration is the length of a timeout in seconds. A CLEARCHAT with a
+	// Target and no Duration is a permanent ban.
+	Duration int `json:"duration,omitempty"`
+	// Tags are all of the message's IRC tags, unescaped.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Raw is the IRC line as it was received.
+	Raw string `json:"raw"`
+}
+
+// ParseEntry parses a raw IRC line. It returns false if the line is not one of the archived commands.
+func ParseEntry(line string, received time.Time) (Entry, bool) {
+	m := parseLine(line)
+	switch m.command {
+	case CommandPrivmsg, CommandUserNotice, CommandClearChat, CommandClearMsg:
+	default:
+		return Entry{}, false
+	}
+	if len(m.params) == 0 || !strings.HasPrefix(m.params[0], "#") {
+		return Entry{}, false
+	}
+
+	e := Entry{
+		Time:        received.UTC(),
+		Channel:     strings.ToLower(m.params[0][1:]),
+		Command:     m.command,
+		ID:          m.tags["id"],
+		DisplayName: m.tags["display-name"],
+		Text:        m.trailing,
+		Tags:        m.tags,
+		Raw:         line,
+	}
+	if ms, err := strconv.ParseInt(m.tags["tmi-sent-ts"], 10, 64); err == nil {
+		e.Time = time.Unix(0, ms*int64(time.Millisecond)).UTC()
+	}
+
+	switch m.command {
+	case CommandPrivmsg:
+		e.Username = m.nick()
+		if strings.HasPrefix(e.Text, "\x01ACTION ") && strings.HasSuffix(e.Text, "\x01") {
+			e.Action = true
+			e.Text = e.Text[len("\x01ACTION ") : len(e.Text)-1]
+		}
+	case CommandUserNotice:
+		e.Username = m.tags["login"]
+		e.Notice = m.tags["msg-id"]
+	case CommandClearChat:
+		e.Target = e.Text
+		e.Text = ""
+		e.Duration, _ = strconv.Atoi(m.tags["ban-duration"])
+	case CommandClearMsg:
+		e.Target = m.tags["login"]
+		e.TargetID = m.tags["target-msg-id"]
+	}
+	return e, true
+}
+
+func (e Entry) match() Match {
+	return Match{
+		Time:        e.Time,
+		Channel:     e.Channel,
+		Command:     e.Command,
+		ID:          e.ID,
+		Username:    e.Username,
+		DisplayName: e.DisplayName,
+		Text:        e.Text,
+		Action:      e.Action,
+	}
+}
+
+// String renders the entry as a line of a plain text IRC log.
+func (e Entry) String() string {
+	prefix := fmt.Sprintf("[%s] #%s", e.Time.UTC().Format("2006-01-02 15:04:05"), e.Channel)
+	switch e.Command {
+	case CommandPrivmsg:
+		if e.Action {
+			return fmt.Sprintf("%s * %s %s", prefix, e.Username, e.Text)
+		}
+		return fmt.Sprintf("%s %s: %s", prefix, e.Username, e.Text)
+	case CommandUserNotice:
+		text := e.Tags["system-msg"]
+		if text == "" {
+			text = e.Notice
+		}
+		if e.Text != "" {
+			text = fmt.Sprintf("%s %s: %s", text, e.Username, e.Text)
+		}
+		return fmt.Sprintf("%s %s", prefix, text)
+	case CommandClearChat:
+		switch {
+		case e.Target == "":
+			return prefix + " chat has been cleared"
+		case e.Duration > 0:
+			return fmt.Sprintf("%s %s has been timed out for %d seconds", prefix, e.Target, e.Duration)
+		}
+		return fmt.Sprintf("%s %s has been banned", prefix, e.Target)
+	case CommandClearMsg:
+		return fmt.Sprintf("%s a message from %s was deleted: %s", prefix, e.Target, e.Text)
+	}
+	return fmt.Sprintf("%s %s", prefix, e.Raw)
+}
+
+// ircLine is the parts of a raw IRC line.
+type ircLine struct {
+	tags     map[string]string
+	prefix   string
+	command  string
+	params   []string
+	trailing string
+}
+
+// nick returns the nickname in the line's prefix.
+func (l ircLine) nick() string {
+	if i := strings.Index(l.prefix, "!"); i >= 0 {
+		return l.prefix[:i]
+	}
+	return l.prefix
+}
+
+func parseLine(line string) ircLine {
+	l := ircLine{tags: map[string]string{}}
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "@") {
+		i := strings.Index(line, " ")
+		if i < 0 {
+			return l
+		}
+		for _, tag := range strings.Split(line[1:i], ";") {
+			kv := strings.SplitN(tag, "=", 2)
+			if len(kv) == 2 {
+				l.tags[kv[0]] = unescapeTag(kv[1])
+			} else {
+				l.tags[kv[0]] = ""
+			}
+		}
+		line = strings.TrimLeft(line[i+1:], " ")
+	}
+	if strings.HasPrefix(line, ":") {
+		i := strings.Index(line, " ")
+		if i < 0 {
+			return l
+		}
+		l.prefix = line[1:i]
+		line = strings.TrimLeft(line[i+1:], " ")
+	}
+	if i := strings.Index(line, " :"); i >= 0 {
+		l.trailing = line[i+2:]
+		line = line[:i]
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return l
+	}
+	l.command = fields[0]
+	l.params = fields[1:]
+	return l
+}
+
+var tagReplacer = strings.NewReplacer(`\:`, ";", `\s`, " ", `\\`, `\`, `\r`, "\r", `\n`, "\n")
+
+func unescapeTag(value string) string {
+	return tagReplacer.Replace(value)
+}
@@ -0,0 +1,83 @@
+package chatlog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseEntry(t *testing.T) {
+	received := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		line     string
+		expected Entry
+		text     string
+	}{
+		{
+			line:     "@display-name=Foo;id=abc;tmi-sent-ts=1760875200000 :foo!foo@foo.tmi.twitch.tv PRIVMSG #test :hello there",
+			expected: Entry{Time: time.Unix(1760875200, 0).UTC(), Channel: "test", Command: CommandPrivmsg, ID: "abc", Username: "foo", DisplayName: "Foo", Text: "hello there"},
+			text:     "[2025-10-19 12:00:00] #test foo: hello there",
+		},
+		{
+			line:     "@id=abc :foo!foo@foo.tmi.twitch.tv PRIVMSG #test :\x01ACTION waves\x01",
+			expected: Entry{Time: received, Channel: "test", Command: CommandPrivmsg, ID: "abc", Username: "foo", Text: "waves", Action: true},
+			text:     "[2026-10-19 12:00:00] #test * foo waves",
+		},
+		{
+			line:     `@login=foo;msg-id=resub;system-msg=foo\ssubscribed\sfor\s5\smonths! :tmi.twitch.tv USERNOTICE #test :hype`,
+			expected: Entry{Time: received, Channel: "test", Command: CommandUserNotice, Username: "foo", Text: "hype", Notice: "resub"},
+			text:     "[2026-10-19 12:00:00] #test foo subscribed for 5 months! foo: hype",
+		},
+		{
+			line:     "@ban-duration=600 :tmi.twitch.tv CLEARCHAT #test :foo",
+			expected: Entry{Time: received, Channel: "test", Command: CommandClearChat, Target: "foo", Duration: 600},
+			text:     "[2026-10-19 12:00:00] #test foo has been timed out for 600 seconds",
+		},
+		{
+			line:     "@room-id=1 :tmi.twitch.tv CLEARCHAT #test :foo",
+			expected: Entry{Time: received, Channel: "test", Command: CommandClearChat, Target: "foo"},
+			text:     "[2026-10-19 12:00:00] #test foo has been banned",
+		},
+		{
+			line:     "@room-id=1 :tmi.twitch.tv CLEARCHAT #test",
+			expected: Entry{Time: received, Channel: "test", Command: CommandClearChat},
+			text:     "[2026-10-19 12:00:00] #test chat has been cleared",
+		},
+		{
+			line:     "@login=foo;target-msg-id=abc :tmi.twitch.tv CLEARMSG #test :bad words",
+			expected: Entry{Time: received, Channel: "test", Command: CommandClearMsg, Text: "bad words", Target: "foo", TargetID: "abc"},
+			text:     "[2026-10-19 12:00:00] #test a message from foo was deleted: bad words",
+		},
+	}
+	for _, tt := range tests {
+		e, ok := ParseEntry(tt.line, received)
+		if !ok {
+			t.Errorf("expected %q to be archived", tt.line)
+			continue
+		}
+		if e.Raw != tt.line {
+			t.Errorf("expected raw line %q, got %q", tt.line, e.Raw)
+		}
+		e.Raw, e.Tags = "", nil
+		if !reflect.DeepEqual(e, tt.expected) {
+			t.Errorf("expected %+v, got %+v", tt.expected, e)
+		}
+		e.Tags = parseLine(tt.line).tags
+		if s := e.String(); s != tt.text {
+			t.Errorf("expected %q, got %q", tt.text, s)
+		}
+	}
+}
+
+func TestParseEntry_Ignored(t *testing.T) {
+	for _, line := range []string{
+		"@emote-only=0 :tmi.twitch.tv ROOMSTATE #test",
+		"@msg-id=slow_on :tmi.twitch.tv NOTICE #test :This room is now in slow mode.",
+		":foo!foo@foo.tmi.twitch.tv JOIN #test",
+		"PING :tmi.twitch.tv",
+	} {
+		if _, ok := ParseEntry(line, time.Now()); ok {
+			t.Errorf("expected %q to be ignored", line)
+		}
+	}
+}
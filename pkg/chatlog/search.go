@@ -0,0 +1,379 @@
+package chatlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrSearchDisabled is returned when searching an Archive that is not indexed.
+var ErrSearchDisabled = errors.New("chat log search is not enabled")
+
+// orderSlack is how far the times of messages can be out of order, since
+// they are indexed in the order that they were received.
+const orderSlack = time.Minute
+
+// Query filters the messages returned by a search. Empty fields match every message.
+type Query struct {
+	Channel  string
+	Username string
+	// From and To limit the messages to those sent within the range, inclusive.
+	From time.Time
+	To   time.Time
+	// Text is the words that must all appear in the message, ignoring case.
+	// Words are matched whole, and words that contain punctuation, such as
+	// links, must appear exactly.
+	Text string
+	// Channels limits the messages to these channels, unless it is empty.
+	Channels []string
+	// Limit is the greatest number of matches returned. Count includes every match.
+	Limit int
+}
+
+// Result is the outcome of a search.
+type Result struct {
+	// Count is the number of messages that matched.
+	Count int `json:"count"`
+	// Matches are the newest matches, newest first.
+	Matches []Match `json:"matches"`
+}
+
+// Match is a message found by a search. It has the same fields as an Entry,
+// leaving out the tags and raw line.
+type Match struct {
+	Time        time.Time `json:"time"`
+	Channel     string    `json:"channel"`
+	Command     string    `json:"command"`
+	ID          string    `json:"id,omitempty"`
+	Username    string    `json:"username,omitempty"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Text        string    `json:"text,omitempty"`
+	Action      bool      `json:"action,omitempty"`
+}
+
+// indexed determines if a message is searchable, which are
+// chat messages and the messages shared with a USERNOTICE.
+func (m Match) indexed() bool {
+	return m.Text != "" && (m.Command == CommandPrivmsg || m.Command == CommandUserNotice)
+}
+
+// index is an inverted index of messages. Messages are numbered in the order
+// that they are added, and each posting list holds ascending message numbers.
+type index struct {
+	// first is the number of docs[0]. It increases as old messages are pruned.
+	first    uint32
+	docs     []Match
+	terms    map[string][]uint32
+	users    map[string][]uint32
+	channels map[string][]uint32
+	// strings interns the names that repeat between messages.
+	strings map[string]string
+	mutex   *sync.RWMutex
+}
+
+func newIndex() *index {
+	return &index{
+		terms:    make(map[string][]uint32),
+		users:    make(map[string][]uint32),
+		channels: make(map[string][]uint32),
+		strings:  make(map[string]string),
+		mutex:    &sync.RWMutex{},
+	}
+}
+
+func (ix *index) intern(s string) string {
+	if v, ok := ix.strings[s]; ok {
+		return v
+	}
+	ix.strings[s] = s
+	return s
+}
+
+// add indexes a message, ignoring messages that are not searchable.
+func (ix *index) add(m Match) {
+	if !m.indexed() {
+		return
+	}
+	ix.mutex.Lock()
+	defer ix.mutex.Unlock()
+
+	m.Channel = ix.intern(m.Channel)
+	m.Command = ix.intern(m.Command)
+	m.Username = ix.intern(strings.ToLower(m.Username))
+	m.DisplayName = ix.intern(m.DisplayName)
+	n := ix.first + uint32(len(ix.docs))
+	ix.docs = append(ix.docs, m)
+
+	ix.channels[m.Channel] = append(ix.channels[m.Channel], n)
+	if m.Username != "" {
+		ix.users[m.Username] = append(ix.users[m.Username], n)
+	}
+	for _, t := range tokenize(m.Text) {
+		ix.terms[t] = append(ix.terms[t], n)
+	}
+}
+
+// prune removes the messages sent before the time.
+func (ix *index) prune(before time.Time) {
+	ix.mutex.Lock()
+	defer ix.mutex.Unlock()
+	n := 0
+	for n < len(ix.docs) && ix.docs[n].Time.Before(before) {
+		n++
+	}
+	if n == 0 {
+		return
+	}
+	ix.docs = append([]Match(nil), ix.docs[n:]...)
+	ix.first += uint32(n)
+	for _, postings := range []map[string][]uint32{ix.terms, ix.users, ix.channels} {
+		for key, list := range postings {
+			i := sort.Search(len(list), func(i int) bool { return list[i] >= ix.first })
+			switch {
+			case i == len(list):
+				delete(postings, key)
+			case i > 0:
+				postings[key] = append([]uint32(nil), list[i:]...)
+			}
+		}
+	}
+}
+
+// search finds the messages that match the query.
+func (ix *index) search(q Query) Result {
+	ix.mutex.RLock()
+	defer ix.mutex.RUnlock()
+
+	res := Result{Matches: []Match{}}
+	var lists [][]uint32
+	if q.Channel != "" {
+		lists = append(lists, ix.channels[strings.ToLower(q.Channel)])
+	}
+	if q.Username != "" {
+		lists = append(lists, ix.users[strings.ToLower(strings.TrimPrefix(q.Username, "@"))])
+	}
+	// Words that are a single token are answered by their posting
+	// list, while the others must also be found in the text.
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(q.Text)) {
+		tokens := tokenize(w)
+		for _, t := range tokens {
+			lists = append(lists, ix.terms[t])
+		}
+		if len(tokens) != 1 || tokens[0] != w {
+			words = append(words, w)
+		}
+	}
+
+	match := func(n uint32) bool {
+		if n < ix.first {
+			return true
+		}
+		m := ix.docs[n-ix.first]
+		if !q.To.IsZero() && m.Time.After(q.To) {
+			return true
+		}
+		if !q.From.IsZero() && m.Time.Before(q.From) {
+			// Messages before this one were sent earlier, give or take the slack.
+			return !m.Time.Before(q.From.Add(-orderSlack))
+		}
+		if !q.allows(m, words) {
+			return true
+		}
+		res.Count++
+		if q.Limit <= 0 || len(res.Matches) < q.Limit {
+			res.Matches = append(res.Matches, m)
+		}
+		return true
+	}
+
+	if len(lists) == 0 {
+		for i := len(ix.docs) - 1; i >= 0; i-- {
+			if !match(ix.first + uint32(i)) {
+				break
+			}
+		}
+		return res
+	}
+
+	// Walk the shortest list from the newest message, moving back through the others.
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+	shortest, others := lists[0], lists[1:]
+	cursors := make([]int, len(others))
+	for i, list := range others {
+		cursors[i] = len(list) - 1
+	}
+	for i := len(shortest) - 1; i >= 0; i-- {
+		n := shortest[i]
+		if contains(others, cursors, n) && !match(n) {
+			break
+		}
+	}
+	return res
+}
+
+// allows checks the parts of a query that are not answered by the posting lists.
+func (q Query) allows(m Match, words []string) bool {
+	if len(q.Channels) > 0 {
+		allowed := false
+		for _, c := range q.Channels {
+			if strings.EqualFold(c, m.Channel) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	if len(words) == 0 {
+		return true
+	}
+	text := strings.ToLower(m.Text)
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains determines if every list contains n. Each cursor is moved back
+// to the list's last number that is not greater than n, so calls must be
+// made with descending numbers.
+func contains(lists [][]uint32, cursors []int, n uint32) bool {
+	found := true
+	for i, list := range lists {
+		for cursors[i] >= 0 && list[cursors[i]] > n {
+			cursors[i]--
+		}
+		if cursors[i] < 0 || list[cursors[i]] != n {
+			found = false
+		}
+	}
+	return found
+}
+
+// tokenize splits text into its distinct lower case words.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	seen := make(map[string]bool, len(fields))
+	tokens := fields[:0]
+	for _, f := range fields {
+		if !seen[f] {
+			seen[f] = true
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+// Search finds the archived messages that match the query.
+func (a *Archive) Search(q Query) (Result, error) {
+	if a.index == nil {
+		return Result{}, ErrSearchDisabled
+	}
+	a.Flush()
+	return a.index.search(q), nil
+}
+
+// load indexes the messages already in the Archive, a day at a time.
+func (a *Archive) load() error {
+	days := make(map[string][]string)
+	channels, err := ioutil.ReadDir(a.opts.Dir)
+	if err != nil {
+		return fmt.Errorf("unable to read chat log directory: %w", err)
+	}
+	for _, channel := range channels {
+		if !channel.IsDir() {
+			continue
+		}
+		dir := filepath.Join(a.opts.Dir, channel.Name())
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("unable to read chat log directory: %w", err)
+		}
+		for _, f := range files {
+			name := f.Name()
+			date := strings.SplitN(name, ".", 2)[0]
+			if _, err := time.Parse(DateLayout, date); err != nil {
+				continue
+			}
+			if strings.HasSuffix(name, "."+string(FormatJSON)) || strings.HasSuffix(name, "."+string(FormatJSON)+".gz") {
+				days[date] = append(days[date], filepath.Join(dir, name))
+			}
+		}
+	}
+
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates {
+		var matches []Match
+		for _, path := range days[date] {
+			if matches, err = a.readMatches(path, matches); err != nil {
+				return err
+			}
+		}
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Time.Before(matches[j].Time) })
+		for _, m := range matches {
+			a.index.add(m)
+		}
+	}
+	return nil
+}
+
+// readMatches appends the searchable messages of a log file to matches.
+// Lines that cannot be decoded, such as one left half written when the bot
+// stopped, are skipped with a warning, as is the rest of a truncated file.
+func (a *Archive) readMatches(path string, matches []Match) ([]Match, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open chat log: %w", err)
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			a.opts.Log.WithFields(log.Fields{"error": err, "path": path}).Warn("skipped a truncated chat log")
+			return matches, nil
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; s.Scan(); line++ {
+		var m Match
+		if err := json.Unmarshal(s.Bytes(), &m); err != nil {
+			a.opts.Log.WithFields(log.Fields{"error": err, "path": path, "line": line}).Warn("skipped an invalid chat log line")
+			continue
+		}
+		if m.indexed() {
+			matches = append(matches, m)
+		}
+	}
+	if err := s.Err(); err != nil {
+		a.opts.Log.WithFields(log.Fields{"error": err, "path": path}).Warn("skipped the rest of a truncated chat log")
+	}
+	return matches, nil
+}
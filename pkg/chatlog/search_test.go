@@ -0,0 +1,127 @@
+package chatlog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func privmsg(channel, user, text string, t time.Time) string {
+	return fmt.Sprintf("@tmi-sent-ts=%d :%s!%s@%s.tmi.twitch.tv PRIVMSG #%s :%s", t.UnixNano()/int64(time.Millisecond), user, user, user, channel, text)
+}
+
+func texts(res Result) []string {
+	s := []string{}
+	for _, m := range res.Matches {
+		s = append(s, m.Text)
+	}
+	return s
+}
+
+func TestArchive_Search(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	a := openTest(t, Options{Search: true}, &now)
+	write(t, a,
+		privmsg("test", "foo", "check out https://example.com/clip", now.Add(-time.Hour*30)),
+		privmsg("test", "bar", "Example of a message", now.Add(-time.Hour*2)),
+		privmsg("other", "foo", "hello from the other channel", now.Add(-time.Hour)),
+		privmsg("test", "foo", "hello again, see example.com", now.Add(-time.Minute)),
+		"@ban-duration=600 :tmi.twitch.tv CLEARCHAT #test :foo",
+	)
+
+	tests := []struct {
+		name     string
+		query    Query
+		count    int
+		expected []string
+	}{
+		{"user", Query{Username: "FOO"}, 3, []string{"hello again, see example.com", "hello from the other channel", "check out https://example.com/clip"}},
+		{"channel and user", Query{Channel: "test", Username: "@foo"}, 2, []string{"hello again, see example.com", "check out https://example.com/clip"}},
+		{"link", Query{Text: "example.com"}, 2, []string{"hello again, see example.com", "check out https://example.com/clip"}},
+		{"words", Query{Text: "HELLO channel"}, 1, []string{"hello from the other channel"}},
+		{"whole words", Query{Text: "exam"}, 0, []string{}},
+		{"time range", Query{From: now.Add(-time.Hour * 3), To: now.Add(-time.Minute * 30)}, 2, []string{"hello from the other channel", "Example of a message"}},
+		{"channels", Query{Text: "hello", Channels: []string{"test"}}, 1, []string{"hello again, see example.com"}},
+		{"limit", Query{Text: "example", Limit: 1}, 3, []string{"hello again, see example.com"}},
+		{"unknown user", Query{Username: "nobody"}, 0, []string{}},
+	}
+	for _, tt := range tests {
+		res, err := a.Search(tt.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Count != tt.count || fmt.Sprint(texts(res)) != fmt.Sprint(tt.expected) {
+			t.Errorf("%s: expected %d matches %q, got %d matches %q", tt.name, tt.count, tt.expected, res.Count, texts(res))
+		}
+	}
+}
+
+func TestArchive_SearchLoadAndPrune(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	a := openTest(t, Options{Compress: true, RetentionDays: 2}, &now)
+	write(t, a, privmsg("test", "foo", "yesterday", now))
+	now = now.AddDate(0, 0, 1)
+	write(t, a, privmsg("test", "foo", "today", now))
+	a.Close()
+
+	// The previous day has been compressed, and both days are indexed when opened.
+	b, err := Open(Options{Dir: a.opts.Dir, RetentionDays: 2, Search: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	b.now = func() time.Time { return now }
+	res, _ := b.Search(Query{Username: "foo"})
+	if fmt.Sprint(texts(res)) != "[today yesterday]" {
+		t.Errorf("expected both days to be indexed, got %q", texts(res))
+	}
+
+	now = now.AddDate(0, 0, 1)
+	if err := b.Maintain(); err != nil {
+		t.Fatal(err)
+	}
+	res, _ = b.Search(Query{Username: "foo"})
+	if fmt.Sprint(texts(res)) != "[today]" {
+		t.Errorf("expected days outside the retention to be pruned, got %q", texts(res))
+	}
+	if res, _ := b.Search(Query{Text: "yesterday"}); res.Count != 0 {
+		t.Errorf("expected pruned messages not to match, got %d", res.Count)
+	}
+
+	if _, err := a.Search(Query{}); err != ErrSearchDisabled {
+		t.Errorf("expected %v, got %v", ErrSearchDisabled, err)
+	}
+}
+
+func TestArchive_SearchLoadTruncated(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	a := openTest(t, Options{}, &now)
+	write(t, a, privmsg("test", "foo", "before the crash", now))
+	a.Close()
+
+	// A crash can leave the last line half written.
+	path := filepath.Join(a.opts.Dir, "test", "2026-10-19."+string(FormatJSON))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2026-10-19T12:00:01Z","channel":"te`)
+	f.Close()
+
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	b, err := Open(Options{Dir: a.opts.Dir, Search: true, Log: logger})
+	if err != nil {
+		t.Fatalf("expected the truncated line to be skipped, got %v", err)
+	}
+	defer b.Close()
+	res, _ := b.Search(Query{Username: "foo"})
+	if fmt.Sprint(texts(res)) != "[before the crash]" {
+		t.Errorf("expected the complete lines to be indexed, got %q", texts(res))
+	}
+}
@@ -0,0 +1,75 @@
+package event
+
+import "sync"
+
+// Handler handles a published Event.
+type Handler func(e Event)
+
+type subscription struct {
+	handler Handler
+	types   map[Type]bool
+}
+
+// Bus is a publish/subscribe event bus.
+type Bus struct {
+	subs      map[int]*subscription
+	subsMutex *sync.RWMutex
+	next      int
+}
+
+// NewBus creates a new event Bus.
+func NewBus() *Bus {
+	return &Bus{
+		subs:      make(map[int]*subscription),
+		subsMutex: &sync.RWMutex{},
+	}
+}
+
+// Subscribe registers a handler that is called for every published
+// event of the given types. If no types are given, the handler receives
+// every event. The returned function removes the subscription.
+//
+// Handlers are called synchronously by Publish, so handlers that do
+// slow work should hand it off to another goroutine.
+func (b *Bus) Subscribe(h Handler, types ...Type) (unsubscribe func()) {
+	sub := &subscription{handler: h}
+	if len(types) > 0 {
+		sub.types = make(map[Type]bool)
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+
+	b.subsMutex.Lock()
+	id := b.next
+	b.next++
+	b.subs[id] = sub
+	b.subsMutex.Unlock()
+
+	return func() {
+		b.subsMutex.Lock()
+		delete(b.subs, id)
+		b.subsMutex.Unlock()
+	}
+}
+
+// Publish sends the event to all interested subscribers.
+// Publishing on a nil Bus is a no-op.
+func (b *Bus) Publish(e Event) {
+	if b == nil || e == nil {
+		return
+	}
+
+	b.subsMutex.RLock()
+	handlers := []Handler{}
+	for _, sub := range b.subs {
+		if sub.types == nil || sub.types[e.Type()] {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.subsMutex.RUnlock()
+
+	for _, h := range handlers {
+		h(e)
+	}
+}
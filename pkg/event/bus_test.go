@@ -0,0 +1,61 @@
+package event
+
+import "testing"
+
+func TestPublish(t *testing.T) {
+	b := NewBus()
+
+	received := []Event{}
+	b.Subscribe(func(e Event) {
+		received = append(received, e)
+	})
+
+	b.Publish(ChannelJoined{Channel: "channel"})
+	b.Publish(MessageSent{Channel: "channel", Text: "hi"})
+
+	if len(received) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(received))
+	}
+	if received[0].Type() != TypeChannelJoined {
+		t.Errorf("expected first event to be %s, got %s", TypeChannelJoined, received[0].Type())
+	}
+}
+
+func TestSubscribe_Types(t *testing.T) {
+	b := NewBus()
+
+	count := 0
+	b.Subscribe(func(e Event) {
+		if e.Type() != TypeMessageSent {
+			t.Errorf("handler unexpectedly received event of type %s", e.Type())
+		}
+		count++
+	}, TypeMessageSent)
+
+	b.Publish(ChannelJoined{Channel: "channel"})
+	b.Publish(MessageSent{Channel: "channel", Text: "hi"})
+
+	if count != 1 {
+		t.Errorf("expected handler to be called once, got %d", count)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	b := NewBus()
+
+	called := false
+	unsubscribe := b.Subscribe(func(e Event) {
+		called = true
+	})
+	unsubscribe()
+
+	b.Publish(ChannelJoined{Channel: "channel"})
+	if called {
+		t.Error("handler was called after unsubscribing")
+	}
+}
+
+func TestPublish_NilBus(t *testing.T) {
+	var b *Bus
+	b.Publish(ChannelJoined{Channel: "channel"})
+}
@@ -0,0 +1,300 @@
+package event
+
+import "time"
+
+// Type identifies the kind of an Event.
+type Type string
+
+// Event types published by the bot.
+const (
+	TypeMessageReceived        Type = "message_received"
+	TypeCommandExecuted        Type = "command_executed"
+	TypeCommandFailed          Type = "command_failed"
+	TypeCommandSkipped         Type = "command_skipped"
+	TypeModuleToggled          Type = "module_toggled"
+	TypeChannelJoined          Type = "channel_joined"
+	TypeConnectionStateChanged Type = "connection_state_changed"
+	TypeMessageSent            Type = "message_sent"
+	TypeSubscription           Type = "subscription"
+	TypeGiftSubscription       Type = "gift_subscription"
+	TypeMysteryGift            Type = "mystery_gift"
+	TypeRaid                   Type = "raid"
+	TypeCheer                  Type = "cheer"
+	TypeChannelRestricted      Type = "channel_restricted"
+)
+
+// Types are all of the event types published by the bot.
+var Types = []Type{
+	TypeMessageReceived,
+	TypeCommandExecuted,
+	TypeCommandFailed,
+	TypeCommandSkipped,
+	TypeModuleToggled,
+	TypeChannelJoined,
+	TypeConnectionStateChanged,
+	TypeMessageSent,
+	TypeSubscription,
+	TypeGiftSubscription,
+	TypeMysteryGift,
+	TypeRaid,
+	TypeCheer,
+	TypeChannelRestricted,
+}
+
+// Valid determines if t is one of the event types published by the bot.
+func (t Type) Valid() bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is something that happened within the bot.
+type Event interface {
+	// Type returns the type of the event.
+	Type() Type
+	// ChannelName returns the name of the channel that the event
+	// relates to, or an empty string if it is not channel specific.
+	ChannelName() string
+}
+
+// MessageReceived is published when a chat message is received in a channel.
+type MessageReceived struct {
+	Channel     string    `json:"channel"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"displayName"`
+	Text        string    `json:"text"`
+	Time        time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e MessageReceived) Type() Type { return TypeMessageReceived }
+
+// ChannelName implements Event.
+func (e MessageReceived) ChannelName() string { return e.Channel }
+
+// CommandExecuted is published when a command has finished executing.
+type CommandExecuted struct {
+	Channel  string        `json:"channel"`
+	Module   string        `json:"module"`
+	Command  string        `json:"command"`
+	Username string        `json:"username"`
+	Duration time.Duration `json:"duration"`
+	Time     time.Time     `json:"time"`
+}
+
+// Type implements Event.
+func (e CommandExecuted) Type() Type { return TypeCommandExecuted }
+
+// ChannelName implements Event.
+func (e CommandExecuted) ChannelName() string { return e.Channel }
+
+// CommandFailed is published when a command could not be executed.
+type CommandFailed struct {
+	Channel  string    `json:"channel"`
+	Module   string    `json:"module"`
+	Command  string    `json:"command"`
+	Username string    `json:"username"`
+	Error    string    `json:"error"`
+	Time     time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e CommandFailed) Type() Type { return TypeCommandFailed }
+
+// ChannelName implements Event.
+func (e CommandFailed) ChannelName() string { return e.Channel }
+
+// Reasons that a matched command is not executed.
+const (
+	SkippedNotAllowed = "not_allowed"
+	SkippedDisabled   = "disabled"
+	SkippedCooldown   = "cooldown"
+)
+
+// CommandSkipped is published when a message matches a command that is not
+// executed, because it is disabled, on cooldown or cannot be invoked the way
+// that the message was sent.
+type CommandSkipped struct {
+	Channel  string    `json:"channel"`
+	Module   string    `json:"module"`
+	Command  string    `json:"command"`
+	Username string    `json:"username"`
+	Reason   string    `json:"reason"`
+	Time     time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e CommandSkipped) Type() Type { return TypeCommandSkipped }
+
+// ChannelName implements Event.
+func (e CommandSkipped) ChannelName() string { return e.Channel }
+
+// ModuleToggled is published when a module, or a command within a module,
+// is enabled or disabled. Command is empty when the module itself was toggled.
+type ModuleToggled struct {
+	Channel string    `json:"channel"`
+	Module  string    `json:"module"`
+	Command string    `json:"command,omitempty"`
+	Enabled bool      `json:"enabled"`
+	Time    time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e ModuleToggled) Type() Type { return TypeModuleToggled }
+
+// ChannelName implements Event.
+func (e ModuleToggled) ChannelName() string { return e.Channel }
+
+// ChannelJoined is published when the bot joins a channel.
+type ChannelJoined struct {
+	Channel string    `json:"channel"`
+	Time    time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e ChannelJoined) Type() Type { return TypeChannelJoined }
+
+// ChannelName implements Event.
+func (e ChannelJoined) ChannelName() string { return e.Channel }
+
+// ConnectionState is the state of the connection to twitch.
+type ConnectionState string
+
+// Connection states.
+const (
+	Connecting   ConnectionState = "connecting"
+	Connected    ConnectionState = "connected"
+	Disconnected ConnectionState = "disconnected"
+)
+
+// ConnectionStateChanged is published when the connection to twitch changes state.
+type ConnectionStateChanged struct {
+	State ConnectionState `json:"state"`
+	Time  time.Time       `json:"time"`
+}
+
+// Type implements Event.
+func (e ConnectionStateChanged) Type() Type { return TypeConnectionStateChanged }
+
+// ChannelName implements Event.
+func (e ConnectionStateChanged) ChannelName() string { return "" }
+
+// MessageSent is published when the bot sends a message to a channel.
+// Wait is how long the message was queued for before it was sent.
+type MessageSent struct {
+	Channel string        `json:"channel"`
+	Text    string        `json:"text"`
+	Wait    time.Duration `json:"wait"`
+	Time    time.Time     `json:"time"`
+}
+
+// Type implements Event.
+func (e MessageSent) Type() Type { return TypeMessageSent }
+
+// ChannelName implements Event.
+func (e MessageSent) ChannelName() string { return e.Channel }
+
+// Subscription is published when a user subscribes or resubscribes to a channel.
+type Subscription struct {
+	Channel     string    `json:"channel"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"displayName"`
+	Plan        string    `json:"plan"`
+	Months      int       `json:"months"`
+	Streak      int       `json:"streak"`
+	Resub       bool      `json:"resub"`
+	Text        string    `json:"text"`
+	Time        time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e Subscription) Type() Type { return TypeSubscription }
+
+// ChannelName implements Event.
+func (e Subscription) ChannelName() string { return e.Channel }
+
+// GiftSubscription is published when a user gifts a subscription to another user.
+// Gifter fields are empty when the gift is anonymous.
+type GiftSubscription struct {
+	Channel              string    `json:"channel"`
+	Gifter               string    `json:"gifter"`
+	GifterDisplayName    string    `json:"gifterDisplayName"`
+	Recipient            string    `json:"recipient"`
+	RecipientDisplayName string    `json:"recipientDisplayName"`
+	Plan                 string    `json:"plan"`
+	Anonymous            bool      `json:"anonymous"`
+	Time                 time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e GiftSubscription) Type() Type { return TypeGiftSubscription }
+
+// ChannelName implements Event.
+func (e GiftSubscription) ChannelName() string { return e.Channel }
+
+// MysteryGift is published when a user gifts subscriptions to random users in a channel.
+// It is followed by a GiftSubscription for each of the Count gifted subscriptions.
+type MysteryGift struct {
+	Channel           string    `json:"channel"`
+	Gifter            string    `json:"gifter"`
+	GifterDisplayName string    `json:"gifterDisplayName"`
+	Count             int       `json:"count"`
+	Plan              string    `json:"plan"`
+	Anonymous         bool      `json:"anonymous"`
+	Time              time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e MysteryGift) Type() Type { return TypeMysteryGift }
+
+// ChannelName implements Event.
+func (e MysteryGift) ChannelName() string { return e.Channel }
+
+// Raid is published when another channel raids a channel.
+type Raid struct {
+	Channel     string    `json:"channel"`
+	Raider      string    `json:"raider"`
+	DisplayName string    `json:"displayName"`
+	Viewers     int       `json:"viewers"`
+	Time        time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e Raid) Type() Type { return TypeRaid }
+
+// ChannelName implements Event.
+func (e Raid) ChannelName() string { return e.Channel }
+
+// Cheer is published when a user cheers bits in a channel.
+type Cheer struct {
+	Channel     string    `json:"channel"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"displayName"`
+	Bits        int       `json:"bits"`
+	Text        string    `json:"text"`
+	Time        time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e Cheer) Type() Type { return TypeCheer }
+
+// ChannelName implements Event.
+func (e Cheer) ChannelName() string { return e.Channel }
+
+// ChannelRestricted is published when twitch stops the bot from chatting in a channel,
+// for example because it was timed out or banned. Until is zero for permanent restrictions.
+type ChannelRestricted struct {
+	Channel string    `json:"channel"`
+	Reason  string    `json:"reason"`
+	Until   time.Time `json:"until"`
+	Time    time.Time `json:"time"`
+}
+
+// Type implements Event.
+func (e ChannelRestricted) Type() Type { return TypeChannelRestricted }
+
+// ChannelName implements Event.
+func (e ChannelRestricted) ChannelName() string { return e.Channel }
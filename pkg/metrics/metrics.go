@@ -0,0 +1,210 @@
+// Package metrics exposes the bot's metrics in the Prometheus format.
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+const namespace = "roastedbot"
+
+var commandLabels = []string{"channel", "module", "command"}
+
+// Metrics records the events published by a client as Prometheus metrics.
+type Metrics struct {
+	client   *twitch.Client
+	registry *prometheus.Registry
+
+	messagesReceived *prometheus.CounterVec
+	messagesSent     *prometheus.CounterVec
+	commandsMatched  *prometheus.CounterVec
+	commandsExecuted *prometheus.CounterVec
+	commandsFailed   *prometheus.CounterVec
+	commandsCooldown *prometheus.CounterVec
+	commandsDisabled *prometheus.CounterVec
+	commandDuration  *prometheus.HistogramVec
+	queueWait        *prometheus.HistogramVec
+	reconnects       prometheus.Counter
+
+	connected      bool
+	connections    int
+	connectedMutex *sync.Mutex
+}
+
+// New creates Metrics for the client. The metrics are not
+// recorded until Subscribe is called.
+func New(client *twitch.Client) *Metrics {
+	m := &Metrics{
+		client:         client,
+		registry:       prometheus.NewRegistry(),
+		connectedMutex: &sync.Mutex{},
+
+		messagesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_received_total",
+			Help:      "Chat messages received, by channel.",
+		}, []string{"channel"}),
+		messagesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_sent_total",
+			Help:      "Chat messages sent by the bot, by channel.",
+		}, []string{"channel"}),
+		commandsMatched: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_matched_total",
+			Help:      "Messages that matched a command, whether or not it was executed.",
+		}, commandLabels),
+		commandsExecuted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_executed_total",
+			Help:      "Commands that were executed successfully.",
+		}, commandLabels),
+		commandsFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_failed_total",
+			Help:      "Commands that returned an error.",
+		}, commandLabels),
+		commandsCooldown: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_cooldown_total",
+			Help:      "Commands that were not executed because they were on cooldown.",
+		}, commandLabels),
+		commandsDisabled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_disabled_total",
+			Help:      "Commands that were not executed because they were disabled.",
+		}, commandLabels),
+		commandDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "command_duration_seconds",
+			Help:      "How long commands took to execute.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, commandLabels),
+		queueWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "outgoing_queue_wait_seconds",
+			Help:      "How long messages waited in a channel's outgoing queue before they were sent.",
+			Buckets:   []float64{.1, .5, 1, 1.5, 2.5, 5, 10, 30, 60, 300},
+		}, []string{"channel"}),
+		reconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reconnects_total",
+			Help:      "Times the bot has reconnected to twitch after its first connection.",
+		}),
+	}
+	m.registry.MustRegister(
+		m.messagesReceived,
+		m.messagesSent,
+		m.commandsMatched,
+		m.commandsExecuted,
+		m.commandsFailed,
+		m.commandsCooldown,
+		m.commandsDisabled,
+		m.commandDuration,
+		m.queueWait,
+		m.reconnects,
+		&channelCollector{m},
+	)
+	return m
+}
+
+// Handler serves the metrics to Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Subscribe starts recording the events on the client's event bus.
+// The returned function stops recording them.
+func (m *Metrics) Subscribe() (unsubscribe func()) {
+	return m.client.Events.Subscribe(
+		m.handle,
+		event.TypeMessageReceived,
+		event.TypeMessageSent,
+		event.TypeCommandExecuted,
+		event.TypeCommandFailed,
+		event.TypeCommandSkipped,
+		event.TypeConnectionStateChanged,
+	)
+}
+
+func (m *Metrics) handle(e event.Event) {
+	switch e := e.(type) {
+	case event.MessageReceived:
+		m.messagesReceived.WithLabelValues(e.Channel).Inc()
+	case event.MessageSent:
+		m.messagesSent.WithLabelValues(e.Channel).Inc()
+		m.queueWait.WithLabelValues(e.Channel).Observe(e.Wait.Seconds())
+	case event.CommandExecuted:
+		m.commandsMatched.WithLabelValues(e.Channel, e.Module, e.Command).Inc()
+		m.commandsExecuted.WithLabelValues(e.Channel, e.Module, e.Command).Inc()
+		m.commandDuration.WithLabelValues(e.Channel, e.Module, e.Command).Observe(e.Duration.Seconds())
+	case event.CommandFailed:
+		m.commandsMatched.WithLabelValues(e.Channel, e.Module, e.Command).Inc()
+		m.commandsFailed.WithLabelValues(e.Channel, e.Module, e.Command).Inc()
+	case event.CommandSkipped:
+		m.commandsMatched.WithLabelValues(e.Channel, e.Module, e.Command).Inc()
+		switch e.Reason {
+		case event.SkippedCooldown:
+			m.commandsCooldown.WithLabelValues(e.Channel, e.Module, e.Command).Inc()
+		case event.SkippedDisabled:
+			m.commandsDisabled.WithLabelValues(e.Channel, e.Module, e.Command).Inc()
+		}
+	case event.ConnectionStateChanged:
+		m.connectedMutex.Lock()
+		m.connected = e.State == event.Connected
+		if m.connected {
+			m.connections++
+			if m.connections > 1 {
+				m.reconnects.Inc()
+			}
+		}
+		m.connectedMutex.Unlock()
+	}
+}
+
+func (m *Metrics) isConnected() bool {
+	m.connectedMutex.Lock()
+	defer m.connectedMutex.Unlock()
+	return m.connected
+}
+
+var (
+	queueLengthDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "outgoing_queue_length"),
+		"Messages waiting in a channel's outgoing queue.",
+		[]string{"channel"}, nil,
+	)
+	connectedChannelsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "connected_channels"),
+		"Channels that the bot is in while it is connected to twitch.",
+		nil, nil,
+	)
+)
+
+// channelCollector reads the state of the client's channels when metrics are collected.
+type channelCollector struct {
+	m *Metrics
+}
+
+func (c *channelCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueLengthDesc
+	ch <- connectedChannelsDesc
+}
+
+func (c *channelCollector) Collect(ch chan<- prometheus.Metric) {
+	channels := c.m.client.Channels()
+	for _, channel := range channels {
+		ch <- prometheus.MustNewConstMetric(queueLengthDesc, prometheus.GaugeValue, float64(channel.QueueLength()), channel.Name)
+	}
+	connected := 0
+	if c.m.isConnected() {
+		connected = len(channels)
+	}
+	ch <- prometheus.MustNewConstMetric(connectedChannelsDesc, prometheus.GaugeValue, float64(connected))
+}
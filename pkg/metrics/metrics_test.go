@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	tirc "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	b, err := ioutil.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func expectLines(t *testing.T, body string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("expected metrics to contain %q", line)
+		}
+	}
+}
+
+func TestCommands(t *testing.T) {
+	client := twitch.NewClient("bot", tirc.NewClient("bot", "oauth:token"))
+	m := New(client)
+	m.Subscribe()
+
+	labels := `{channel="test",command="ping",module="general"}`
+	client.Events.Publish(event.CommandExecuted{Channel: "test", Module: "general", Command: "ping", Duration: time.Millisecond * 20})
+	client.Events.Publish(event.CommandFailed{Channel: "test", Module: "general", Command: "ping"})
+	client.Events.Publish(event.CommandSkipped{Channel: "test", Module: "general", Command: "ping", Reason: event.SkippedCooldown})
+	client.Events.Publish(event.CommandSkipped{Channel: "test", Module: "general", Command: "ping", Reason: event.SkippedDisabled})
+	client.Events.Publish(event.CommandSkipped{Channel: "test", Module: "general", Command: "ping", Reason: event.SkippedNotAllowed})
+
+	expectLines(t, scrape(t, m),
+		"roastedbot_commands_matched_total"+labels+" 5",
+		"roastedbot_commands_executed_total"+labels+" 1",
+		"roastedbot_commands_failed_total"+labels+" 1",
+		"roastedbot_commands_cooldown_total"+labels+" 1",
+		"roastedbot_commands_disabled_total"+labels+" 1",
+		"roastedbot_command_duration_seconds_count"+labels+" 1",
+		`roastedbot_command_duration_seconds_bucket{channel="test",command="ping",module="general",le="0.025"} 1`,
+	)
+}
+
+func TestMessagesAndConnection(t *testing.T) {
+	client := twitch.NewClient("bot", tirc.NewClient("bot", "oauth:token"))
+	if err := client.AddChannel("test"); err != nil {
+		t.Fatal(err)
+	}
+	m := New(client)
+	m.Subscribe()
+
+	client.Events.Publish(event.MessageReceived{Channel: "test"})
+	client.Events.Publish(event.MessageReceived{Channel: "test"})
+	client.Events.Publish(event.MessageSent{Channel: "test", Wait: time.Second * 2})
+	expectLines(t, scrape(t, m),
+		`roastedbot_messages_received_total{channel="test"} 2`,
+		`roastedbot_messages_sent_total{channel="test"} 1`,
+		`roastedbot_outgoing_queue_wait_seconds_bucket{channel="test",le="1.5"} 0`,
+		`roastedbot_outgoing_queue_wait_seconds_bucket{channel="test",le="2.5"} 1`,
+		`roastedbot_outgoing_queue_length{channel="test"} 0`,
+		"roastedbot_connected_channels 0",
+		"roastedbot_reconnects_total 0",
+	)
+
+	client.Events.Publish(event.ConnectionStateChanged{State: event.Connected})
+	client.Events.Publish(event.ConnectionStateChanged{State: event.Disconnected})
+	client.Events.Publish(event.ConnectionStateChanged{State: event.Connected})
+	expectLines(t, scrape(t, m),
+		"roastedbot_connected_channels 1",
+		"roastedbot_reconnects_total 1",
+	)
+}
@@ -0,0 +1,201 @@
+// Package store keeps the state of the bot's channels, such as which modules
+// and commands are enabled, their cooldowns and the channels' custom commands,
+// so that the changes made while the bot is running survive a restart.
+//
+// The state is stored as a JSON file with a version, so that state written by
+// a newer version of the bot is rejected rather than misread.
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/channelname"
+)
+
+// Version is the version of the state written by this version of the bot.
+const Version = 1
+
+// ErrVersion is returned when the state was written by a newer version of the bot.
+var ErrVersion = errors.New("state was written by a newer version of the bot")
+
+// State is the state of the bot's channels.
+type State struct {
+	Version  int       `json:"version"`
+	Channels []Channel `json:"channels"`
+}
+
+// Channel is the state of a channel.
+type Channel struct {
+	Name           string   `json:"name"`
+	ReplyMode      string   `json:"replyMode,omitempty"`
+	RestrictedMode string   `json:"restrictedMode,omitempty"`
+	Modules        []Module `json:"modules,omitempty"`
+}
+
+// Module is the state of a module in a channel.
+type Module struct {
+	Name     string    `json:"name"`
+	Enabled  bool      `json:"enabled"`
+	Commands []Command `json:"commands,omitempty"`
+}
+
+// Command is the state of a command in a module.
+type Command struct {
+	Name     string   `json:"name"`
+	Enabled  bool     `json:"enabled"`
+	Cooldown Duration `json:"cooldown"`
+	// Response is the fixed message sent by a custom command.
+	Response string `json:"response,omitempty"`
+}
+
+// Duration is a time.Duration that is written as a string such as "30s".
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// New creates an empty State of the current version.
+func New() *State {
+	return &State{Version: Version, Channels: []Channel{}}
+}
+
+// Validate checks that the state can be restored.
+func (s *State) Validate() error {
+	var errs []error
+	seen := make(map[string]bool)
+	for _, ch := range s.Channels {
+		if !channelname.Valid(ch.Name) {
+			errs = append(errs, fmt.Errorf("invalid channel name '%s'", ch.Name))
+			continue
+		}
+		if seen[ch.Name] {
+			errs = append(errs, fmt.Errorf("channel '%s' is listed more than once", ch.Name))
+		}
+		seen[ch.Name] = true
+		modules := make(map[string]bool)
+		for _, m := range ch.Modules {
+			if m.Name == "" || modules[m.Name] {
+				errs = append(errs, fmt.Errorf("channel '%s': module '%s' is unnamed or listed more than once", ch.Name, m.Name))
+			}
+			modules[m.Name] = true
+			commands := make(map[string]bool)
+			for _, c := range m.Commands {
+				if c.Name == "" || commands[c.Name] {
+					errs = append(errs, fmt.Errorf("channel '%s': command '%s' in module '%s' is unnamed or listed more than once", ch.Name, c.Name, m.Name))
+				}
+				commands[c.Name] = true
+				if c.Cooldown < 0 {
+					errs = append(errs, fmt.Errorf("channel '%s': cooldown of command '%s' in module '%s' cannot be negative", ch.Name, c.Name, m.Name))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Read reads state from r.
+func Read(r io.Reader) (*State, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state: %w", err)
+	}
+	return decode(b)
+}
+
+// Write writes the state to w.
+func (s *State) Write(w io.Writer) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// Load reads the state stored at path. It returns an empty State if the file does not exist.
+func Load(path string) (*State, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read state: %w", err)
+	}
+	s, err := decode(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save stores the state at path, replacing the file at once
+// so that partly written state is never read.
+func Save(path string, s *State) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write state: %w", err)
+	}
+	err = s.Write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("unable to write state: %w", err)
+	}
+	return nil
+}
+
+// decode parses state, checking that it was written in the current version.
+func decode(b []byte) (*State, error) {
+	var header struct {
+		Version json.Number `json:"version"`
+	}
+	if err := json.Unmarshal(b, &header); err != nil {
+		return nil, fmt.Errorf("invalid state: %v", err)
+	}
+	version, err := header.Version.Int64()
+	if err != nil || version < 1 {
+		return nil, errors.New("invalid state: missing or invalid version")
+	}
+	if version > Version {
+		return nil, fmt.Errorf("version %d: %w", version, ErrVersion)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	s := New()
+	if err := d.Decode(s); err != nil {
+		return nil, fmt.Errorf("invalid state: %v", err)
+	}
+	if s.Channels == nil {
+		s.Channels = []Channel{}
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid state: %w", err)
+	}
+	return s, nil
+}
@@ -0,0 +1,61 @@
+package store
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if s, err := Load(path); err != nil || s.Version != Version || len(s.Channels) != 0 {
+		t.Fatalf("expected empty state for a missing file, got %+v and %v", s, err)
+	}
+
+	s := New()
+	s.Channels = append(s.Channels, Channel{
+		Name:      "foo",
+		ReplyMode: "action",
+		Modules: []Module{{Name: "custom", Enabled: true, Commands: []Command{
+			{Name: "discord", Enabled: true, Cooldown: Duration(30 * time.Second), Response: "join us"},
+		}}},
+	})
+	if err := Save(path, s); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(path)
+	if !strings.Contains(string(b), `"cooldown": "30s"`) {
+		t.Errorf("expected the cooldown to be written as a duration, got %s", b)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, s) {
+		t.Errorf("expected %+v, got %+v", s, loaded)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no version":        `{"channels": []}`,
+		"invalid version":   `{"version": 1.5, "channels": []}`,
+		"unknown field":     `{"version": 1, "channels": [], "extra": true}`,
+		"invalid channel":   `{"version": 1, "channels": [{"name": "#foo"}]}`,
+		"duplicate channel": `{"version": 1, "channels": [{"name": "foo"}, {"name": "foo"}]}`,
+		"negative cooldown": `{"version": 1, "channels": [{"name": "foo", "modules": [{"name": "general", "commands": [{"name": "uptime", "cooldown": "-1s"}]}]}]}`,
+	}
+	for name, input := range tests {
+		if _, err := Read(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error, got nil", name)
+		}
+	}
+
+	if _, err := Read(strings.NewReader(`{"version": 99, "channels": []}`)); !errors.Is(err, ErrVersion) {
+		t.Errorf("expected ErrVersion, got %v", err)
+	}
+}
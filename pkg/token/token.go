@@ -0,0 +1,341 @@
+// Package token keeps the bot's chat token valid. It validates the token
+// against twitch, refreshes it before it expires and stores the new token
+// so that it is used the next time the bot starts.
+package token
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Twitch's endpoints for validating and refreshing tokens.
+const (
+	TwitchValidateURL = "https://id.twitch.tv/oauth2/validate"
+	TwitchTokenURL    = "https://id.twitch.tv/oauth2/token"
+)
+
+// DefaultInterval is how often a started Manager validates the token,
+// which is as often as twitch requires.
+const DefaultInterval = time.Hour
+
+// ErrInvalid is returned when twitch rejects a token.
+var ErrInvalid = errors.New("token is invalid or has expired")
+
+// ErrNoRefresh is returned when a token cannot be refreshed because the
+// refresh token or client credentials are not configured.
+var ErrNoRefresh = errors.New("token cannot be refreshed without a refresh token, client id and client secret")
+
+// Token is a chat token and the refresh token that renews it.
+type Token struct {
+	// AccessToken is the token without the "oauth:" prefix used by chat.
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	// Expires is when the access token expires. It is zero if it is unknown
+	// or the token does not expire.
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+// Chat returns the token in the "oauth:<token>" form used to log in to chat.
+func (t Token) Chat() string {
+	return "oauth:" + t.AccessToken
+}
+
+// stored is the contents of the token file.
+type stored struct {
+	Token
+	// Source identifies the configured token that the stored token was
+	// refreshed from, so that the file is ignored once the configuration
+	// has a different token.
+	Source string `json:"source"`
+}
+
+// source returns the hash that identifies a configured token.
+func source(t Token) string {
+	sum := sha256.Sum256([]byte(strings.TrimPrefix(t.AccessToken, "oauth:") + "\n" + t.RefreshToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// Validation is what twitch knows about a valid token.
+type Validation struct {
+	ClientID string   `json:"client_id"`
+	Login    string   `json:"login"`
+	Scopes   []string `json:"scopes"`
+	UserID   string   `json:"user_id"`
+	// ExpiresIn is the number of seconds until the token expires, or zero if it does not expire.
+	ExpiresIn int `json:"expires_in"`
+}
+
+// Options configures a Manager. The URLs default to twitch's endpoints
+// but can point at any server that implements the same API.
+type Options struct {
+	ClientID     string
+	ClientSecret string
+	ValidateURL  string
+	TokenURL     string
+	// Path is the file that the token is stored in. A token stored in the
+	// file is used in place of the one given to New, as long as it was
+	// refreshed from that token. Tokens are only kept in memory if it is empty.
+	Path string
+	// Interval is how often a started Manager validates the token. Defaults to DefaultInterval.
+	Interval time.Duration
+	Log      *log.Logger
+}
+
+// Manager validates and refreshes a token.
+type Manager struct {
+	client    *http.Client
+	mutex     *sync.Mutex
+	now       func() time.Time
+	onRefresh func(t Token)
+	opts      Options
+	source    string
+	token     Token
+}
+
+// New creates a Manager for the token, or for the token stored at the path of
+// the options if it was refreshed from the same token.
+func New(t Token, opts Options) (*Manager, error) {
+	if opts.ValidateURL == "" {
+		opts.ValidateURL = TwitchValidateURL
+	}
+	if opts.TokenURL == "" {
+		opts.TokenURL = TwitchTokenURL
+	}
+	if opts.Interval == 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Log == nil {
+		opts.Log = log.StandardLogger()
+	}
+	configured := source(t)
+	logger := opts.Log.WithField("path", opts.Path)
+	if opts.Path != "" {
+		b, err := ioutil.ReadFile(opts.Path)
+		switch {
+		case err == nil:
+			var s stored
+			if err := json.Unmarshal(b, &s); err != nil {
+				return nil, fmt.Errorf("unable to read token file %s: %v", opts.Path, err)
+			}
+			if s.Source == configured {
+				logger.Info("using the refreshed chat token from the token file")
+				t = s.Token
+			} else {
+				logger.Info("the configured chat token has changed, ignoring the token file")
+			}
+		case os.IsNotExist(err):
+			logger.Info("using the configured chat token")
+		default:
+			return nil, fmt.Errorf("unable to read token file: %w", err)
+		}
+	}
+	t.AccessToken = strings.TrimPrefix(t.AccessToken, "oauth:")
+	if t.AccessToken == "" {
+		return nil, errors.New("token is not set")
+	}
+	return &Manager{
+		client: &http.Client{Timeout: time.Second * 10},
+		mutex:  &sync.Mutex{},
+		now:    time.Now,
+		opts:   opts,
+		source: configured,
+		token:  t,
+	}, nil
+}
+
+// Token returns the current token.
+func (m *Manager) Token() Token {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.token
+}
+
+// OnRefresh sets the callback that is called with the new token after it is refreshed.
+func (m *Manager) OnRefresh(callback func(t Token)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.onRefresh = callback
+}
+
+// CanRefresh determines if the Manager has what it needs to refresh the token.
+func (m *Manager) CanRefresh() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.token.RefreshToken != "" && m.opts.ClientID != "" && m.opts.ClientSecret != ""
+}
+
+// Validate asks twitch whether the current token is valid. It returns
+// ErrInvalid if it is not.
+func (m *Manager) Validate(ctx context.Context) (Validation, error) {
+	req, err := http.NewRequest(http.MethodGet, m.opts.ValidateURL, nil)
+	if err != nil {
+		return Validation{}, err
+	}
+	req.Header.Set("Authorization", "OAuth "+m.Token().AccessToken)
+
+	var v Validation
+	if err := m.do(req.WithContext(ctx), &v); err != nil {
+		return Validation{}, fmt.Errorf("unable to validate token: %w", err)
+	}
+	if v.ExpiresIn > 0 {
+		m.mutex.Lock()
+		m.token.Expires = m.now().Add(time.Duration(v.ExpiresIn) * time.Second)
+		m.mutex.Unlock()
+	}
+	return v, nil
+}
+
+// Refresh gets a new token using the refresh token and client credentials,
+// stores it and calls the OnRefresh callback.
+func (m *Manager) Refresh(ctx context.Context) (Token, error) {
+	if !m.CanRefresh() {
+		return Token{}, ErrNoRefresh
+	}
+	v := url.Values{}
+	v.Set("client_id", m.opts.ClientID)
+	v.Set("client_secret", m.opts.ClientSecret)
+	v.Set("grant_type", "refresh_token")
+	v.Set("refresh_token", m.Token().RefreshToken)
+	req, err := http.NewRequest(http.MethodPost, m.opts.TokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := m.do(req.WithContext(ctx), &resp); err != nil {
+		return Token{}, fmt.Errorf("unable to refresh token: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return Token{}, errors.New("unable to refresh token: no access token in response")
+	}
+
+	m.mutex.Lock()
+	t := Token{AccessToken: resp.AccessToken, RefreshToken: resp.RefreshToken}
+	if t.RefreshToken == "" {
+		t.RefreshToken = m.token.RefreshToken
+	}
+	if resp.ExpiresIn > 0 {
+		t.Expires = m.now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	m.token = t
+	callback := m.onRefresh
+	m.mutex.Unlock()
+
+	if err := m.save(t); err != nil {
+		m.opts.Log.WithField("error", err).Error("failed to store refreshed token")
+	}
+	if callback != nil {
+		callback(t)
+	}
+	return t, nil
+}
+
+// Check validates the token, refreshing it if it is invalid or will expire
+// before the next check. It returns the validation of the resulting token.
+func (m *Manager) Check(ctx context.Context) (Validation, error) {
+	v, err := m.Validate(ctx)
+	switch {
+	case errors.Is(err, ErrInvalid):
+	case err != nil:
+		return Validation{}, err
+	case v.ExpiresIn == 0 || time.Duration(v.ExpiresIn)*time.Second > m.opts.Interval+time.Minute:
+		return v, nil
+	}
+	if _, refreshErr := m.Refresh(ctx); refreshErr != nil {
+		if err != nil {
+			return Validation{}, fmt.Errorf("%w, and %w", err, refreshErr)
+		}
+		// The token is still valid for now.
+		m.opts.Log.WithField("error", refreshErr).Warn("token expires soon but could not be refreshed")
+		return v, nil
+	}
+	return m.Validate(ctx)
+}
+
+// Start checks the token at the Manager's interval until the returned function is called.
+func (m *Manager) Start() (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(m.opts.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				if _, err := m.Check(ctx); err != nil {
+					m.opts.Log.WithField("error", err).Error("failed to check chat token")
+				}
+				cancel()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// save writes the token to the Manager's path, replacing the file at once
+// so that a partly written token is never read.
+func (m *Manager) save(t Token) error {
+	if m.opts.Path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(stored{Token: t, Source: m.source}, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(m.opts.Path), filepath.Base(m.opts.Path)+".*")
+	if err != nil {
+		return fmt.Errorf("unable to write token file: %w", err)
+	}
+	_, err = f.Write(append(b, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), m.opts.Path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("unable to write token file: %w", err)
+	}
+	return nil
+}
+
+func (m *Manager) do(req *http.Request, v interface{}) error {
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return ErrInvalid
+	case resp.StatusCode == http.StatusBadRequest && req.Method == http.MethodPost:
+		// Twitch rejects refresh tokens that are invalid with a bad request.
+		return ErrInvalid
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
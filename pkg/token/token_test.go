@@ -0,0 +1,163 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// fakeTwitch stands in for twitch's validate and token endpoints.
+type fakeTwitch struct {
+	mutex     *sync.Mutex
+	valid     map[string]int
+	refreshed int
+}
+
+func newFakeTwitch(t *testing.T) (*fakeTwitch, *httptest.Server) {
+	f := &fakeTwitch{mutex: &sync.Mutex{}, valid: map[string]int{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate", func(w http.ResponseWriter, r *http.Request) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		expiresIn, ok := f.valid[r.Header.Get("Authorization")]
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(Validation{ClientID: "client", Login: "bot", ExpiresIn: expiresIn})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		if r.PostFormValue("grant_type") != "refresh_token" || r.PostFormValue("refresh_token") != "refresh" ||
+			r.PostFormValue("client_id") != "client" || r.PostFormValue("client_secret") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.refreshed++
+		f.valid["OAuth new"] = 14400
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "new",
+			"refresh_token": "refresh",
+			"expires_in":    14400,
+		})
+	})
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return f, s
+}
+
+func newTestManager(t *testing.T, s *httptest.Server, path string) *Manager {
+	t.Helper()
+	return newTestManagerFor(t, Token{AccessToken: "oauth:old", RefreshToken: "refresh"}, s, path)
+}
+
+func newTestManagerFor(t *testing.T, token Token, s *httptest.Server, path string) *Manager {
+	t.Helper()
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	m, err := New(token, Options{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		ValidateURL:  s.URL + "/validate",
+		TokenURL:     s.URL + "/token",
+		Path:         path,
+		Log:          logger,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestManager_CheckValid(t *testing.T) {
+	f, s := newFakeTwitch(t)
+	f.valid["OAuth old"] = 14400
+	m := newTestManager(t, s, "")
+
+	v, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Login != "bot" || f.refreshed != 0 {
+		t.Errorf("expected a valid token for bot without refreshing, got %+v and %d refreshes", v, f.refreshed)
+	}
+	if m.Token().Expires.IsZero() {
+		t.Error("expected the expiry to be recorded")
+	}
+}
+
+func TestManager_CheckRefreshes(t *testing.T) {
+	tests := map[string]func(f *fakeTwitch){
+		"expired":      func(f *fakeTwitch) {},
+		"expires soon": func(f *fakeTwitch) { f.valid["OAuth old"] = 60 },
+	}
+	for name, setup := range tests {
+		f, s := newFakeTwitch(t)
+		setup(f)
+		path := filepath.Join(t.TempDir(), "token.json")
+		m := newTestManager(t, s, path)
+		var refreshed Token
+		m.OnRefresh(func(t Token) { refreshed = t })
+
+		if _, err := m.Check(context.Background()); err != nil {
+			t.Errorf("%s: expected no error, got %v", name, err)
+			continue
+		}
+		if m.Token().AccessToken != "new" || refreshed.Chat() != "oauth:new" {
+			t.Errorf("%s: expected the new token, got %+v", name, m.Token())
+		}
+
+		// The stored token is used in place of the one given to New,
+		// until the configured token changes.
+		if m := newTestManager(t, s, path); m.Token().AccessToken != "new" {
+			t.Errorf("%s: expected the stored token, got %s", name, m.Token().AccessToken)
+		}
+		changed := newTestManagerFor(t, Token{AccessToken: "oauth:other", RefreshToken: "refresh"}, s, path)
+		if changed.Token().AccessToken != "other" {
+			t.Errorf("%s: expected the changed configured token, got %s", name, changed.Token().AccessToken)
+		}
+	}
+}
+
+func TestManager_RefreshRejected(t *testing.T) {
+	_, s := newFakeTwitch(t)
+	m, err := New(Token{AccessToken: "old", RefreshToken: "wrong"}, Options{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		ValidateURL:  s.URL + "/validate",
+		TokenURL:     s.URL + "/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Check(context.Background()); !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected ErrInvalid, got %v", err)
+	}
+}
+
+func TestManager_NoRefresh(t *testing.T) {
+	_, s := newFakeTwitch(t)
+	m, err := New(Token{AccessToken: "old"}, Options{ValidateURL: s.URL + "/validate"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Refresh(context.Background()); !errors.Is(err, ErrNoRefresh) {
+		t.Errorf("expected ErrNoRefresh, got %v", err)
+	}
+	if _, err := m.Check(context.Background()); !errors.Is(err, ErrNoRefresh) || !errors.Is(err, ErrInvalid) {
+		t.Errorf("expected the token to be invalid and unable to refresh, got %v", err)
+	}
+	if m.Token().Expires.After(time.Now()) {
+		t.Error("expected an invalid token to have no expiry")
+	}
+}
@@ -1,8 +1,11 @@
 package twitch
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Channel represents a twitch channel.
@@ -11,6 +14,8 @@ type Channel struct {
 	enabledModulesMutex *sync.Mutex
 	modules             map[string]*Module
 	modulesMutex        *sync.Mutex
+	outbox              *outbox
+	state               *channelState
 
 	Name string `json:"name"`
 }
@@ -21,6 +26,8 @@ func newChannel(name string) *Channel {
 		enabledModulesMutex: &sync.Mutex{},
 		modules:             make(map[string]*Module),
 		modulesMutex:        &sync.Mutex{},
+		outbox:              newOutbox(),
+		state:               newChannelState(),
 		Name:                name,
 	}
 }
@@ -41,7 +48,7 @@ func (ch *Channel) AddModule(name string) (*Module, error) {
 	ch.modulesMutex.Lock()
 	defer ch.modulesMutex.Unlock()
 	if _, ok := ch.modules[name]; ok {
-		return nil, fmt.Errorf("module '%s' already exists in channel '%s'", name, ch.Name)
+		return nil, fmt.Errorf("module '%s' in channel '%s': %w", name, ch.Name, ErrExists)
 	}
 	ch.modules[name] = newModule(name)
 	return ch.modules[name], nil
@@ -53,18 +60,18 @@ func (ch *Channel) EnableCommand(module, command string) error {
 	defer ch.modulesMutex.Unlock()
 	m, ok := ch.modules[module]
 	if !ok {
-		return fmt.Errorf("module with name '%s' does not exist in channel '%s'", module, ch.Name)
+		return fmt.Errorf("module '%s' in channel '%s': %w", module, ch.Name, ErrNotFound)
 	}
 	return m.EnableCommand(command)
 }
 
 // EnableModule enables a module in the channel.
 func (ch *Channel) EnableModule(module string) error {
+	if _, err := ch.Module(module); err != nil {
+		return err
+	}
 	ch.enabledModulesMutex.Lock()
 	defer ch.enabledModulesMutex.Unlock()
-	if _, ok := ch.modules[module]; !ok {
-		return fmt.Errorf("module with name '%s' does not exist in channel '%s'", module, ch.Name)
-	}
 	ch.enabledModules[module] = true
 	return nil
 }
@@ -84,24 +91,24 @@ func (ch *Channel) DisableCommand(module, command string) error {
 	defer ch.modulesMutex.Unlock()
 	m, ok := ch.modules[module]
 	if !ok {
-		return fmt.Errorf("module with name '%s' does not exist in channel '%s'", module, ch.Name)
+		return fmt.Errorf("module '%s' in channel '%s': %w", module, ch.Name, ErrNotFound)
 	}
 	return m.DisableCommand(command)
 }
 
 // DisableModule disables a module in the channel.
 func (ch *Channel) DisableModule(module string) error {
+	if _, err := ch.Module(module); err != nil {
+		return err
+	}
 	ch.enabledModulesMutex.Lock()
 	defer ch.enabledModulesMutex.Unlock()
-	if _, ok := ch.modules[module]; !ok {
-		return fmt.Errorf("module with name '%s' does not exist in channel '%s'", module, ch.Name)
-	}
 	ch.enabledModules[module] = false
 	return nil
 }
 
 // IsModuleEnabled determines if the module is enabled.
-func (ch *Channel) isModuleEnabled(module string) bool {
+func (ch *Channel) IsModuleEnabled(module string) bool {
 	ch.enabledModulesMutex.Lock()
 	defer ch.enabledModulesMutex.Unlock()
 	enabled, ok := ch.enabledModules[module]
@@ -111,26 +118,145 @@ func (ch *Channel) isModuleEnabled(module string) bool {
 // MatchCommand returns the Command that is triggered by the given args,
 // as well as the module that it belongs to.
 func (ch *Channel) MatchCommand(args []string) (command *Command, module *Module) {
+	ch.modulesMutex.Lock()
+	defer ch.modulesMutex.Unlock()
 	for _, m := range ch.modules {
-		if !ch.isModuleEnabled(m.Name) {
+		if !ch.IsModuleEnabled(m.Name) {
 			continue
 		}
 
-		for _, c := range m.commands {
-			if !c.match(args[0]) {
-				continue
-			}
+		if c := m.match(args[0]); c != nil {
 			return c, m
 		}
 	}
 	return nil, nil
 }
 
+// Module returns the module with the given name.
+func (ch *Channel) Module(name string) (*Module, error) {
+	ch.modulesMutex.Lock()
+	defer ch.modulesMutex.Unlock()
+	m, ok := ch.modules[name]
+	if !ok {
+		return nil, fmt.Errorf("module '%s' in channel '%s': %w", name, ch.Name, ErrNotFound)
+	}
+	return m, nil
+}
+
 // Modules returns a list of all modules in the channel.
 func (ch *Channel) Modules() []Module {
+	ch.modulesMutex.Lock()
+	defer ch.modulesMutex.Unlock()
 	mods := []Module{}
 	for _, m := range ch.modules {
 		mods = append(mods, *m)
 	}
 	return mods
 }
+
+// RoomState returns the chat settings of the channel.
+func (ch *Channel) RoomState() RoomState {
+	ch.state.mutex.Lock()
+	defer ch.state.mutex.Unlock()
+	return ch.state.room
+}
+
+// UserState returns the bot's status in the channel.
+func (ch *Channel) UserState() UserState {
+	ch.state.mutex.Lock()
+	defer ch.state.mutex.Unlock()
+	return ch.state.user
+}
+
+// RestrictedMode returns what happens to messages that cannot be
+// sent because the channel is in emote-only or subs-only mode.
+func (ch *Channel) RestrictedMode() string {
+	ch.state.mutex.Lock()
+	defer ch.state.mutex.Unlock()
+	return ch.state.restrictedMode
+}
+
+// SetRestrictedMode sets what happens to messages that cannot be sent
+// because the channel is in emote-only or subs-only mode.
+func (ch *Channel) SetRestrictedMode(mode string) error {
+	if mode != RestrictedQueue && mode != RestrictedDrop {
+		return fmt.Errorf("invalid restricted mode '%s'", mode)
+	}
+	ch.state.mutex.Lock()
+	ch.state.restrictedMode = mode
+	ch.state.mutex.Unlock()
+	ch.outbox.notify()
+	return nil
+}
+
+// ReplyMode returns how the bot responds to commands in the channel.
+func (ch *Channel) ReplyMode() string {
+	ch.state.mutex.Lock()
+	defer ch.state.mutex.Unlock()
+	return ch.state.replyMode
+}
+
+// SetReplyMode sets how the bot responds to commands in the channel.
+func (ch *Channel) SetReplyMode(mode string) error {
+	if mode != ReplyMention && mode != ReplyThread && mode != ReplyAction {
+		return fmt.Errorf("invalid reply mode '%s'", mode)
+	}
+	ch.state.mutex.Lock()
+	ch.state.replyMode = mode
+	ch.state.mutex.Unlock()
+	return nil
+}
+
+// Restriction returns the reason that the bot cannot currently chat in the channel.
+// The returned Restriction has an empty Reason if the bot is not restricted.
+func (ch *Channel) Restriction() Restriction {
+	ch.state.mutex.Lock()
+	defer ch.state.mutex.Unlock()
+	if !ch.state.restriction.active(time.Now()) {
+		return Restriction{}
+	}
+	return ch.state.restriction
+}
+
+// ClearRestriction allows the bot to chat in the channel again,
+// for example after it has been unbanned.
+func (ch *Channel) ClearRestriction() {
+	ch.state.restrict(Restriction{})
+	ch.outbox.notify()
+}
+
+// IsModerator determines if a user is the broadcaster or a moderator of the channel.
+// Moderators are only known once they have chatted since the bot joined.
+func (ch *Channel) IsModerator(login string) bool {
+	return strings.EqualFold(login, ch.Name) || ch.state.isModerator(login)
+}
+
+// QueueLength returns the number of messages waiting to be sent to the channel.
+func (ch *Channel) QueueLength() int {
+	return ch.outbox.len()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (ch Channel) MarshalJSON() ([]byte, error) {
+	var restriction *Restriction
+	if r := ch.Restriction(); r.Reason != "" {
+		restriction = &r
+	}
+	return json.Marshal(struct {
+		Name           string       `json:"name"`
+		RoomState      RoomState    `json:"roomState"`
+		UserState      UserState    `json:"userState"`
+		ReplyMode      string       `json:"replyMode"`
+		RestrictedMode string       `json:"restrictedMode"`
+		Restriction    *Restriction `json:"restriction,omitempty"`
+		QueueLength    int          `json:"queueLength"`
+	}{
+		Name:           ch.Name,
+		RoomState:      ch.RoomState(),
+		UserState:      ch.UserState(),
+		ReplyMode:      ch.ReplyMode(),
+		RestrictedMode: ch.RestrictedMode(),
+		Restriction:    restriction,
+		QueueLength:    ch.QueueLength(),
+	})
+}
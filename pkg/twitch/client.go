@@ -2,49 +2,207 @@ package twitch
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gempir/go-twitch-irc"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/channelname"
+	"github.com/brattonross/roastedbot/pkg/chatlog"
+	"github.com/brattonross/roastedbot/pkg/event"
 )
 
+// sender sends messages to twitch.
+type sender interface {
+	Say(channel, text string)
+	Reply(channel, parentMsgID, text string)
+	Whisper(username, text string)
+}
+
 // Client is a wrapper of go-twitch-irc Client.
 type Client struct {
 	*twitch.Client
 
-	channels      map[string]*Channel
-	channelsMutex *sync.Mutex
-	rateLimit     <-chan time.Time
-	start         time.Time
+	// autoPartBanned makes the Client leave channels that it is permanently banned from.
+	autoPartBanned bool
+	channels       map[string]*Channel
+	channelsMutex  *sync.Mutex
+	connection     *connection
+	// disconnected stops Connect from reconnecting once Disconnect is called.
+	disconnected   bool
+	ircMutex       *sync.Mutex
+	onChannelAdded func(channel string)
+	onConnect      func()
+	onNewMessage   func(channel string, user twitch.User, message twitch.Message)
+	onNewWhisper   func(user twitch.User, message twitch.Message)
+	onChatLine     func(channel, line string)
+	rateLimit      <-chan time.Time
+	// reconnect tells Connect that the connection to twitch has been replaced.
+	reconnect    chan struct{}
+	sender       sender
+	start        time.Time
+	whisperLimit *limiter
+	whispers     *outbox
 
+	// Audit records the administrative actions taken on the Client.
+	Audit *audit.Log
+	// ChatLog archives the chat of the Client's channels. It is nil if chat is not archived.
+	ChatLog *chatlog.Archive
+	// Events is the bus that the Client publishes events to.
+	Events *event.Bus
+	// Log is the logger used by the Client and its commands.
+	Log *log.Logger
+	// Reload reloads the bot's configuration, returning a description of each
+	// change. It is nil if the configuration cannot be reloaded.
+	Reload   func() ([]string, error)
 	Username string
 }
 
 // NewClient creates a new Client using the given Config.
 func NewClient(username string, client *twitch.Client) *Client {
-	return &Client{
+	cl := &Client{
+		Audit:         audit.New(),
 		channelsMutex: &sync.Mutex{},
 		channels:      make(map[string]*Channel),
+		connection:    newConnection(),
 		Client:        client,
+		ircMutex:      &sync.Mutex{},
 		rateLimit:     time.Tick(time.Millisecond * 1500),
+		reconnect:     make(chan struct{}, 1),
+		sender:        client,
 		start:         time.Now(),
+		whisperLimit:  newLimiter(whisperLimits),
+		whispers:      newOutbox(),
+		Events:        event.NewBus(),
+		Log:           log.StandardLogger(),
 		Username:      username,
 	}
+	cl.handle(client)
+	go cl.drainWhispers()
+	return cl
+}
+
+// handle registers the Client's handlers with a connection to twitch.
+func (cl *Client) handle(client *twitch.Client) {
+	client.OnConnect(cl.handleConnect)
+	client.OnNewMessage(cl.handleMessage)
+	client.OnNewWhisper(cl.handleWhisper)
+	client.OnNewUsernoticeMessage(cl.handleUserNotice)
+	client.OnNewRoomstateMessage(cl.handleRoomState)
+	client.OnNewUserstateMessage(cl.handleUserState)
+	client.OnNewNoticeMessage(cl.handleNotice)
+	client.OnNewClearchatMessage(cl.handleClearChat)
+	client.OnUserJoin(cl.handleUserJoin)
+	client.OnNewUnsetMessage(cl.handleUnset)
+}
+
+// irc returns the connection to twitch, which is replaced by Reauthenticate.
+func (cl *Client) irc() *twitch.Client {
+	cl.ircMutex.Lock()
+	defer cl.ircMutex.Unlock()
+	return cl.Client
+}
+
+func (cl *Client) currentSender() sender {
+	cl.ircMutex.Lock()
+	defer cl.ircMutex.Unlock()
+	return cl.sender
+}
+
+// Reauthenticate replaces the chat token that the Client logs in with. If the
+// Client is connected, Connect reconnects with the new token and rejoins its channels.
+func (cl *Client) Reauthenticate(oauth string) {
+	cl.ircMutex.Lock()
+	old := cl.Client
+	next := twitch.NewClient(cl.Username, oauth)
+	next.IrcAddress = old.IrcAddress
+	next.TLS = old.TLS
+	cl.handle(next)
+	for _, ch := range cl.Channels() {
+		next.Join(ch.Name)
+	}
+	cl.Client = next
+	if cl.sender == sender(old) {
+		cl.sender = next
+	}
+	cl.ircMutex.Unlock()
+
+	select {
+	case cl.reconnect <- struct{}{}:
+	default:
+	}
+}
+
+// SetAutoPartBanned sets whether the Client leaves channels that it is permanently banned from.
+func (cl *Client) SetAutoPartBanned(enabled bool) {
+	cl.channelsMutex.Lock()
+	defer cl.channelsMutex.Unlock()
+	cl.autoPartBanned = enabled
+}
+
+func (cl *Client) autoParts() bool {
+	cl.channelsMutex.Lock()
+	defer cl.channelsMutex.Unlock()
+	return cl.autoPartBanned
 }
 
 // AddChannel adds a channel to the Client, but does not join it.
 func (cl *Client) AddChannel(name string) error {
-	return cl.addChannel(newChannel(name))
+	if err := cl.addChannel(newChannel(name)); err != nil {
+		return err
+	}
+	if cl.onChannelAdded != nil {
+		cl.onChannelAdded(name)
+	}
+	return nil
 }
 
-func (cl *Client) addChannel(ch *Channel) error {
-	if _, ok := cl.channels[ch.Name]; ok {
-		return fmt.Errorf("Client already contains channel with name '%s'", ch.Name)
+// OnChannelAdded sets the callback that is called after a channel is added
+// to the Client, which can be used to set up the channel's modules.
+func (cl *Client) OnChannelAdded(callback func(channel string)) {
+	cl.onChannelAdded = callback
+}
+
+// JoinChannel adds a channel to the Client and joins it.
+func (cl *Client) JoinChannel(name string) error {
+	name, err := ChannelName(name)
+	if err != nil {
+		return err
 	}
+	if err := cl.AddChannel(name); err != nil {
+		return err
+	}
+	cl.irc().Join(name)
+	cl.Events.Publish(event.ChannelJoined{
+		Channel: name,
+		Time:    time.Now(),
+	})
+	return nil
+}
+
+// ChannelName normalizes a channel name given by a user, removing a leading #
+// and lower casing it. It returns ErrInvalidChannel if the result is not a
+// valid channel name, so that it can be safely sent to twitch.
+func ChannelName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	if !channelname.Valid(name) {
+		return "", fmt.Errorf("%w '%s': expected up to 25 lower case letters, digits and underscores", ErrInvalidChannel, name)
+	}
+	return name, nil
+}
+
+func (cl *Client) addChannel(ch *Channel) error {
 	cl.channelsMutex.Lock()
 	defer cl.channelsMutex.Unlock()
+	if _, ok := cl.channels[ch.Name]; ok {
+		return fmt.Errorf("channel '%s': %w", ch.Name, ErrExists)
+	}
 
 	cl.channels[ch.Name] = ch
+	go cl.drain(ch)
 
 	return nil
 }
@@ -53,9 +211,9 @@ func (cl *Client) addChannel(ch *Channel) error {
 // If the Client is not currently connected to the channel it will return an error.
 // If the module does not already exist, it will be created.
 func (cl *Client) AddCommand(channel, module string, c *Command) error {
-	ch, ok := cl.channels[channel]
-	if !ok {
-		return fmt.Errorf("Client is not connected to channel '%s'", channel)
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return err
 	}
 	ch.AddCommand(module, c)
 	return nil
@@ -63,9 +221,9 @@ func (cl *Client) AddCommand(channel, module string, c *Command) error {
 
 // AddModule adds a new module with the given name to the given channel.
 func (cl *Client) AddModule(channel, module string) (*Module, error) {
-	ch, ok := cl.channels[channel]
-	if !ok {
-		return nil, fmt.Errorf("channel '%s' is not configured", channel)
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return nil, err
 	}
 	m, err := ch.AddModule(module)
 	if err != nil {
@@ -77,15 +235,19 @@ func (cl *Client) AddModule(channel, module string) (*Module, error) {
 // Channel gets the channel with the given name if the Client
 // has it configured, otherwise it returns an error.
 func (cl *Client) Channel(name string) (*Channel, error) {
+	cl.channelsMutex.Lock()
+	defer cl.channelsMutex.Unlock()
 	ch, ok := cl.channels[name]
 	if !ok {
-		return nil, fmt.Errorf("channel %s is not configured", name)
+		return nil, fmt.Errorf("channel '%s': %w", name, ErrNotFound)
 	}
 	return ch, nil
 }
 
 // Channels returns the channels that the Client is currently connected to.
 func (cl *Client) Channels() []Channel {
+	cl.channelsMutex.Lock()
+	defer cl.channelsMutex.Unlock()
 	chans := []Channel{}
 	for _, c := range cl.channels {
 		chans = append(chans, *c)
@@ -93,53 +255,443 @@ func (cl *Client) Channels() []Channel {
 	return chans
 }
 
+// Connect connects to twitch, publishing the connection state as it changes.
+// It blocks until the connection is closed, reconnecting if the Client reauthenticates.
+func (cl *Client) Connect() error {
+	for {
+		irc := cl.irc()
+		cl.publishConnectionState(event.Connecting)
+		err := cl.connect(irc)
+		cl.publishConnectionState(event.Disconnected)
+
+		cl.ircMutex.Lock()
+		replaced := cl.Client != irc && !cl.disconnected
+		cl.ircMutex.Unlock()
+		if !replaced {
+			return err
+		}
+	}
+}
+
+// connect runs a connection to twitch until it fails, is disconnected,
+// or is replaced by Reauthenticate. Replaced connections are closed here
+// rather than by Reauthenticate, so that they are never closed before
+// or while they are being connected.
+func (cl *Client) connect(irc *twitch.Client) error {
+	done := make(chan error, 1)
+	go func() { done <- irc.Connect() }()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-cl.reconnect:
+			if cl.irc() == irc {
+				// The connection was replaced before this one was started.
+				continue
+			}
+			return closeConnection(irc, done)
+		}
+	}
+}
+
+// disconnectRetry is how often a replaced connection is disconnected until it stops.
+const disconnectRetry = 100 * time.Millisecond
+
+// closeConnection disconnects a replaced connection and waits for its Connect
+// to return. go-twitch-irc only notices that it was disconnected between
+// attempts to connect, so Disconnect is repeated until it does.
+func closeConnection(irc *twitch.Client, done <-chan error) error {
+	for {
+		irc.Disconnect()
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(disconnectRetry):
+		}
+	}
+}
+
+// Disconnect closes the connection to twitch.
+func (cl *Client) Disconnect() error {
+	cl.ircMutex.Lock()
+	cl.disconnected = true
+	irc := cl.Client
+	cl.ircMutex.Unlock()
+	return irc.Disconnect()
+}
+
+// OnConnect sets the callback that is called when a connection to twitch has been established.
+func (cl *Client) OnConnect(callback func()) {
+	cl.onConnect = callback
+}
+
+func (cl *Client) handleConnect() {
+	cl.publishConnectionState(event.Connected)
+	if cl.onConnect != nil {
+		cl.onConnect()
+	}
+}
+
+// OnNewMessage sets the callback that is called for every chat message.
+func (cl *Client) OnNewMessage(callback func(channel string, user twitch.User, message twitch.Message)) {
+	cl.onNewMessage = callback
+}
+
+// OnChatLine sets the callback that is called with the channel and raw IRC line
+// of every PRIVMSG, USERNOTICE, CLEARCHAT and CLEARMSG that the Client receives.
+func (cl *Client) OnChatLine(callback func(channel, line string)) {
+	cl.onChatLine = callback
+}
+
+func (cl *Client) chatLine(channel, line string) {
+	if cl.onChatLine != nil {
+		cl.onChatLine(channel, line)
+	}
+}
+
+func (cl *Client) handleMessage(channel string, user twitch.User, message twitch.Message) {
+	cl.connection.received(time.Now())
+	cl.chatLine(channel, message.Raw)
+	correlate(&message)
+	if ch, err := cl.Channel(channel); err == nil {
+		ch.state.updateModerator(user, message)
+	}
+	if e := ParseCheer(channel, user, message); e != nil {
+		cl.Events.Publish(e)
+	}
+	if cl.onNewMessage != nil {
+		cl.onNewMessage(channel, user, message)
+	}
+}
+
+func (cl *Client) handleRoomState(channel string, user twitch.User, message twitch.Message) {
+	cl.connection.received(time.Now())
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return
+	}
+	// Twitch sends the full ROOMSTATE when the bot joins a channel.
+	ch.state.setJoined(true)
+	ch.state.readmit()
+	ch.state.updateRoom(message.Tags)
+	ch.outbox.notify()
+}
+
+func (cl *Client) handleUserState(channel string, user twitch.User, message twitch.Message) {
+	cl.connection.received(time.Now())
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return
+	}
+	ch.state.readmit()
+	ch.state.updateUser(user, message)
+	ch.outbox.notify()
+}
+
+func (cl *Client) handleUserJoin(channel, user string) {
+	if !strings.EqualFold(user, cl.Username) {
+		return
+	}
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return
+	}
+	ch.state.readmit()
+	ch.outbox.notify()
+}
+
+func (cl *Client) handleNotice(channel string, user twitch.User, message twitch.Message) {
+	cl.connection.received(time.Now())
+	r, ok := parseNotice(message, time.Now())
+	if !ok {
+		return
+	}
+	cl.restrict(channel, r)
+}
+
+func (cl *Client) handleClearChat(channel string, user twitch.User, message twitch.Message) {
+	cl.connection.received(time.Now())
+	cl.chatLine(channel, message.Raw)
+	if !strings.EqualFold(user.Username, cl.Username) {
+		return
+	}
+	cl.restrict(channel, parseClearChat(message, time.Now()))
+}
+
+// restrict stops the bot from sending messages to the channel until the restriction expires.
+func (cl *Client) restrict(channel string, r Restriction) {
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return
+	}
+	ch.state.restrict(r)
+	ch.outbox.notify()
+	cl.Events.Publish(event.ChannelRestricted{
+		Channel: channel,
+		Reason:  r.Reason,
+		Until:   r.Until,
+		Time:    time.Now(),
+	})
+
+	if cl.autoParts() && r.Reason == RestrictionBanned {
+		cl.PartChannel(channel)
+	}
+}
+
+func (cl *Client) handleUserNotice(channel string, user twitch.User, message twitch.Message) {
+	cl.connection.received(time.Now())
+	cl.chatLine(channel, message.Raw)
+	if e := ParseUserNotice(channel, user, message); e != nil {
+		cl.Events.Publish(e)
+	}
+}
+
+// handleUnset handles the lines that go-twitch-irc does not parse, which include CLEARMSG.
+func (cl *Client) handleUnset(line string) {
+	cl.connection.received(time.Now())
+	const clearMsg = " CLEARMSG #"
+	if i := strings.Index(line, clearMsg); i >= 0 {
+		channel := line[i+len(clearMsg):]
+		if j := strings.IndexByte(channel, ' '); j >= 0 {
+			channel = channel[:j]
+		}
+		cl.chatLine(channel, line)
+	}
+}
+
+func (cl *Client) publishConnectionState(state event.ConnectionState) {
+	now := time.Now()
+	cl.connection.setConnected(state == event.Connected, now)
+	if state != event.Connected {
+		// Channels are joined again when the Client reconnects.
+		for _, ch := range cl.Channels() {
+			ch.state.setJoined(false)
+		}
+	}
+	cl.Events.Publish(event.ConnectionStateChanged{
+		State: state,
+		Time:  now,
+	})
+}
+
 // EnableCommand enables a command in the given channel and module.
 // The Client must be connected to the given channel, and the command must exist within the module.
 func (cl *Client) EnableCommand(channel, module, command string) error {
-	ch, ok := cl.channels[channel]
-	if !ok {
-		return fmt.Errorf("Client is not connected to channel '%s'", channel)
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return err
+	}
+	if err := ch.EnableCommand(module, command); err != nil {
+		return err
 	}
-	return ch.EnableCommand(module, command)
+	cl.publishToggle(channel, module, command, true)
+	return nil
 }
 
 // EnableModule enables a module in the given channel.
 func (cl *Client) EnableModule(channel, module string) error {
-	ch, ok := cl.channels[channel]
-	if !ok {
-		return fmt.Errorf("Client is not connected to channel '%s'", channel)
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return err
 	}
-	return ch.EnableModule(module)
+	if err := ch.EnableModule(module); err != nil {
+		return err
+	}
+	cl.publishToggle(channel, module, "", true)
+	return nil
 }
 
 // DisableCommand disables a command in the given channel and module.
 func (cl *Client) DisableCommand(channel, module, command string) error {
-	ch, ok := cl.channels[channel]
-	if !ok {
-		return fmt.Errorf("Client is not connected to channel '%s'", channel)
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return err
+	}
+	if err := ch.DisableCommand(module, command); err != nil {
+		return err
 	}
-	return ch.DisableCommand(module, command)
+	cl.publishToggle(channel, module, command, false)
+	return nil
 }
 
 // DisableModule disables a module in a channel.
 func (cl *Client) DisableModule(channel, module string) error {
-	ch, ok := cl.channels[channel]
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return err
+	}
+	if err := ch.DisableModule(module); err != nil {
+		return err
+	}
+	cl.publishToggle(channel, module, "", false)
+	return nil
+}
+
+func (cl *Client) publishToggle(channel, module, command string, enabled bool) {
+	cl.Events.Publish(event.ModuleToggled{
+		Channel: channel,
+		Module:  module,
+		Command: command,
+		Enabled: enabled,
+		Time:    time.Now(),
+	})
+}
+
+// PartChannel leaves a channel and removes it from the Client.
+// Any messages still waiting to be sent to the channel are discarded.
+func (cl *Client) PartChannel(name string) error {
+	cl.channelsMutex.Lock()
+	ch, ok := cl.channels[name]
 	if !ok {
-		return fmt.Errorf("Client is not connected to channel '%s'", channel)
+		cl.channelsMutex.Unlock()
+		return fmt.Errorf("channel '%s': %w", name, ErrNotFound)
 	}
-	return ch.DisableModule(module)
+	delete(cl.channels, name)
+	cl.channelsMutex.Unlock()
+
+	ch.outbox.close()
+	cl.irc().Depart(name)
+	return nil
+}
+
+// Uptime returns how long the Client has been running.
+func (cl *Client) Uptime() time.Duration {
+	return time.Since(cl.start)
+}
+
+// SetCommandCooldown changes the cooldown of a command in the given channel and module.
+func (cl *Client) SetCommandCooldown(channel, module, command string, cooldown time.Duration) error {
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return err
+	}
+	m, err := ch.Module(module)
+	if err != nil {
+		return err
+	}
+	return m.SetCooldown(command, cooldown)
 }
 
 // JoinChannels joins all of the channels in the Client's channel list.
 func (cl *Client) JoinChannels() {
-	for _, c := range cl.channels {
-		cl.Join(c.Name)
+	for _, c := range cl.Channels() {
+		cl.irc().Join(c.Name)
+		cl.Events.Publish(event.ChannelJoined{
+			Channel: c.Name,
+			Time:    time.Now(),
+		})
 	}
 }
 
 // Say will send a message to twitch irc.
-// Messages are limited to sending as per the rate limit.
+// Messages to configured channels are queued and sent in order, as per the rate
+// limit and the channel's chat settings, so Say does not wait for the message to be sent.
 func (cl *Client) Say(channel, text string) {
-	<-cl.rateLimit
-	cl.Client.Say(channel, text)
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		<-cl.rateLimit
+		cl.send(channel, outgoing{text: text})
+		return
+	}
+	ch.outbox.push(outgoing{text: text})
+}
+
+// Reply responds to a message in a channel, using the channel's reply mode.
+// Whispers are responded to with a whisper. Threaded replies fall back to
+// mentioning the user when the message has no ID.
+func (cl *Client) Reply(channel string, message twitch.Message, text string) {
+	if message.Type == twitch.WHISPER {
+		cl.replyWhisper(message, text)
+		return
+	}
+
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		cl.Say(channel, text)
+		return
+	}
+
+	switch ch.ReplyMode() {
+	case ReplyThread:
+		if id := message.Tags["id"]; id != "" {
+			ch.outbox.push(outgoing{text: text, replyTo: id})
+			return
+		}
+	case ReplyAction:
+		ch.outbox.push(outgoing{text: "/me " + text})
+		return
+	}
+
+	if name := message.Tags["display-name"]; name != "" {
+		text = fmt.Sprintf("%s, %s", name, text)
+	}
+	ch.outbox.push(outgoing{text: text})
+}
+
+// drain sends the messages queued for a channel until the channel's outbox is closed.
+func (cl *Client) drain(ch *Channel) {
+	o := ch.outbox
+	for {
+		m, ok := o.peek()
+		if !ok {
+			select {
+			case <-o.wake:
+				continue
+			case <-o.done:
+				return
+			}
+		}
+
+		delay, restricted := ch.state.sendDelay(time.Now())
+		if restricted && ch.RestrictedMode() == RestrictedDrop {
+			o.pop()
+			continue
+		}
+		if restricted {
+			select {
+			case <-o.wake:
+				continue
+			case <-o.done:
+				return
+			}
+		}
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-o.wake:
+				timer.Stop()
+			case <-o.done:
+				timer.Stop()
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-cl.rateLimit:
+		case <-o.done:
+			return
+		}
+		o.pop()
+		ch.state.sent(time.Now())
+		cl.send(ch.Name, m)
+	}
+}
+
+func (cl *Client) send(channel string, m outgoing) {
+	if m.replyTo != "" {
+		cl.currentSender().Reply(channel, m.replyTo, m.text)
+	} else {
+		cl.currentSender().Say(channel, m.text)
+	}
+	e := event.MessageSent{
+		Channel: channel,
+		Text:    m.text,
+		Time:    time.Now(),
+	}
+	if !m.queued.IsZero() {
+		e.Wait = e.Time.Sub(m.queued)
+	}
+	cl.Events.Publish(e)
 }
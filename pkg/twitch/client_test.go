@@ -0,0 +1,376 @@
+package twitch
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+)
+
+type fakeSender struct {
+	mutex    *sync.Mutex
+	said     []string
+	whispers []string
+}
+
+func (s *fakeSender) Say(channel, text string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.said = append(s.said, text)
+}
+
+func (s *fakeSender) Reply(channel, parentMsgID, text string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.said = append(s.said, "reply to "+parentMsgID+": "+text)
+}
+
+func (s *fakeSender) Whisper(username, text string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.whispers = append(s.whispers, username+": "+text)
+}
+
+func (s *fakeSender) messages() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.said...)
+}
+
+func (s *fakeSender) sentWhispers() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.whispers...)
+}
+
+func newTestClient() (*Client, *fakeSender) {
+	s := &fakeSender{mutex: &sync.Mutex{}}
+	cl := NewClient("bot", twitch.NewClient("bot", "oauth:token"))
+	cl.rateLimit = time.Tick(time.Millisecond)
+	cl.sender = s
+	return cl, s
+}
+
+// waitFor polls until cond returns true or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond * 5)
+	}
+}
+
+func TestSay(t *testing.T) {
+	cl, s := newTestClient()
+	cl.AddChannel("channel")
+
+	cl.Say("channel", "one")
+	cl.Say("channel", "two")
+
+	waitFor(t, func() bool { return len(s.messages()) == 2 })
+	if said := s.messages(); said[0] != "one" || said[1] != "two" {
+		t.Errorf("expected messages to be sent in order, got %v", said)
+	}
+}
+
+func TestSay_EmoteOnlyQueue(t *testing.T) {
+	cl, s := newTestClient()
+	cl.AddChannel("channel")
+	cl.handleRoomState("channel", twitch.User{}, twitch.Message{Tags: map[string]string{"emote-only": "1"}})
+
+	cl.Say("channel", "held")
+	time.Sleep(time.Millisecond * 20)
+	if len(s.messages()) != 0 {
+		t.Fatal("expected message to be held while emote-only mode is on")
+	}
+
+	cl.handleRoomState("channel", twitch.User{}, twitch.Message{Tags: map[string]string{"emote-only": "0"}})
+	waitFor(t, func() bool { return len(s.messages()) == 1 })
+}
+
+func TestSay_Wait(t *testing.T) {
+	cl, s := newTestClient()
+	cl.AddChannel("channel")
+	sent := make(chan event.MessageSent, 1)
+	cl.Events.Subscribe(func(e event.Event) { sent <- e.(event.MessageSent) }, event.TypeMessageSent)
+	cl.handleRoomState("channel", twitch.User{}, twitch.Message{Tags: map[string]string{"emote-only": "1"}})
+
+	cl.Say("channel", "held")
+	time.Sleep(time.Millisecond * 20)
+	cl.handleRoomState("channel", twitch.User{}, twitch.Message{Tags: map[string]string{"emote-only": "0"}})
+	waitFor(t, func() bool { return len(s.messages()) == 1 })
+
+	if e := <-sent; e.Wait < time.Millisecond*20 {
+		t.Errorf("expected the message to have waited at least 20ms, got %v", e.Wait)
+	}
+}
+
+func TestSay_EmoteOnlyDrop(t *testing.T) {
+	cl, s := newTestClient()
+	cl.AddChannel("channel")
+	ch, _ := cl.Channel("channel")
+	ch.SetRestrictedMode(RestrictedDrop)
+	cl.handleRoomState("channel", twitch.User{}, twitch.Message{Tags: map[string]string{"emote-only": "1"}})
+
+	cl.Say("channel", "dropped")
+	waitFor(t, func() bool { return ch.QueueLength() == 0 })
+
+	cl.handleRoomState("channel", twitch.User{}, twitch.Message{Tags: map[string]string{"emote-only": "0"}})
+	time.Sleep(time.Millisecond * 20)
+	if len(s.messages()) != 0 {
+		t.Errorf("expected message to be dropped, got %v", s.messages())
+	}
+}
+
+func TestReply(t *testing.T) {
+	message := twitch.Message{Type: twitch.PRIVMSG, Tags: map[string]string{"display-name": "User", "id": "abc"}}
+	noID := twitch.Message{Type: twitch.PRIVMSG, Tags: map[string]string{"display-name": "User"}}
+	tests := []struct {
+		mode     string
+		message  twitch.Message
+		expected string
+	}{
+		{mode: ReplyMention, message: message, expected: "User, hello"},
+		{mode: ReplyAction, message: message, expected: "/me hello"},
+		{mode: ReplyThread, message: message, expected: "reply to abc: hello"},
+		{mode: ReplyThread, message: noID, expected: "User, hello"},
+	}
+
+	for _, test := range tests {
+		cl, s := newTestClient()
+		cl.AddChannel("channel")
+		ch, _ := cl.Channel("channel")
+		ch.SetReplyMode(test.mode)
+
+		cl.Reply("channel", test.message, "hello")
+		waitFor(t, func() bool { return len(s.messages()) == 1 })
+		if said := s.messages()[0]; said != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.mode, test.expected, said)
+		}
+	}
+}
+
+func TestOnChatLine(t *testing.T) {
+	cl, _ := newTestClient()
+	var lines []string
+	cl.OnChatLine(func(channel, line string) {
+		if channel != "test" {
+			t.Errorf("expected channel 'test', got '%s'", channel)
+		}
+		lines = append(lines, line)
+	})
+
+	cl.handleMessage("test", twitch.User{}, twitch.Message{Raw: "PRIVMSG"})
+	cl.handleUserNotice("test", twitch.User{}, twitch.Message{Raw: "USERNOTICE"})
+	cl.handleClearChat("test", twitch.User{}, twitch.Message{Raw: "CLEARCHAT"})
+	cl.handleUnset("@login=foo :tmi.twitch.tv CLEARMSG #test :text")
+	cl.handleUnset("@badge-info= :tmi.twitch.tv GLOBALUSERSTATE")
+	cl.handleRoomState("test", twitch.User{}, twitch.Message{Raw: "ROOMSTATE"})
+
+	expected := []string{"PRIVMSG", "USERNOTICE", "CLEARCHAT", "@login=foo :tmi.twitch.tv CLEARMSG #test :text"}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %v", len(expected), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("expected line %q, got %q", expected[i], lines[i])
+		}
+	}
+}
+
+// fakeIRC is a twitch IRC server that welcomes every connection.
+type fakeIRC struct {
+	conns    chan *fakeConn
+	listener net.Listener
+	// messages receives the PRIVMSG lines sent to the server.
+	messages chan string
+}
+
+// fakeConn is a connection to a fakeIRC.
+type fakeConn struct {
+	closed chan struct{}
+	pass   string
+}
+
+func newFakeIRC(t *testing.T) *fakeIRC {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	s := &fakeIRC{conns: make(chan *fakeConn, 10), listener: l, messages: make(chan string, 10)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeIRC) handle(conn net.Conn) {
+	defer conn.Close()
+	c := &fakeConn{closed: make(chan struct{})}
+	defer close(c.closed)
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "PASS "):
+			c.pass = strings.TrimPrefix(line, "PASS ")
+		case strings.HasPrefix(line, "NICK "):
+			conn.Write([]byte(":tmi.twitch.tv 001 bot :Welcome, GLHF!\r\n"))
+			s.conns <- c
+		case strings.Contains(line, "PRIVMSG "):
+			s.messages <- line
+		}
+	}
+}
+
+// next returns the next connection that the server welcomes.
+func (s *fakeIRC) next(t *testing.T) *fakeConn {
+	t.Helper()
+	select {
+	case c := <-s.conns:
+		return c
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a connection")
+		return nil
+	}
+}
+
+// expectNone fails the test if the server welcomes another connection.
+func (s *fakeIRC) expectNone(t *testing.T) {
+	t.Helper()
+	select {
+	case c := <-s.conns:
+		t.Errorf("expected no other connection, got one with PASS %s", c.pass)
+	case <-time.After(disconnectRetry * 3):
+	}
+}
+
+func waitClosed(t *testing.T, c *fakeConn) {
+	t.Helper()
+	select {
+	case <-c.closed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected the connection with PASS %s to be closed", c.pass)
+	}
+}
+
+func newConnectClient(s *fakeIRC) *Client {
+	irc := twitch.NewClient("bot", "oauth:old")
+	irc.IrcAddress = s.listener.Addr().String()
+	irc.TLS = false
+	cl := NewClient("bot", irc)
+	cl.AddChannel("foo")
+	return cl
+}
+
+func connect(t *testing.T, cl *Client) {
+	done := make(chan error)
+	go func() { done <- cl.Connect() }()
+	t.Cleanup(func() {
+		cl.Disconnect()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Error("expected Connect to return after Disconnect")
+		}
+	})
+}
+
+func TestReauthenticate(t *testing.T) {
+	s := newFakeIRC(t)
+	cl := newConnectClient(s)
+	connect(t, cl)
+
+	old := s.next(t)
+	if old.pass != "oauth:old" {
+		t.Errorf("expected PASS oauth:old, got %s", old.pass)
+	}
+
+	cl.Reauthenticate("oauth:new")
+	if c := s.next(t); c.pass != "oauth:new" {
+		t.Errorf("expected PASS oauth:new, got %s", c.pass)
+	}
+	waitClosed(t, old)
+	if cl.currentSender() != sender(cl.irc()) {
+		t.Error("expected messages to be sent with the new connection")
+	}
+	s.expectNone(t)
+}
+
+func TestReauthenticate_BeforeConnect(t *testing.T) {
+	s := newFakeIRC(t)
+	cl := newConnectClient(s)
+	cl.Reauthenticate("oauth:new")
+	connect(t, cl)
+
+	c := s.next(t)
+	if c.pass != "oauth:new" {
+		t.Errorf("expected PASS oauth:new, got %s", c.pass)
+	}
+	s.expectNone(t)
+	select {
+	case <-c.closed:
+		t.Error("expected the connection to stay open")
+	default:
+	}
+}
+
+func TestReauthenticate_WhileConnecting(t *testing.T) {
+	s := newFakeIRC(t)
+	cl := newConnectClient(s)
+	connect(t, cl)
+	cl.Reauthenticate("oauth:new")
+
+	// The old connection may or may not have been made before it was replaced,
+	// but it must be closed and the bot must end up connected with the new token.
+	c := s.next(t)
+	if c.pass == "oauth:old" {
+		waitClosed(t, c)
+		c = s.next(t)
+	}
+	if c.pass != "oauth:new" {
+		t.Errorf("expected PASS oauth:new, got %s", c.pass)
+	}
+	s.expectNone(t)
+}
+
+func TestReply_Thread(t *testing.T) {
+	s := newFakeIRC(t)
+	cl := newConnectClient(s)
+	cl.rateLimit = time.Tick(time.Millisecond)
+	ch, _ := cl.Channel("foo")
+	ch.SetReplyMode(ReplyThread)
+	connect(t, cl)
+	s.next(t)
+
+	cl.Reply("foo", twitch.Message{Type: twitch.PRIVMSG, Tags: map[string]string{"id": "abc"}}, "hello")
+	select {
+	case line := <-s.messages:
+		expected := "@reply-parent-msg-id=abc PRIVMSG #foo :hello"
+		if line != expected {
+			t.Errorf("expected %q, got %q", expected, line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the reply")
+	}
+}
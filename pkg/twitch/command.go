@@ -7,6 +7,19 @@ import (
 	twitch "github.com/gempir/go-twitch-irc"
 )
 
+// Response determines how a command can be invoked, and how it responds.
+type Response int
+
+const (
+	// RespondPublic commands are invoked and respond in channel chat.
+	RespondPublic Response = iota
+	// RespondWhisper commands are invoked and respond by whisper.
+	RespondWhisper
+	// RespondBoth commands can be invoked in chat or by whisper,
+	// and respond the same way that they were invoked.
+	RespondBoth
+)
+
 // Command is a command that a bot can use.
 type Command struct {
 	// Cooldown of the command.
@@ -17,6 +30,13 @@ type Command struct {
 	LastUsed time.Time
 	// Name of the command.
 	Name string
+	// Permitted determines if a user may use the command.
+	// Commands without it can be used by anyone.
+	Permitted func(user twitch.User) bool
+	// Respond determines whether the command is used in chat, by whisper, or both.
+	Respond Response
+	// Response is the fixed message sent by custom commands.
+	Response string
 	// Usage of the command.
 	Use string
 }
@@ -33,11 +53,42 @@ func (c *Command) Execute(cl *Client, args []string, channel string, user twitch
 	return nil
 }
 
+// ParseCooldown parses a command cooldown given by a user, such as "30s".
+// An empty string is no cooldown.
+func ParseCooldown(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cooldown '%s': %v", s, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid cooldown '%s': must not be negative", s)
+	}
+	return d, nil
+}
+
 // IsOnCooldown determines if the command is on cooldown.
 func (c Command) IsOnCooldown() bool {
 	return time.Now().Add(-c.Cooldown).Before(c.LastUsed)
 }
 
+// AllowsChat determines if the command can be invoked in channel chat.
+func (c Command) AllowsChat() bool {
+	return c.Respond == RespondPublic || c.Respond == RespondBoth
+}
+
+// AllowsWhisper determines if the command can be invoked by whisper.
+func (c Command) AllowsWhisper() bool {
+	return c.Respond == RespondWhisper || c.Respond == RespondBoth
+}
+
+// Permits determines if the user may use the command.
+func (c Command) Permits(user twitch.User) bool {
+	return c.Permitted == nil || c.Permitted(user)
+}
+
 func (c Command) match(s string) bool {
 	if len(s) < 1 {
 		return false
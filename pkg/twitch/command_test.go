@@ -92,3 +92,22 @@ func TestMatch_EmptyString(t *testing.T) {
 		t.Error("Command unexpectedly matched on empty string")
 	}
 }
+
+func TestParseCooldown(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected time.Duration
+		valid    bool
+	}{
+		{s: "", expected: 0, valid: true},
+		{s: "30s", expected: 30 * time.Second, valid: true},
+		{s: "-1s"},
+		{s: "soon"},
+	}
+	for _, tt := range tests {
+		d, err := ParseCooldown(tt.s)
+		if (err == nil) != tt.valid || d != tt.expected {
+			t.Errorf("%q: expected %s and valid %t, got %s and %v", tt.s, tt.expected, tt.valid, d, err)
+		}
+	}
+}
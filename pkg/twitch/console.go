@@ -0,0 +1,89 @@
+package twitch
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+// consoleSender writes the Client's messages to a console instead of twitch.
+type consoleSender struct {
+	mutex    *sync.Mutex
+	username string
+	w        io.Writer
+}
+
+func (s *consoleSender) Say(channel, text string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	fmt.Fprintf(s.w, "#%s <%s> %s\n", channel, s.username, text)
+}
+
+func (s *consoleSender) Reply(channel, parentMsgID, text string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	fmt.Fprintf(s.w, "#%s <%s> (reply to %s) %s\n", channel, s.username, parentMsgID, text)
+}
+
+func (s *consoleSender) Whisper(username, text string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	fmt.Fprintf(s.w, "<%s> -> <%s> %s\n", s.username, username, text)
+}
+
+// Console writes the messages that the Client sends to w instead of sending
+// them to twitch, and stops rate limiting them, so that the bot can be tried
+// locally with messages given to Receive and ReceiveWhisper. It must be
+// called before any channels are added, and the Client must not be connected.
+func (cl *Client) Console(w io.Writer) {
+	cl.ircMutex.Lock()
+	defer cl.ircMutex.Unlock()
+	cl.sender = &consoleSender{mutex: &sync.Mutex{}, username: cl.Username, w: w}
+	cl.rateLimit = time.Tick(time.Millisecond)
+}
+
+// Receive handles a chat message sent by a user in a channel, as though
+// it was received from twitch. The broadcaster of the channel is the user
+// with the same name as the channel.
+func (cl *Client) Receive(channel, username, text string) {
+	user, message := consoleMessage(username, text)
+	message.Type = twitch.PRIVMSG
+	message.Raw = fmt.Sprintf("@display-name=%s;id=%s :%s!%s@%s.tmi.twitch.tv PRIVMSG #%s :%s",
+		user.DisplayName, message.Tags["id"], user.Username, user.Username, user.Username, channel, text)
+	if strings.EqualFold(username, channel) {
+		user.Badges["broadcaster"] = 1
+	}
+	cl.handleMessage(channel, user, message)
+}
+
+// ReceiveWhisper handles a whisper sent to the bot by a user, as though it was received from twitch.
+func (cl *Client) ReceiveWhisper(username, text string) {
+	user, message := consoleMessage(username, text)
+	message.Type = twitch.WHISPER
+	message.Raw = fmt.Sprintf("@display-name=%s :%s!%s@%s.tmi.twitch.tv WHISPER %s :%s",
+		user.DisplayName, user.Username, user.Username, user.Username, cl.Username, text)
+	cl.handleWhisper(user, message)
+}
+
+func consoleMessage(username, text string) (twitch.User, twitch.Message) {
+	username = strings.ToLower(username)
+	user := twitch.User{
+		Username:    username,
+		DisplayName: username,
+		Badges:      map[string]int{},
+	}
+	now := time.Now()
+	message := twitch.Message{
+		Time: now,
+		Text: text,
+		Tags: map[string]string{
+			"display-name": username,
+			"id":           fmt.Sprintf("console-%d", now.UnixNano()),
+		},
+	}
+	return user, message
+}
@@ -0,0 +1,57 @@
+package twitch
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+// syncBuffer is a bytes.Buffer that can be written to by the Client's goroutines.
+type syncBuffer struct {
+	buf   bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+func TestConsole(t *testing.T) {
+	cl := NewClient("bot", twitch.NewClient("bot", "oauth:token"))
+	out := &syncBuffer{}
+	cl.Console(out)
+	cl.AddChannel("channel")
+
+	var received []string
+	cl.OnNewMessage(func(channel string, user twitch.User, message twitch.Message) {
+		received = append(received, channel+" "+user.Username+" "+message.Text)
+		cl.Reply(channel, message, "pong")
+	})
+	cl.OnNewWhisper(func(user twitch.User, message twitch.Message) {
+		cl.Reply("", message, "psst")
+	})
+
+	cl.Receive("channel", "Viewer", "ping")
+	cl.ReceiveWhisper("viewer", "hello")
+
+	if len(received) != 1 || received[0] != "channel viewer ping" {
+		t.Errorf("expected the message to be handled, got %q", received)
+	}
+	waitFor(t, func() bool { return strings.Count(out.String(), "\n") == 2 })
+	for _, expected := range []string{"#channel <bot> viewer, pong\n", "<bot> -> <viewer> psst\n"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected output to contain %q, got %q", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package twitch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+// CustomModule is the name of the module that holds a channel's custom commands.
+const CustomModule = "custom"
+
+// SetCustomCommand adds a custom command to a channel that responds with a fixed message,
+// replacing any custom command with the same name. The custom module is created if needed.
+func (cl *Client) SetCustomCommand(channel, name, response string, cooldown time.Duration) error {
+	name = strings.ToLower(name)
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid command name '%s'", name)
+	}
+	if response == "" {
+		return fmt.Errorf("custom command '%s' must have a response", name)
+	}
+
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return err
+	}
+	// Custom commands cannot shadow the channel's other commands,
+	// as only one of them would be matched.
+	for _, other := range ch.Modules() {
+		if other.Name == CustomModule {
+			continue
+		}
+		for _, c := range other.Commands() {
+			if c.Use == name {
+				return fmt.Errorf("command '%s' in module '%s': %w", name, other.Name, ErrExists)
+			}
+		}
+	}
+	m, err := ch.Module(CustomModule)
+	if errors.Is(err, ErrNotFound) {
+		if m, err = ch.AddModule(CustomModule); err != nil {
+			return err
+		}
+		ch.EnableModule(CustomModule)
+	} else if err != nil {
+		return err
+	}
+
+	if err := m.RemoveCommand(name); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	err = m.AddCommand(&Command{
+		Cooldown: cooldown,
+		Name:     name,
+		Response: response,
+		Run: func(cl *Client, args []string, channel string, user twitch.User, message twitch.Message) {
+			cl.Reply(channel, message, response)
+		},
+		Use: name,
+	})
+	if err != nil {
+		return err
+	}
+	return m.EnableCommand(name)
+}
+
+// RemoveCustomCommand removes a custom command from a channel.
+func (cl *Client) RemoveCustomCommand(channel, name string) error {
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return err
+	}
+	m, err := ch.Module(CustomModule)
+	if err != nil {
+		return fmt.Errorf("custom command '%s': %w", name, ErrNotFound)
+	}
+	return m.RemoveCommand(strings.ToLower(name))
+}
+
+// CustomCommand returns a custom command of a channel.
+func (cl *Client) CustomCommand(channel, name string) (Command, error) {
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return Command{}, err
+	}
+	m, err := ch.Module(CustomModule)
+	if err != nil {
+		return Command{}, fmt.Errorf("custom command '%s': %w", name, ErrNotFound)
+	}
+	c, err := m.Command(strings.ToLower(name))
+	if err != nil {
+		return Command{}, err
+	}
+	return *c, nil
+}
+
+// CustomCommands returns the custom commands of a channel.
+func (cl *Client) CustomCommands(channel string) ([]Command, error) {
+	ch, err := cl.Channel(channel)
+	if err != nil {
+		return nil, err
+	}
+	m, err := ch.Module(CustomModule)
+	if errors.Is(err, ErrNotFound) {
+		return []Command{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.Commands(), nil
+}
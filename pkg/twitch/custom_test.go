@@ -0,0 +1,41 @@
+package twitch
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetCustomCommand(t *testing.T) {
+	cl, _ := newTestClient()
+	cl.AddChannel("channel")
+	cl.AddCommand("channel", "general", &Command{Name: "uptime", Use: "uptime"})
+
+	if err := cl.SetCustomCommand("channel", "discord", "join us", time.Second); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := cl.SetCustomCommand("channel", "discord", "join us now", time.Second); err != nil {
+		t.Errorf("expected a custom command to be replaceable, got %v", err)
+	}
+	if err := cl.SetCustomCommand("channel", "Uptime", "always", 0); !errors.Is(err, ErrExists) {
+		t.Errorf("expected ErrExists for the name of a built-in command, got %v", err)
+	}
+
+	custom, _ := cl.CustomCommands("channel")
+	if len(custom) != 1 || custom[0].Response != "join us now" {
+		t.Errorf("expected only the discord command, got %+v", custom)
+	}
+}
+
+func TestCustomCommand(t *testing.T) {
+	cl, _ := newTestClient()
+	cl.AddChannel("channel")
+
+	if _, err := cl.CustomCommand("channel", "discord"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound without custom commands, got %v", err)
+	}
+	cl.SetCustomCommand("channel", "discord", "join us", 0)
+	if c, err := cl.CustomCommand("channel", "Discord"); err != nil || c.Response != "join us" {
+		t.Errorf("expected the discord command, got %+v and %v", c, err)
+	}
+}
@@ -0,0 +1,12 @@
+package twitch
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when a channel, module or command does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrExists is returned when adding a channel, module or command that already exists.
+	ErrExists = errors.New("already exists")
+	// ErrInvalidChannel is returned for names that are not valid channel names.
+	ErrInvalidChannel = errors.New("invalid channel name")
+)
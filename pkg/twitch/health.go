@@ -0,0 +1,129 @@
+package twitch
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// stallTimeout is how long a message can wait in an outbox while the
+	// channel allows the bot to chat before the outbox is considered stalled.
+	stallTimeout = time.Minute
+	// disconnectTimeout is how long the Client can be disconnected
+	// before it is considered unhealthy, which allows time to reconnect.
+	disconnectTimeout = time.Minute * 2
+)
+
+// Health describes the state of the Client's connection to twitch.
+type Health struct {
+	Connected bool `json:"connected"`
+	// Since is when the Client connected or disconnected.
+	Since time.Time `json:"since"`
+	// LastMessage is when the Client last received a message from twitch.
+	LastMessage *time.Time      `json:"lastMessage,omitempty"`
+	Channels    []ChannelHealth `json:"channels"`
+}
+
+// ChannelHealth describes the state of a single channel.
+type ChannelHealth struct {
+	Name string `json:"name"`
+	// Joined is true once twitch has sent the channel's ROOMSTATE since the Client connected.
+	Joined      bool `json:"joined"`
+	QueueLength int  `json:"queueLength"`
+	// Stalled is true if messages are not leaving the outbox even though the
+	// channel allows the bot to chat.
+	Stalled bool `json:"stalled"`
+}
+
+// Ready determines if the Client is connected and has joined all of its channels.
+func (h Health) Ready() bool {
+	if !h.Connected {
+		return false
+	}
+	for _, ch := range h.Channels {
+		if !ch.Joined {
+			return false
+		}
+	}
+	return true
+}
+
+// Healthy determines if the Client is working. It is unhealthy if it has been
+// disconnected for too long to be reconnecting, or if an outbox is stalled.
+func (h Health) Healthy(now time.Time) bool {
+	if !h.Connected && !h.Since.IsZero() && now.Sub(h.Since) > disconnectTimeout {
+		return false
+	}
+	for _, ch := range h.Channels {
+		if ch.Stalled {
+			return false
+		}
+	}
+	return true
+}
+
+// connection tracks the state of the connection to twitch.
+type connection struct {
+	connected   bool
+	lastMessage time.Time
+	mutex       *sync.Mutex
+	since       time.Time
+}
+
+func newConnection() *connection {
+	return &connection{mutex: &sync.Mutex{}}
+}
+
+func (c *connection) setConnected(connected bool, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.connected != connected || c.since.IsZero() {
+		c.since = now
+	}
+	c.connected = connected
+}
+
+func (c *connection) received(now time.Time) {
+	c.mutex.Lock()
+	c.lastMessage = now
+	c.mutex.Unlock()
+}
+
+// Health returns the current state of the Client's connection and channels.
+func (cl *Client) Health() Health {
+	now := time.Now()
+	cl.connection.mutex.Lock()
+	h := Health{
+		Connected: cl.connection.connected,
+		Since:     cl.connection.since,
+		Channels:  []ChannelHealth{},
+	}
+	if !cl.connection.lastMessage.IsZero() {
+		last := cl.connection.lastMessage
+		h.LastMessage = &last
+	}
+	cl.connection.mutex.Unlock()
+
+	for _, ch := range cl.Channels() {
+		h.Channels = append(h.Channels, ChannelHealth{
+			Name:        ch.Name,
+			Joined:      ch.state.isJoined(),
+			QueueLength: ch.QueueLength(),
+			Stalled:     h.Connected && ch.stalled(now),
+		})
+	}
+	sort.Slice(h.Channels, func(i, j int) bool { return h.Channels[i].Name < h.Channels[j].Name })
+	return h
+}
+
+// stalled determines if the oldest message in the outbox has been waiting
+// for too long while nothing stops the bot from sending it.
+func (ch *Channel) stalled(now time.Time) bool {
+	m, ok := ch.outbox.peek()
+	if !ok || now.Sub(m.queued) < stallTimeout {
+		return false
+	}
+	delay, restricted := ch.state.sendDelay(now)
+	return delay == 0 && !restricted
+}
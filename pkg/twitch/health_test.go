@@ -0,0 +1,62 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+)
+
+func TestHealth(t *testing.T) {
+	cl, _ := newTestClient()
+	cl.AddChannel("channel")
+
+	h := cl.Health()
+	if h.Ready() || !h.Healthy(time.Now()) {
+		t.Errorf("expected a new client to be healthy but not ready, got %+v", h)
+	}
+
+	cl.handleConnect()
+	if h = cl.Health(); !h.Connected || h.Ready() {
+		t.Errorf("expected client to be connected but not ready before joining, got %+v", h)
+	}
+	cl.handleRoomState("channel", twitch.User{}, twitch.Message{Tags: map[string]string{}})
+	if h = cl.Health(); !h.Ready() || h.LastMessage == nil {
+		t.Errorf("expected client to be ready once the channel is joined, got %+v", h)
+	}
+
+	cl.publishConnectionState(event.Disconnected)
+	h = cl.Health()
+	if h.Ready() || h.Channels[0].Joined {
+		t.Errorf("expected channels to be left when disconnected, got %+v", h)
+	}
+	if !h.Healthy(time.Now()) {
+		t.Error("expected client to be healthy while it may be reconnecting")
+	}
+	if h.Healthy(time.Now().Add(disconnectTimeout + time.Second)) {
+		t.Error("expected client to be unhealthy once it has been disconnected for too long")
+	}
+}
+
+func TestChannel_Stalled(t *testing.T) {
+	ch := newChannel("channel")
+	later := time.Now().Add(stallTimeout + time.Second)
+	if ch.stalled(later) {
+		t.Error("expected an empty outbox not to be stalled")
+	}
+
+	ch.outbox.push(outgoing{text: "waiting"})
+	if ch.stalled(time.Now()) {
+		t.Error("expected a new message not to be stalled")
+	}
+	if !ch.stalled(later) {
+		t.Error("expected an old message to be stalled")
+	}
+
+	ch.state.updateRoom(map[string]string{"emote-only": "1"})
+	if ch.stalled(later) {
+		t.Error("expected messages held by emote-only mode not to be stalled")
+	}
+}
@@ -1,7 +1,6 @@
 package twitch
 
 import (
-	"fmt"
 	"time"
 
 	twitch "github.com/gempir/go-twitch-irc"
@@ -17,8 +16,5 @@ var HelpCommand = &Command{
 
 // Execute the command.
 func executeHelp(cl *Client, args []string, channel string, user twitch.User, message twitch.Message) {
-	cl.Say(
-		channel,
-		fmt.Sprintf("%s, to use my commands, mention me at the start or end of your message.", user.DisplayName),
-	)
+	cl.Reply(channel, message, "to use my commands, mention me at the start or end of your message.")
 }
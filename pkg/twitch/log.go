@@ -0,0 +1,37 @@
+package twitch
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	twitch "github.com/gempir/go-twitch-irc"
+	log "github.com/sirupsen/logrus"
+)
+
+// CorrelationIDTag is the tag that the Client adds to every chat message and
+// whisper that it receives, so that the log lines of handling a message can
+// be found together.
+const CorrelationIDTag = "roastedbot-correlation-id"
+
+// Logger returns a log entry for handling the message,
+// carrying the message's correlation ID if it has one.
+func (cl *Client) Logger(message twitch.Message) *log.Entry {
+	logger := cl.Log
+	if logger == nil {
+		logger = log.StandardLogger()
+	}
+	if id := message.Tags[CorrelationIDTag]; id != "" {
+		return logger.WithField("correlation_id", id)
+	}
+	return log.NewEntry(logger)
+}
+
+// correlate adds a new correlation ID to the message's tags.
+func correlate(message *twitch.Message) {
+	if message.Tags == nil {
+		message.Tags = make(map[string]string)
+	}
+	b := make([]byte, 8)
+	rand.Read(b)
+	message.Tags[CorrelationIDTag] = hex.EncodeToString(b)
+}
@@ -0,0 +1,24 @@
+package twitch
+
+import (
+	"testing"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+func TestCorrelationID(t *testing.T) {
+	cl, _ := newTestClient()
+	ids := []string{}
+	cl.OnNewMessage(func(channel string, user twitch.User, message twitch.Message) {
+		ids = append(ids, message.Tags[CorrelationIDTag])
+		if id := cl.Logger(message).Data["correlation_id"]; id != message.Tags[CorrelationIDTag] {
+			t.Errorf("expected log entry to carry correlation ID %s, got %v", message.Tags[CorrelationIDTag], id)
+		}
+	})
+
+	cl.handleMessage("channel", twitch.User{}, twitch.Message{})
+	cl.handleMessage("channel", twitch.User{}, twitch.Message{Tags: map[string]string{}})
+	if len(ids) != 2 || ids[0] == "" || ids[0] == ids[1] {
+		t.Errorf("expected each message to have a unique correlation ID, got %v", ids)
+	}
+}
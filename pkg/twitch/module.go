@@ -3,6 +3,7 @@ package twitch
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
 // Module is a named collection of Commands.
@@ -34,15 +35,58 @@ func (m *Module) AddCommand(c *Command) error {
 		return fmt.Errorf("attempted to add a nil Command to the module %s", m.Name)
 	}
 	if _, ok := m.commands[c.Name]; ok {
-		return fmt.Errorf("command '%s' already exists in module '%s'", c.Name, m.Name)
+		return fmt.Errorf("command '%s' in module '%s': %w", c.Name, m.Name, ErrExists)
 	}
 
-	m.commands[c.Name] = c
+	// Commands are often shared between channels, so each module keeps
+	// its own copy to track cooldowns and usage independently.
+	command := *c
+	m.commands[c.Name] = &command
+	return nil
+}
+
+// RemoveCommand removes a command from the module.
+func (m *Module) RemoveCommand(command string) error {
+	m.commandsMutex.Lock()
+	defer m.commandsMutex.Unlock()
+	if _, ok := m.commands[command]; !ok {
+		return fmt.Errorf("command '%s' in module '%s': %w", command, m.Name, ErrNotFound)
+	}
+	delete(m.commands, command)
+
+	m.enabledCommandsMutex.Lock()
+	delete(m.enabledCommands, command)
+	m.enabledCommandsMutex.Unlock()
+	return nil
+}
+
+// Command returns the command with the given name.
+func (m *Module) Command(name string) (*Command, error) {
+	m.commandsMutex.Lock()
+	defer m.commandsMutex.Unlock()
+	c, ok := m.commands[name]
+	if !ok {
+		return nil, fmt.Errorf("command '%s' in module '%s': %w", name, m.Name, ErrNotFound)
+	}
+	return c, nil
+}
+
+// SetCooldown changes the cooldown of a command in the module.
+func (m *Module) SetCooldown(command string, cooldown time.Duration) error {
+	c, err := m.Command(command)
+	if err != nil {
+		return err
+	}
+	m.commandsMutex.Lock()
+	c.Cooldown = cooldown
+	m.commandsMutex.Unlock()
 	return nil
 }
 
 // Commands returns all of the commands in this module.
 func (m *Module) Commands() []Command {
+	m.commandsMutex.Lock()
+	defer m.commandsMutex.Unlock()
 	commands := []Command{}
 	for _, c := range m.commands {
 		commands = append(commands, *c)
@@ -52,28 +96,24 @@ func (m *Module) Commands() []Command {
 
 // EnableCommand enables a command within the module.
 func (m *Module) EnableCommand(command string) error {
+	if _, err := m.Command(command); err != nil {
+		return err
+	}
 	m.enabledCommandsMutex.Lock()
 	defer m.enabledCommandsMutex.Unlock()
-	for _, c := range m.commands {
-		if c.Name == command {
-			m.enabledCommands[command] = true
-			return nil
-		}
-	}
-	return fmt.Errorf("command with name '%s' does not exist in module '%s'", command, m.Name)
+	m.enabledCommands[command] = true
+	return nil
 }
 
 // DisableCommand disables a command in the module.
 func (m *Module) DisableCommand(command string) error {
+	if _, err := m.Command(command); err != nil {
+		return err
+	}
 	m.enabledCommandsMutex.Lock()
 	defer m.enabledCommandsMutex.Unlock()
-	for _, c := range m.commands {
-		if c.Name == command {
-			m.enabledCommands[command] = false
-			return nil
-		}
-	}
-	return fmt.Errorf("command with name '%s' does not exist in module '%s'", command, m.Name)
+	m.enabledCommands[command] = false
+	return nil
 }
 
 // IsCommandEnabled determines if a command is enabled.
@@ -83,3 +123,15 @@ func (m *Module) IsCommandEnabled(command string) bool {
 	enabled, ok := m.enabledCommands[command]
 	return ok && enabled
 }
+
+// match returns the command in the module that is triggered by s, if any.
+func (m *Module) match(s string) *Command {
+	m.commandsMutex.Lock()
+	defer m.commandsMutex.Unlock()
+	for _, c := range m.commands {
+		if c.match(s) {
+			return c
+		}
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package twitch
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestNewModule(t *testing.T) {
 	name := "test"
@@ -103,3 +106,19 @@ func TestCommands(t *testing.T) {
 		}
 	}
 }
+
+func TestRemoveCommand(t *testing.T) {
+	m := newModule("test")
+	m.AddCommand(&Command{Name: "command"})
+	m.EnableCommand("command")
+
+	if err := m.RemoveCommand("command"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if m.IsCommandEnabled("command") {
+		t.Error("expected removed command to not be enabled")
+	}
+	if err := m.RemoveCommand("command"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
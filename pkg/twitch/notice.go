@@ -0,0 +1,73 @@
+package twitch
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+// Reasons that the bot can be restricted from chatting in a channel.
+const (
+	RestrictionBanned      = "banned"
+	RestrictionTimedOut    = "timed_out"
+	RestrictionSuspended   = "channel_suspended"
+	RestrictionRateLimited = "rate_limited"
+)
+
+const (
+	// defaultTimeout is used when the length of a timeout cannot be determined.
+	defaultTimeout = time.Minute * 10
+	// rateLimitBackoff is how long the bot stops chatting after being rate limited.
+	rateLimitBackoff = time.Second * 30
+)
+
+var timeoutPattern = regexp.MustCompile(`(\d+) more seconds?`)
+
+// Restriction describes why the bot cannot chat in a channel.
+type Restriction struct {
+	Reason string `json:"reason"`
+	// Until is when the restriction expires, or zero if it is permanent.
+	Until time.Time `json:"until"`
+}
+
+// Permanent determines if the restriction never expires.
+func (r Restriction) Permanent() bool {
+	return r.Until.IsZero()
+}
+
+// active determines if the restriction applies at the given time.
+func (r Restriction) active(now time.Time) bool {
+	return r.Reason != "" && (r.Permanent() || r.Until.After(now))
+}
+
+// parseNotice determines if a NOTICE message restricts the bot from chatting.
+func parseNotice(message twitch.Message, now time.Time) (Restriction, bool) {
+	switch message.Tags["msg-id"] {
+	case "msg_banned":
+		return Restriction{Reason: RestrictionBanned}, true
+	case "msg_channel_suspended":
+		return Restriction{Reason: RestrictionSuspended}, true
+	case "msg_timedout":
+		timeout := defaultTimeout
+		if m := timeoutPattern.FindStringSubmatch(message.Text); m != nil {
+			if s, err := strconv.Atoi(m[1]); err == nil {
+				timeout = time.Duration(s) * time.Second
+			}
+		}
+		return Restriction{Reason: RestrictionTimedOut, Until: now.Add(timeout)}, true
+	case "msg_ratelimit":
+		return Restriction{Reason: RestrictionRateLimited, Until: now.Add(rateLimitBackoff)}, true
+	}
+	return Restriction{}, false
+}
+
+// parseClearChat determines if a CLEARCHAT message that targets the bot
+// is a timeout or a ban.
+func parseClearChat(message twitch.Message, now time.Time) Restriction {
+	if s, err := strconv.Atoi(message.Tags["ban-duration"]); err == nil {
+		return Restriction{Reason: RestrictionTimedOut, Until: now.Add(time.Duration(s) * time.Second)}
+	}
+	return Restriction{Reason: RestrictionBanned}
+}
@@ -0,0 +1,120 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+func TestParseNotice(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		line      string
+		reason    string
+		permanent bool
+		until     time.Time
+	}{
+		{
+			name:      "banned",
+			line:      "@msg-id=msg_banned :tmi.twitch.tv NOTICE #channel :You are permanently banned from talking in channel.",
+			reason:    RestrictionBanned,
+			permanent: true,
+		},
+		{
+			name:   "timed out",
+			line:   "@msg-id=msg_timedout :tmi.twitch.tv NOTICE #channel :You are timed out for 568 more seconds.",
+			reason: RestrictionTimedOut,
+			until:  now.Add(time.Second * 568),
+		},
+		{
+			name:      "suspended",
+			line:      "@msg-id=msg_channel_suspended :tmi.twitch.tv NOTICE #channel :This channel has been suspended.",
+			reason:    RestrictionSuspended,
+			permanent: true,
+		},
+	}
+
+	for _, test := range tests {
+		_, _, message := twitch.ParseMessage(test.line)
+		r, ok := parseNotice(*message, now)
+		if !ok {
+			t.Errorf("%s: expected notice to restrict the bot", test.name)
+			continue
+		}
+		if r.Reason != test.reason {
+			t.Errorf("%s: expected reason %s, got %s", test.name, test.reason, r.Reason)
+		}
+		if r.Permanent() != test.permanent {
+			t.Errorf("%s: expected permanent to be %v", test.name, test.permanent)
+		}
+		if !test.permanent && !r.Until.Equal(test.until) {
+			t.Errorf("%s: expected restriction until %v, got %v", test.name, test.until, r.Until)
+		}
+	}
+}
+
+func TestParseNotice_Unrelated(t *testing.T) {
+	_, _, message := twitch.ParseMessage("@msg-id=host_on :tmi.twitch.tv NOTICE #channel :Now hosting someone.")
+	if _, ok := parseNotice(*message, time.Now()); ok {
+		t.Error("expected unrelated notice to not restrict the bot")
+	}
+}
+
+func TestRestrict_PausesOutbox(t *testing.T) {
+	cl, s := newTestClient()
+	cl.AddChannel("channel")
+	cl.restrict("channel", Restriction{Reason: RestrictionTimedOut, Until: time.Now().Add(time.Millisecond * 50)})
+
+	cl.Say("channel", "later")
+	time.Sleep(time.Millisecond * 20)
+	if len(s.messages()) != 0 {
+		t.Fatal("expected message to be held while the bot is timed out")
+	}
+	waitFor(t, func() bool { return len(s.messages()) == 1 })
+}
+
+func TestRestrict_AutoPart(t *testing.T) {
+	cl, _ := newTestClient()
+	cl.SetAutoPartBanned(true)
+	cl.AddChannel("channel")
+
+	cl.restrict("channel", Restriction{Reason: RestrictionBanned})
+	if _, err := cl.Channel("channel"); err == nil {
+		t.Error("expected the client to leave a channel it is banned from")
+	}
+}
+
+func TestRestrict_ReadmittedAfterBan(t *testing.T) {
+	message := twitch.Message{Tags: map[string]string{}}
+	for name, readmit := range map[string]func(cl *Client){
+		"roomstate": func(cl *Client) { cl.handleRoomState("channel", twitch.User{}, message) },
+		"userstate": func(cl *Client) { cl.handleUserState("channel", twitch.User{}, message) },
+		"join":      func(cl *Client) { cl.handleUserJoin("channel", "bot") },
+	} {
+		cl, s := newTestClient()
+		cl.AddChannel("channel")
+		cl.restrict("channel", Restriction{Reason: RestrictionBanned})
+
+		cl.Say("channel", "hello")
+		time.Sleep(time.Millisecond * 20)
+		if len(s.messages()) != 0 {
+			t.Fatalf("%s: expected message to be held while the bot is banned", name)
+		}
+		readmit(cl)
+		waitFor(t, func() bool { return len(s.messages()) == 1 })
+	}
+}
+
+func TestRestrict_TimeoutNotReadmitted(t *testing.T) {
+	cl, _ := newTestClient()
+	cl.AddChannel("channel")
+	cl.restrict("channel", Restriction{Reason: RestrictionTimedOut, Until: time.Now().Add(time.Minute)})
+
+	cl.handleRoomState("channel", twitch.User{}, twitch.Message{Tags: map[string]string{}})
+	ch, _ := cl.Channel("channel")
+	if ch.Restriction().Reason != RestrictionTimedOut {
+		t.Error("expected a timeout to last until it expires")
+	}
+}
@@ -0,0 +1,97 @@
+package twitch
+
+import (
+	"sync"
+	"time"
+)
+
+// maxOutboxSize is the number of messages that can be waiting to be sent in
+// a channel. When the outbox is full the oldest message is discarded.
+const maxOutboxSize = 50
+
+// outgoing is a message waiting to be sent.
+type outgoing struct {
+	text string
+	// replyTo is the ID of the message that this message replies to, if any.
+	replyTo string
+	// to is the user that a whisper is sent to.
+	to string
+	// queued is when the message was added to the outbox.
+	queued time.Time
+}
+
+// outbox is a queue of messages waiting to be sent to a channel.
+type outbox struct {
+	done       chan struct{}
+	items      []outgoing
+	itemsMutex *sync.Mutex
+	wake       chan struct{}
+}
+
+func newOutbox() *outbox {
+	return &outbox{
+		done:       make(chan struct{}),
+		itemsMutex: &sync.Mutex{},
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// push adds a message to the back of the queue.
+// It returns false if a message had to be discarded to make room.
+func (o *outbox) push(m outgoing) bool {
+	o.itemsMutex.Lock()
+	ok := true
+	if len(o.items) >= maxOutboxSize {
+		o.items = o.items[1:]
+		ok = false
+	}
+	m.queued = time.Now()
+	o.items = append(o.items, m)
+	o.itemsMutex.Unlock()
+	o.notify()
+	return ok
+}
+
+// peek returns the message at the front of the queue without removing it.
+func (o *outbox) peek() (outgoing, bool) {
+	o.itemsMutex.Lock()
+	defer o.itemsMutex.Unlock()
+	if len(o.items) == 0 {
+		return outgoing{}, false
+	}
+	return o.items[0], true
+}
+
+// pop removes the message at the front of the queue.
+func (o *outbox) pop() {
+	o.itemsMutex.Lock()
+	defer o.itemsMutex.Unlock()
+	if len(o.items) > 0 {
+		o.items = o.items[1:]
+	}
+}
+
+// len returns the number of messages in the queue.
+func (o *outbox) len() int {
+	o.itemsMutex.Lock()
+	defer o.itemsMutex.Unlock()
+	return len(o.items)
+}
+
+// notify wakes the goroutine draining the outbox so that it
+// can re-evaluate whether the next message can be sent.
+func (o *outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// close stops the goroutine draining the outbox.
+func (o *outbox) close() {
+	select {
+	case <-o.done:
+	default:
+		close(o.done)
+	}
+}
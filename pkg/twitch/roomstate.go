@@ -0,0 +1,208 @@
+package twitch
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+// RoomState is the chat settings of a channel, as sent by twitch in ROOMSTATE messages.
+type RoomState struct {
+	EmoteOnly bool `json:"emoteOnly"`
+	// FollowersOnly is the number of minutes a user must have followed
+	// the channel for to chat, or -1 if followers-only mode is off.
+	FollowersOnly int  `json:"followersOnly"`
+	R9K           bool `json:"r9k"`
+	// Slow is the number of seconds users must wait between messages.
+	Slow     int  `json:"slow"`
+	SubsOnly bool `json:"subsOnly"`
+}
+
+// UserState is the bot's status in a channel, as sent by twitch in USERSTATE messages.
+type UserState struct {
+	Broadcaster bool `json:"broadcaster"`
+	Moderator   bool `json:"moderator"`
+	Subscriber  bool `json:"subscriber"`
+	VIP         bool `json:"vip"`
+}
+
+// Exempt determines if the bot is exempt from the slow mode and subs-only
+// mode of the channel. Only the broadcaster and moderators can chat in emote-only mode.
+func (u UserState) Exempt() bool {
+	return u.Broadcaster || u.Moderator || u.VIP
+}
+
+// Restricted modes decide what happens to messages that the bot is
+// not allowed to send because of emote-only or subs-only mode.
+const (
+	// RestrictedQueue holds messages until the mode is turned off.
+	RestrictedQueue = "queue"
+	// RestrictedDrop discards messages.
+	RestrictedDrop = "drop"
+)
+
+// Reply modes decide how the bot responds to the messages that trigger its commands.
+const (
+	// ReplyMention prefixes responses with the user's display name.
+	ReplyMention = "mention"
+	// ReplyThread sends responses as threaded replies to the triggering message.
+	ReplyThread = "thread"
+	// ReplyAction sends responses as /me actions.
+	ReplyAction = "action"
+)
+
+// channelState holds the state of a channel that changes while the bot is connected.
+type channelState struct {
+	joined   bool
+	lastSent time.Time
+	// moderators are the users that have been seen chatting with a moderator badge.
+	moderators     map[string]bool
+	mutex          *sync.Mutex
+	replyMode      string
+	restrictedMode string
+	restriction    Restriction
+	room           RoomState
+	user           UserState
+}
+
+func newChannelState() *channelState {
+	return &channelState{
+		moderators:     make(map[string]bool),
+		mutex:          &sync.Mutex{},
+		replyMode:      ReplyMention,
+		restrictedMode: RestrictedQueue,
+		room:           RoomState{FollowersOnly: -1},
+	}
+}
+
+// updateRoom applies the tags of a ROOMSTATE message. Twitch only sends the
+// tags that have changed, so any missing tags are left untouched.
+func (s *channelState) updateRoom(tags map[string]string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if v, ok := tags["emote-only"]; ok {
+		s.room.EmoteOnly = v == "1"
+	}
+	if v, ok := tags["followers-only"]; ok {
+		if i, err := strconv.Atoi(v); err == nil {
+			s.room.FollowersOnly = i
+		}
+	}
+	if v, ok := tags["r9k"]; ok {
+		s.room.R9K = v == "1"
+	}
+	if v, ok := tags["slow"]; ok {
+		if i, err := strconv.Atoi(v); err == nil {
+			s.room.Slow = i
+		}
+	}
+	if v, ok := tags["subs-only"]; ok {
+		s.room.SubsOnly = v == "1"
+	}
+}
+
+// updateUser applies the badges of a USERSTATE message.
+func (s *channelState) updateUser(user twitch.User, message twitch.Message) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, broadcaster := user.Badges["broadcaster"]
+	_, moderator := user.Badges["moderator"]
+	_, subscriber := user.Badges["subscriber"]
+	_, vip := user.Badges["vip"]
+	s.user = UserState{
+		Broadcaster: broadcaster,
+		Moderator:   moderator || message.Tags["mod"] == "1",
+		Subscriber:  subscriber || message.Tags["subscriber"] == "1",
+		VIP:         vip,
+	}
+}
+
+// updateModerator records whether the sender of a chat message is a moderator.
+// Twitch does not announce moderators, so they are learned from their badges.
+func (s *channelState) updateModerator(user twitch.User, message twitch.Message) {
+	_, moderator := user.Badges["moderator"]
+	moderator = moderator || message.Tags["mod"] == "1"
+	login := strings.ToLower(user.Username)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if moderator {
+		s.moderators[login] = true
+	} else {
+		delete(s.moderators, login)
+	}
+}
+
+func (s *channelState) isModerator(login string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.moderators[strings.ToLower(login)]
+}
+
+func (s *channelState) restrict(r Restriction) {
+	s.mutex.Lock()
+	s.restriction = r
+	s.mutex.Unlock()
+}
+
+// readmit lifts a ban or suspension, as twitch only sends ROOMSTATE, USERSTATE
+// and JOIN to users that are allowed in the channel. If the bot is still
+// banned, its next message restricts it again. Timeouts expire by themselves.
+func (s *channelState) readmit() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.restriction.Permanent() {
+		s.restriction = Restriction{}
+	}
+}
+
+// sendDelay determines how long the bot must wait before it can send a message.
+// restricted is true if the bot cannot currently send messages at all.
+func (s *channelState) sendDelay(now time.Time) (delay time.Duration, restricted bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.restriction.active(now) {
+		if s.restriction.Permanent() {
+			return 0, true
+		}
+		return s.restriction.Until.Sub(now), false
+	}
+	s.restriction = Restriction{}
+	if s.room.EmoteOnly && !s.user.Broadcaster && !s.user.Moderator {
+		return 0, true
+	}
+	if s.user.Exempt() {
+		return 0, false
+	}
+	if s.room.SubsOnly && !s.user.Subscriber {
+		return 0, true
+	}
+	if s.room.Slow > 0 {
+		next := s.lastSent.Add(time.Duration(s.room.Slow) * time.Second)
+		if next.After(now) {
+			return next.Sub(now), false
+		}
+	}
+	return 0, false
+}
+
+func (s *channelState) setJoined(joined bool) {
+	s.mutex.Lock()
+	s.joined = joined
+	s.mutex.Unlock()
+}
+
+func (s *channelState) isJoined() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.joined
+}
+
+func (s *channelState) sent(now time.Time) {
+	s.mutex.Lock()
+	s.lastSent = now
+	s.mutex.Unlock()
+}
@@ -0,0 +1,127 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+func TestUpdateRoom_Partial(t *testing.T) {
+	s := newChannelState()
+	s.updateRoom(map[string]string{
+		"emote-only":     "0",
+		"followers-only": "-1",
+		"r9k":            "0",
+		"slow":           "0",
+		"subs-only":      "0",
+	})
+	s.updateRoom(map[string]string{"slow": "10"})
+
+	if s.room.Slow != 10 {
+		t.Errorf("expected slow to be 10, got %d", s.room.Slow)
+	}
+	if s.room.FollowersOnly != -1 {
+		t.Errorf("expected followers-only to be unchanged, got %d", s.room.FollowersOnly)
+	}
+}
+
+func TestSendDelay_Slow(t *testing.T) {
+	s := newChannelState()
+	s.updateRoom(map[string]string{"slow": "30"})
+
+	now := time.Now()
+	s.sent(now)
+
+	delay, restricted := s.sendDelay(now.Add(time.Second * 10))
+	if restricted {
+		t.Error("expected slow mode to not restrict sending")
+	}
+	if delay != time.Second*20 {
+		t.Errorf("expected delay of 20s, got %v", delay)
+	}
+}
+
+func TestSendDelay_Moderator(t *testing.T) {
+	s := newChannelState()
+	s.updateRoom(map[string]string{"slow": "30", "emote-only": "1"})
+	s.updateUser(twitch.User{Badges: map[string]int{"moderator": 1}}, twitch.Message{})
+
+	now := time.Now()
+	s.sent(now)
+
+	delay, restricted := s.sendDelay(now)
+	if restricted || delay != 0 {
+		t.Errorf("expected moderator to be exempt, got delay %v and restricted %v", delay, restricted)
+	}
+}
+
+func TestUserState_Exempt(t *testing.T) {
+	tests := []struct {
+		badge  string
+		exempt bool
+	}{
+		{"broadcaster", true},
+		{"moderator", true},
+		{"vip", true},
+		{"subscriber", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		s := newChannelState()
+		s.updateRoom(map[string]string{"slow": "30"})
+		s.updateUser(twitch.User{Badges: map[string]int{test.badge: 1}}, twitch.Message{})
+		if s.user.Exempt() != test.exempt {
+			t.Errorf("%s: expected exempt %t, got %t", test.badge, test.exempt, !test.exempt)
+		}
+
+		now := time.Now()
+		s.sent(now)
+		if delay, _ := s.sendDelay(now); (delay == 0) != test.exempt {
+			t.Errorf("%s: expected exempt %t from slow mode, got delay %v", test.badge, test.exempt, delay)
+		}
+	}
+}
+
+func TestSendDelay_VIPEmoteOnly(t *testing.T) {
+	s := newChannelState()
+	s.updateRoom(map[string]string{"emote-only": "1"})
+	s.updateUser(twitch.User{Badges: map[string]int{"vip": 1}}, twitch.Message{})
+
+	if _, restricted := s.sendDelay(time.Now()); !restricted {
+		t.Error("expected a VIP to be restricted by emote-only mode")
+	}
+}
+
+func TestSendDelay_SubsOnly(t *testing.T) {
+	s := newChannelState()
+	s.updateRoom(map[string]string{"subs-only": "1"})
+
+	if _, restricted := s.sendDelay(time.Now()); !restricted {
+		t.Error("expected subs-only mode to restrict a non-subscriber")
+	}
+
+	s.updateUser(twitch.User{Badges: map[string]int{"subscriber": 6}}, twitch.Message{})
+	if _, restricted := s.sendDelay(time.Now()); restricted {
+		t.Error("expected subs-only mode to not restrict a subscriber")
+	}
+}
+
+func TestIsModerator(t *testing.T) {
+	ch := newChannel("streamer")
+	mod := twitch.User{Username: "Mod", Badges: map[string]int{"moderator": 1}}
+	ch.state.updateModerator(mod, twitch.Message{})
+
+	if !ch.IsModerator("mod") {
+		t.Error("expected user with moderator badge to be a moderator")
+	}
+	if !ch.IsModerator("streamer") {
+		t.Error("expected broadcaster to be a moderator")
+	}
+
+	mod.Badges = map[string]int{}
+	ch.state.updateModerator(mod, twitch.Message{})
+	if ch.IsModerator("mod") {
+		t.Error("expected user to no longer be a moderator after chatting without the badge")
+	}
+}
@@ -0,0 +1,104 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+)
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// readMethods are the methods that do not change the state of the bot.
+var readMethods = map[string]bool{
+	"/roastedbot.botadmin.v1.BotAdmin/ListChannels":       true,
+	"/roastedbot.botadmin.v1.BotAdmin/GetChannel":         true,
+	"/roastedbot.botadmin.v1.BotAdmin/ListCustomCommands": true,
+	"/roastedbot.botadmin.v1.BotAdmin/ListAuditEntries":   true,
+	"/roastedbot.botadmin.v1.BotAdmin/StreamEvents":       true,
+}
+
+// authenticate returns the principal that owns the token in the call's metadata.
+func (s *server) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get("authorization") {
+		if !strings.HasPrefix(v, "Bearer ") {
+			continue
+		}
+		if p, ok := s.tokens.Authenticate(strings.TrimPrefix(v, "Bearer ")); ok {
+			return context.WithValue(ctx, principalKey, p), nil
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "a valid API token is required")
+}
+
+// authorize checks that the principal of the call has the scope.
+// If channel is not empty the principal must also have access to it.
+func authorize(ctx context.Context, scope auth.Scope, channel string) error {
+	p, _ := ctx.Value(principalKey).(*auth.Principal)
+	if p == nil || !p.Has(scope) || (channel != "" && !p.CanAccess(channel)) {
+		return status.Errorf(codes.PermissionDenied, "this token does not have the '%s' scope for this resource", scope)
+	}
+	return nil
+}
+
+func principalName(ctx context.Context) string {
+	if p, ok := ctx.Value(principalKey).(*auth.Principal); ok {
+		return p.Name
+	}
+	return ""
+}
+
+func (s *server) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := handler(ctx, req)
+	if !readMethods[info.FullMethod] {
+		s.audit(ctx, info.FullMethod, err)
+	}
+	return resp, err
+}
+
+func (s *server) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// audit logs a call that may have changed the state of the bot,
+// along with the principal that made it.
+func (s *server) audit(ctx context.Context, method string, err error) {
+	remote := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remote = p.Addr.String()
+	}
+	s.log.WithFields(log.Fields{
+		"token":  principalName(ctx),
+		"method": method,
+		"code":   status.Code(err).String(),
+		"remote": remote,
+	}).Info("grpc request")
+}
+
+// authenticatedStream carries the principal of a streaming call.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
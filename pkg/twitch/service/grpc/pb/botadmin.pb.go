@@ -0,0 +1,1993 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: botadmin.proto
+
+package pb
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type RoomState struct {
+	EmoteOnly bool `protobuf:"varint,1,opt,name=emote_only,json=emoteOnly,proto3" json:"emote_only,omitempty"`
+	// followers_only is the number of minutes a user must have followed
+	// the channel for to chat, or -1 if followers-only mode is off.
+	FollowersOnly        int32    `protobuf:"varint,2,opt,name=followers_only,json=followersOnly,proto3" json:"followers_only,omitempty"`
+	R9K                  bool     `protobuf:"varint,3,opt,name=r9k,proto3" json:"r9k,omitempty"`
+	Slow                 int32    `protobuf:"varint,4,opt,name=slow,proto3" json:"slow,omitempty"`
+	SubsOnly             bool     `protobuf:"varint,5,opt,name=subs_only,json=subsOnly,proto3" json:"subs_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoomState) Reset()         { *m = RoomState{} }
+func (m *RoomState) String() string { return proto.CompactTextString(m) }
+func (*RoomState) ProtoMessage()    {}
+func (*RoomState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{0}
+}
+func (m *RoomState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoomState.Unmarshal(m, b)
+}
+func (m *RoomState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoomState.Marshal(b, m, deterministic)
+}
+func (dst *RoomState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoomState.Merge(dst, src)
+}
+func (m *RoomState) XXX_Size() int {
+	return xxx_messageInfo_RoomState.Size(m)
+}
+func (m *RoomState) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoomState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoomState proto.InternalMessageInfo
+
+func (m *RoomState) GetEmoteOnly() bool {
+	if m != nil {
+		return m.EmoteOnly
+	}
+	return false
+}
+
+func (m *RoomState) GetFollowersOnly() int32 {
+	if m != nil {
+		return m.FollowersOnly
+	}
+	return 0
+}
+
+func (m *RoomState) GetR9K() bool {
+	if m != nil {
+		return m.R9K
+	}
+	return false
+}
+
+func (m *RoomState) GetSlow() int32 {
+	if m != nil {
+		return m.Slow
+	}
+	return 0
+}
+
+func (m *RoomState) GetSubsOnly() bool {
+	if m != nil {
+		return m.SubsOnly
+	}
+	return false
+}
+
+type UserState struct {
+	Broadcaster          bool     `protobuf:"varint,1,opt,name=broadcaster,proto3" json:"broadcaster,omitempty"`
+	Moderator            bool     `protobuf:"varint,2,opt,name=moderator,proto3" json:"moderator,omitempty"`
+	Subscriber           bool     `protobuf:"varint,3,opt,name=subscriber,proto3" json:"subscriber,omitempty"`
+	Vip                  bool     `protobuf:"varint,4,opt,name=vip,proto3" json:"vip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserState) Reset()         { *m = UserState{} }
+func (m *UserState) String() string { return proto.CompactTextString(m) }
+func (*UserState) ProtoMessage()    {}
+func (*UserState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{1}
+}
+func (m *UserState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserState.Unmarshal(m, b)
+}
+func (m *UserState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserState.Marshal(b, m, deterministic)
+}
+func (dst *UserState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserState.Merge(dst, src)
+}
+func (m *UserState) XXX_Size() int {
+	return xxx_messageInfo_UserState.Size(m)
+}
+func (m *UserState) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserState proto.InternalMessageInfo
+
+func (m *UserState) GetBroadcaster() bool {
+	if m != nil {
+		return m.Broadcaster
+	}
+	return false
+}
+
+func (m *UserState) GetModerator() bool {
+	if m != nil {
+		return m.Moderator
+	}
+	return false
+}
+
+func (m *UserState) GetSubscriber() bool {
+	if m != nil {
+		return m.Subscriber
+	}
+	return false
+}
+
+func (m *UserState) GetVip() bool {
+	if m != nil {
+		return m.Vip
+	}
+	return false
+}
+
+type Restriction struct {
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// until is an RFC 3339 timestamp, or empty if the restriction is permanent.
+	Until                string   `protobuf:"bytes,2,opt,name=until,proto3" json:"until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Restriction) Reset()         { *m = Restriction{} }
+func (m *Restriction) String() string { return proto.CompactTextString(m) }
+func (*Restriction) ProtoMessage()    {}
+func (*Restriction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{2}
+}
+func (m *Restriction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Restriction.Unmarshal(m, b)
+}
+func (m *Restriction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Restriction.Marshal(b, m, deterministic)
+}
+func (dst *Restriction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Restriction.Merge(dst, src)
+}
+func (m *Restriction) XXX_Size() int {
+	return xxx_messageInfo_Restriction.Size(m)
+}
+func (m *Restriction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Restriction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Restriction proto.InternalMessageInfo
+
+func (m *Restriction) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Restriction) GetUntil() string {
+	if m != nil {
+		return m.Until
+	}
+	return ""
+}
+
+type Channel struct {
+	Name           string     `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	RoomState      *RoomState `protobuf:"bytes,2,opt,name=room_state,json=roomState,proto3" json:"room_state,omitempty"`
+	UserState      *UserState `protobuf:"bytes,3,opt,name=user_state,json=userState,proto3" json:"user_state,omitempty"`
+	ReplyMode      string     `protobuf:"bytes,4,opt,name=reply_mode,json=replyMode,proto3" json:"reply_mode,omitempty"`
+	RestrictedMode string     `protobuf:"bytes,5,opt,name=restricted_mode,json=restrictedMode,proto3" json:"restricted_mode,omitempty"`
+	// restriction is unset if the bot can chat in the channel.
+	Restriction          *Restriction `protobuf:"bytes,6,opt,name=restriction,proto3" json:"restriction,omitempty"`
+	QueueLength          int32        `protobuf:"varint,7,opt,name=queue_length,json=queueLength,proto3" json:"queue_length,omitempty"`
+	Modules              []*Module    `protobuf:"bytes,8,rep,name=modules,proto3" json:"modules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Channel) Reset()         { *m = Channel{} }
+func (m *Channel) String() string { return proto.CompactTextString(m) }
+func (*Channel) ProtoMessage()    {}
+func (*Channel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{3}
+}
+func (m *Channel) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Channel.Unmarshal(m, b)
+}
+func (m *Channel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Channel.Marshal(b, m, deterministic)
+}
+func (dst *Channel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Channel.Merge(dst, src)
+}
+func (m *Channel) XXX_Size() int {
+	return xxx_messageInfo_Channel.Size(m)
+}
+func (m *Channel) XXX_DiscardUnknown() {
+	xxx_messageInfo_Channel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Channel proto.InternalMessageInfo
+
+func (m *Channel) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Channel) GetRoomState() *RoomState {
+	if m != nil {
+		return m.RoomState
+	}
+	return nil
+}
+
+func (m *Channel) GetUserState() *UserState {
+	if m != nil {
+		return m.UserState
+	}
+	return nil
+}
+
+func (m *Channel) GetReplyMode() string {
+	if m != nil {
+		return m.ReplyMode
+	}
+	return ""
+}
+
+func (m *Channel) GetRestrictedMode() string {
+	if m != nil {
+		return m.RestrictedMode
+	}
+	return ""
+}
+
+func (m *Channel) GetRestriction() *Restriction {
+	if m != nil {
+		return m.Restriction
+	}
+	return nil
+}
+
+func (m *Channel) GetQueueLength() int32 {
+	if m != nil {
+		return m.QueueLength
+	}
+	return 0
+}
+
+func (m *Channel) GetModules() []*Module {
+	if m != nil {
+		return m.Modules
+	}
+	return nil
+}
+
+type Module struct {
+	Name                 string     `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Enabled              bool       `protobuf:"varint,2,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Commands             []*Command `protobuf:"bytes,3,rep,name=commands,proto3" json:"commands,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Module) Reset()         { *m = Module{} }
+func (m *Module) String() string { return proto.CompactTextString(m) }
+func (*Module) ProtoMessage()    {}
+func (*Module) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{4}
+}
+func (m *Module) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Module.Unmarshal(m, b)
+}
+func (m *Module) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Module.Marshal(b, m, deterministic)
+}
+func (dst *Module) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Module.Merge(dst, src)
+}
+func (m *Module) XXX_Size() int {
+	return xxx_messageInfo_Module.Size(m)
+}
+func (m *Module) XXX_DiscardUnknown() {
+	xxx_messageInfo_Module.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Module proto.InternalMessageInfo
+
+func (m *Module) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Module) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *Module) GetCommands() []*Command {
+	if m != nil {
+		return m.Commands
+	}
+	return nil
+}
+
+type Command struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Use     string `protobuf:"bytes,2,opt,name=use,proto3" json:"use,omitempty"`
+	Enabled bool   `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// cooldown is a duration such as "30s".
+	Cooldown string `protobuf:"bytes,4,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
+	// last_used is an RFC 3339 timestamp, or empty if the command has not been used.
+	LastUsed             string   `protobuf:"bytes,5,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	Response             string   `protobuf:"bytes,6,opt,name=response,proto3" json:"response,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Command) Reset()         { *m = Command{} }
+func (m *Command) String() string { return proto.CompactTextString(m) }
+func (*Command) ProtoMessage()    {}
+func (*Command) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{5}
+}
+func (m *Command) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Command.Unmarshal(m, b)
+}
+func (m *Command) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Command.Marshal(b, m, deterministic)
+}
+func (dst *Command) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Command.Merge(dst, src)
+}
+func (m *Command) XXX_Size() int {
+	return xxx_messageInfo_Command.Size(m)
+}
+func (m *Command) XXX_DiscardUnknown() {
+	xxx_messageInfo_Command.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Command proto.InternalMessageInfo
+
+func (m *Command) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Command) GetUse() string {
+	if m != nil {
+		return m.Use
+	}
+	return ""
+}
+
+func (m *Command) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *Command) GetCooldown() string {
+	if m != nil {
+		return m.Cooldown
+	}
+	return ""
+}
+
+func (m *Command) GetLastUsed() string {
+	if m != nil {
+		return m.LastUsed
+	}
+	return ""
+}
+
+func (m *Command) GetResponse() string {
+	if m != nil {
+		return m.Response
+	}
+	return ""
+}
+
+type CustomCommand struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Response             string   `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	Cooldown             string   `protobuf:"bytes,3,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CustomCommand) Reset()         { *m = CustomCommand{} }
+func (m *CustomCommand) String() string { return proto.CompactTextString(m) }
+func (*CustomCommand) ProtoMessage()    {}
+func (*CustomCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{6}
+}
+func (m *CustomCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomCommand.Unmarshal(m, b)
+}
+func (m *CustomCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomCommand.Marshal(b, m, deterministic)
+}
+func (dst *CustomCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomCommand.Merge(dst, src)
+}
+func (m *CustomCommand) XXX_Size() int {
+	return xxx_messageInfo_CustomCommand.Size(m)
+}
+func (m *CustomCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomCommand proto.InternalMessageInfo
+
+func (m *CustomCommand) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CustomCommand) GetResponse() string {
+	if m != nil {
+		return m.Response
+	}
+	return ""
+}
+
+func (m *CustomCommand) GetCooldown() string {
+	if m != nil {
+		return m.Cooldown
+	}
+	return ""
+}
+
+type ListChannelsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListChannelsRequest) Reset()         { *m = ListChannelsRequest{} }
+func (m *ListChannelsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListChannelsRequest) ProtoMessage()    {}
+func (*ListChannelsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{7}
+}
+func (m *ListChannelsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChannelsRequest.Unmarshal(m, b)
+}
+func (m *ListChannelsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChannelsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListChannelsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChannelsRequest.Merge(dst, src)
+}
+func (m *ListChannelsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListChannelsRequest.Size(m)
+}
+func (m *ListChannelsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChannelsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChannelsRequest proto.InternalMessageInfo
+
+type ListChannelsResponse struct {
+	Channels             []*Channel `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListChannelsResponse) Reset()         { *m = ListChannelsResponse{} }
+func (m *ListChannelsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListChannelsResponse) ProtoMessage()    {}
+func (*ListChannelsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{8}
+}
+func (m *ListChannelsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListChannelsResponse.Unmarshal(m, b)
+}
+func (m *ListChannelsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListChannelsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListChannelsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListChannelsResponse.Merge(dst, src)
+}
+func (m *ListChannelsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListChannelsResponse.Size(m)
+}
+func (m *ListChannelsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListChannelsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListChannelsResponse proto.InternalMessageInfo
+
+func (m *ListChannelsResponse) GetChannels() []*Channel {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type GetChannelRequest struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetChannelRequest) Reset()         { *m = GetChannelRequest{} }
+func (m *GetChannelRequest) String() string { return proto.CompactTextString(m) }
+func (*GetChannelRequest) ProtoMessage()    {}
+func (*GetChannelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{9}
+}
+func (m *GetChannelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetChannelRequest.Unmarshal(m, b)
+}
+func (m *GetChannelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetChannelRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetChannelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetChannelRequest.Merge(dst, src)
+}
+func (m *GetChannelRequest) XXX_Size() int {
+	return xxx_messageInfo_GetChannelRequest.Size(m)
+}
+func (m *GetChannelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetChannelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetChannelRequest proto.InternalMessageInfo
+
+func (m *GetChannelRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+type JoinChannelRequest struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JoinChannelRequest) Reset()         { *m = JoinChannelRequest{} }
+func (m *JoinChannelRequest) String() string { return proto.CompactTextString(m) }
+func (*JoinChannelRequest) ProtoMessage()    {}
+func (*JoinChannelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{10}
+}
+func (m *JoinChannelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JoinChannelRequest.Unmarshal(m, b)
+}
+func (m *JoinChannelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JoinChannelRequest.Marshal(b, m, deterministic)
+}
+func (dst *JoinChannelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JoinChannelRequest.Merge(dst, src)
+}
+func (m *JoinChannelRequest) XXX_Size() int {
+	return xxx_messageInfo_JoinChannelRequest.Size(m)
+}
+func (m *JoinChannelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_JoinChannelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JoinChannelRequest proto.InternalMessageInfo
+
+func (m *JoinChannelRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+type PartChannelRequest struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PartChannelRequest) Reset()         { *m = PartChannelRequest{} }
+func (m *PartChannelRequest) String() string { return proto.CompactTextString(m) }
+func (*PartChannelRequest) ProtoMessage()    {}
+func (*PartChannelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{11}
+}
+func (m *PartChannelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartChannelRequest.Unmarshal(m, b)
+}
+func (m *PartChannelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartChannelRequest.Marshal(b, m, deterministic)
+}
+func (dst *PartChannelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartChannelRequest.Merge(dst, src)
+}
+func (m *PartChannelRequest) XXX_Size() int {
+	return xxx_messageInfo_PartChannelRequest.Size(m)
+}
+func (m *PartChannelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartChannelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartChannelRequest proto.InternalMessageInfo
+
+func (m *PartChannelRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+type PartChannelResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PartChannelResponse) Reset()         { *m = PartChannelResponse{} }
+func (m *PartChannelResponse) String() string { return proto.CompactTextString(m) }
+func (*PartChannelResponse) ProtoMessage()    {}
+func (*PartChannelResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{12}
+}
+func (m *PartChannelResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartChannelResponse.Unmarshal(m, b)
+}
+func (m *PartChannelResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartChannelResponse.Marshal(b, m, deterministic)
+}
+func (dst *PartChannelResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartChannelResponse.Merge(dst, src)
+}
+func (m *PartChannelResponse) XXX_Size() int {
+	return xxx_messageInfo_PartChannelResponse.Size(m)
+}
+func (m *PartChannelResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartChannelResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartChannelResponse proto.InternalMessageInfo
+
+type SetModuleEnabledRequest struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Module               string   `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	Enabled              bool     `protobuf:"varint,3,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetModuleEnabledRequest) Reset()         { *m = SetModuleEnabledRequest{} }
+func (m *SetModuleEnabledRequest) String() string { return proto.CompactTextString(m) }
+func (*SetModuleEnabledRequest) ProtoMessage()    {}
+func (*SetModuleEnabledRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{13}
+}
+func (m *SetModuleEnabledRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetModuleEnabledRequest.Unmarshal(m, b)
+}
+func (m *SetModuleEnabledRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetModuleEnabledRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetModuleEnabledRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetModuleEnabledRequest.Merge(dst, src)
+}
+func (m *SetModuleEnabledRequest) XXX_Size() int {
+	return xxx_messageInfo_SetModuleEnabledRequest.Size(m)
+}
+func (m *SetModuleEnabledRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetModuleEnabledRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetModuleEnabledRequest proto.InternalMessageInfo
+
+func (m *SetModuleEnabledRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *SetModuleEnabledRequest) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
+
+func (m *SetModuleEnabledRequest) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+type UpdateCommandRequest struct {
+	Channel string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Module  string `protobuf:"bytes,2,opt,name=module,proto3" json:"module,omitempty"`
+	Command string `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	// enabled is one of "", "true" or "false". An empty value leaves it unchanged.
+	Enabled string `protobuf:"bytes,4,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// cooldown is a duration such as "30s". An empty value leaves it unchanged.
+	Cooldown             string   `protobuf:"bytes,5,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateCommandRequest) Reset()         { *m = UpdateCommandRequest{} }
+func (m *UpdateCommandRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateCommandRequest) ProtoMessage()    {}
+func (*UpdateCommandRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{14}
+}
+func (m *UpdateCommandRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateCommandRequest.Unmarshal(m, b)
+}
+func (m *UpdateCommandRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateCommandRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateCommandRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateCommandRequest.Merge(dst, src)
+}
+func (m *UpdateCommandRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateCommandRequest.Size(m)
+}
+func (m *UpdateCommandRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateCommandRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateCommandRequest proto.InternalMessageInfo
+
+func (m *UpdateCommandRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *UpdateCommandRequest) GetModule() string {
+	if m != nil {
+		return m.Module
+	}
+	return ""
+}
+
+func (m *UpdateCommandRequest) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+func (m *UpdateCommandRequest) GetEnabled() string {
+	if m != nil {
+		return m.Enabled
+	}
+	return ""
+}
+
+func (m *UpdateCommandRequest) GetCooldown() string {
+	if m != nil {
+		return m.Cooldown
+	}
+	return ""
+}
+
+type ListCustomCommandsRequest struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListCustomCommandsRequest) Reset()         { *m = ListCustomCommandsRequest{} }
+func (m *ListCustomCommandsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListCustomCommandsRequest) ProtoMessage()    {}
+func (*ListCustomCommandsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{15}
+}
+func (m *ListCustomCommandsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCustomCommandsRequest.Unmarshal(m, b)
+}
+func (m *ListCustomCommandsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCustomCommandsRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListCustomCommandsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCustomCommandsRequest.Merge(dst, src)
+}
+func (m *ListCustomCommandsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListCustomCommandsRequest.Size(m)
+}
+func (m *ListCustomCommandsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCustomCommandsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListCustomCommandsRequest proto.InternalMessageInfo
+
+func (m *ListCustomCommandsRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+type ListCustomCommandsResponse struct {
+	Commands             []*CustomCommand `protobuf:"bytes,1,rep,name=commands,proto3" json:"commands,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListCustomCommandsResponse) Reset()         { *m = ListCustomCommandsResponse{} }
+func (m *ListCustomCommandsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListCustomCommandsResponse) ProtoMessage()    {}
+func (*ListCustomCommandsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{16}
+}
+func (m *ListCustomCommandsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCustomCommandsResponse.Unmarshal(m, b)
+}
+func (m *ListCustomCommandsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCustomCommandsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListCustomCommandsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCustomCommandsResponse.Merge(dst, src)
+}
+func (m *ListCustomCommandsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListCustomCommandsResponse.Size(m)
+}
+func (m *ListCustomCommandsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCustomCommandsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListCustomCommandsResponse proto.InternalMessageInfo
+
+func (m *ListCustomCommandsResponse) GetCommands() []*CustomCommand {
+	if m != nil {
+		return m.Commands
+	}
+	return nil
+}
+
+type SetCustomCommandRequest struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Command              string   `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	Response             string   `protobuf:"bytes,3,opt,name=response,proto3" json:"response,omitempty"`
+	Cooldown             string   `protobuf:"bytes,4,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetCustomCommandRequest) Reset()         { *m = SetCustomCommandRequest{} }
+func (m *SetCustomCommandRequest) String() string { return proto.CompactTextString(m) }
+func (*SetCustomCommandRequest) ProtoMessage()    {}
+func (*SetCustomCommandRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{17}
+}
+func (m *SetCustomCommandRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetCustomCommandRequest.Unmarshal(m, b)
+}
+func (m *SetCustomCommandRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetCustomCommandRequest.Marshal(b, m, deterministic)
+}
+func (dst *SetCustomCommandRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetCustomCommandRequest.Merge(dst, src)
+}
+func (m *SetCustomCommandRequest) XXX_Size() int {
+	return xxx_messageInfo_SetCustomCommandRequest.Size(m)
+}
+func (m *SetCustomCommandRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetCustomCommandRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetCustomCommandRequest proto.InternalMessageInfo
+
+func (m *SetCustomCommandRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *SetCustomCommandRequest) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+func (m *SetCustomCommandRequest) GetResponse() string {
+	if m != nil {
+		return m.Response
+	}
+	return ""
+}
+
+func (m *SetCustomCommandRequest) GetCooldown() string {
+	if m != nil {
+		return m.Cooldown
+	}
+	return ""
+}
+
+type RemoveCustomCommandRequest struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Command              string   `protobuf:"bytes,2,opt,name=command,proto3" json:"command,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveCustomCommandRequest) Reset()         { *m = RemoveCustomCommandRequest{} }
+func (m *RemoveCustomCommandRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveCustomCommandRequest) ProtoMessage()    {}
+func (*RemoveCustomCommandRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{18}
+}
+func (m *RemoveCustomCommandRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveCustomCommandRequest.Unmarshal(m, b)
+}
+func (m *RemoveCustomCommandRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveCustomCommandRequest.Marshal(b, m, deterministic)
+}
+func (dst *RemoveCustomCommandRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveCustomCommandRequest.Merge(dst, src)
+}
+func (m *RemoveCustomCommandRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveCustomCommandRequest.Size(m)
+}
+func (m *RemoveCustomCommandRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveCustomCommandRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveCustomCommandRequest proto.InternalMessageInfo
+
+func (m *RemoveCustomCommandRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *RemoveCustomCommandRequest) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+type RemoveCustomCommandResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveCustomCommandResponse) Reset()         { *m = RemoveCustomCommandResponse{} }
+func (m *RemoveCustomCommandResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveCustomCommandResponse) ProtoMessage()    {}
+func (*RemoveCustomCommandResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{19}
+}
+func (m *RemoveCustomCommandResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveCustomCommandResponse.Unmarshal(m, b)
+}
+func (m *RemoveCustomCommandResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveCustomCommandResponse.Marshal(b, m, deterministic)
+}
+func (dst *RemoveCustomCommandResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveCustomCommandResponse.Merge(dst, src)
+}
+func (m *RemoveCustomCommandResponse) XXX_Size() int {
+	return xxx_messageInfo_RemoveCustomCommandResponse.Size(m)
+}
+func (m *RemoveCustomCommandResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveCustomCommandResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveCustomCommandResponse proto.InternalMessageInfo
+
+type SayRequest struct {
+	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Text                 string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SayRequest) Reset()         { *m = SayRequest{} }
+func (m *SayRequest) String() string { return proto.CompactTextString(m) }
+func (*SayRequest) ProtoMessage()    {}
+func (*SayRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{20}
+}
+func (m *SayRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SayRequest.Unmarshal(m, b)
+}
+func (m *SayRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SayRequest.Marshal(b, m, deterministic)
+}
+func (dst *SayRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SayRequest.Merge(dst, src)
+}
+func (m *SayRequest) XXX_Size() int {
+	return xxx_messageInfo_SayRequest.Size(m)
+}
+func (m *SayRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SayRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SayRequest proto.InternalMessageInfo
+
+func (m *SayRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *SayRequest) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+type SayResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SayResponse) Reset()         { *m = SayResponse{} }
+func (m *SayResponse) String() string { return proto.CompactTextString(m) }
+func (*SayResponse) ProtoMessage()    {}
+func (*SayResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{21}
+}
+func (m *SayResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SayResponse.Unmarshal(m, b)
+}
+func (m *SayResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SayResponse.Marshal(b, m, deterministic)
+}
+func (dst *SayResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SayResponse.Merge(dst, src)
+}
+func (m *SayResponse) XXX_Size() int {
+	return xxx_messageInfo_SayResponse.Size(m)
+}
+func (m *SayResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SayResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SayResponse proto.InternalMessageInfo
+
+type AuditEntry struct {
+	// time is formatted as RFC 3339.
+	Time                 string   `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Actor                string   `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	Source               string   `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Action               string   `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Channel              string   `protobuf:"bytes,5,opt,name=channel,proto3" json:"channel,omitempty"`
+	Target               string   `protobuf:"bytes,6,opt,name=target,proto3" json:"target,omitempty"`
+	Before               string   `protobuf:"bytes,7,opt,name=before,proto3" json:"before,omitempty"`
+	After                string   `protobuf:"bytes,8,opt,name=after,proto3" json:"after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEntry) Reset()         { *m = AuditEntry{} }
+func (m *AuditEntry) String() string { return proto.CompactTextString(m) }
+func (*AuditEntry) ProtoMessage()    {}
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{22}
+}
+func (m *AuditEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEntry.Unmarshal(m, b)
+}
+func (m *AuditEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEntry.Marshal(b, m, deterministic)
+}
+func (dst *AuditEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEntry.Merge(dst, src)
+}
+func (m *AuditEntry) XXX_Size() int {
+	return xxx_messageInfo_AuditEntry.Size(m)
+}
+func (m *AuditEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEntry proto.InternalMessageInfo
+
+func (m *AuditEntry) GetTime() string {
+	if m != nil {
+		return m.Time
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetBefore() string {
+	if m != nil {
+		return m.Before
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+type ListAuditEntriesRequest struct {
+	Channel string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Actor   string `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	Source  string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	// limit defaults to 50.
+	Limit                int32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAuditEntriesRequest) Reset()         { *m = ListAuditEntriesRequest{} }
+func (m *ListAuditEntriesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAuditEntriesRequest) ProtoMessage()    {}
+func (*ListAuditEntriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{23}
+}
+func (m *ListAuditEntriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditEntriesRequest.Unmarshal(m, b)
+}
+func (m *ListAuditEntriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditEntriesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListAuditEntriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditEntriesRequest.Merge(dst, src)
+}
+func (m *ListAuditEntriesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAuditEntriesRequest.Size(m)
+}
+func (m *ListAuditEntriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditEntriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditEntriesRequest proto.InternalMessageInfo
+
+func (m *ListAuditEntriesRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *ListAuditEntriesRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *ListAuditEntriesRequest) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *ListAuditEntriesRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListAuditEntriesResponse struct {
+	Entries              []*AuditEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListAuditEntriesResponse) Reset()         { *m = ListAuditEntriesResponse{} }
+func (m *ListAuditEntriesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAuditEntriesResponse) ProtoMessage()    {}
+func (*ListAuditEntriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{24}
+}
+func (m *ListAuditEntriesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditEntriesResponse.Unmarshal(m, b)
+}
+func (m *ListAuditEntriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditEntriesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListAuditEntriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditEntriesResponse.Merge(dst, src)
+}
+func (m *ListAuditEntriesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAuditEntriesResponse.Size(m)
+}
+func (m *ListAuditEntriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditEntriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditEntriesResponse proto.InternalMessageInfo
+
+func (m *ListAuditEntriesResponse) GetEntries() []*AuditEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type StreamEventsRequest struct {
+	// channels limits the stream to events in the given channels.
+	// Events that do not belong to a channel are always sent.
+	Channels []string `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+	// types limits the stream to the given event types.
+	Types                []string `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamEventsRequest) Reset()         { *m = StreamEventsRequest{} }
+func (m *StreamEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamEventsRequest) ProtoMessage()    {}
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{25}
+}
+func (m *StreamEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamEventsRequest.Unmarshal(m, b)
+}
+func (m *StreamEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamEventsRequest.Marshal(b, m, deterministic)
+}
+func (dst *StreamEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamEventsRequest.Merge(dst, src)
+}
+func (m *StreamEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamEventsRequest.Size(m)
+}
+func (m *StreamEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamEventsRequest proto.InternalMessageInfo
+
+func (m *StreamEventsRequest) GetChannels() []string {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *StreamEventsRequest) GetTypes() []string {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+type Event struct {
+	Type    string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Channel string `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
+	// json is the event encoded as it is in the HTTP event stream.
+	Json                 string   `protobuf:"bytes,3,opt,name=json,proto3" json:"json,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_botadmin_91753dac5042437f, []int{26}
+}
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (dst *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(dst, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Event) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *Event) GetJson() string {
+	if m != nil {
+		return m.Json
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*RoomState)(nil), "roastedbot.botadmin.v1.RoomState")
+	proto.RegisterType((*UserState)(nil), "roastedbot.botadmin.v1.UserState")
+	proto.RegisterType((*Restriction)(nil), "roastedbot.botadmin.v1.Restriction")
+	proto.RegisterType((*Channel)(nil), "roastedbot.botadmin.v1.Channel")
+	proto.RegisterType((*Module)(nil), "roastedbot.botadmin.v1.Module")
+	proto.RegisterType((*Command)(nil), "roastedbot.botadmin.v1.Command")
+	proto.RegisterType((*CustomCommand)(nil), "roastedbot.botadmin.v1.CustomCommand")
+	proto.RegisterType((*ListChannelsRequest)(nil), "roastedbot.botadmin.v1.ListChannelsRequest")
+	proto.RegisterType((*ListChannelsResponse)(nil), "roastedbot.botadmin.v1.ListChannelsResponse")
+	proto.RegisterType((*GetChannelRequest)(nil), "roastedbot.botadmin.v1.GetChannelRequest")
+	proto.RegisterType((*JoinChannelRequest)(nil), "roastedbot.botadmin.v1.JoinChannelRequest")
+	proto.RegisterType((*PartChannelRequest)(nil), "roastedbot.botadmin.v1.PartChannelRequest")
+	proto.RegisterType((*PartChannelResponse)(nil), "roastedbot.botadmin.v1.PartChannelResponse")
+	proto.RegisterType((*SetModuleEnabledRequest)(nil), "roastedbot.botadmin.v1.SetModuleEnabledRequest")
+	proto.RegisterType((*UpdateCommandRequest)(nil), "roastedbot.botadmin.v1.UpdateCommandRequest")
+	proto.RegisterType((*ListCustomCommandsRequest)(nil), "roastedbot.botadmin.v1.ListCustomCommandsRequest")
+	proto.RegisterType((*ListCustomCommandsResponse)(nil), "roastedbot.botadmin.v1.ListCustomCommandsResponse")
+	proto.RegisterType((*SetCustomCommandRequest)(nil), "roastedbot.botadmin.v1.SetCustomCommandRequest")
+	proto.RegisterType((*RemoveCustomCommandRequest)(nil), "roastedbot.botadmin.v1.RemoveCustomCommandRequest")
+	proto.RegisterType((*RemoveCustomCommandResponse)(nil), "roastedbot.botadmin.v1.RemoveCustomCommandResponse")
+	proto.RegisterType((*SayRequest)(nil), "roastedbot.botadmin.v1.SayRequest")
+	proto.RegisterType((*SayResponse)(nil), "roastedbot.botadmin.v1.SayResponse")
+	proto.RegisterType((*AuditEntry)(nil), "roastedbot.botadmin.v1.AuditEntry")
+	proto.RegisterType((*ListAuditEntriesRequest)(nil), "roastedbot.botadmin.v1.ListAuditEntriesRequest")
+	proto.RegisterType((*ListAuditEntriesResponse)(nil), "roastedbot.botadmin.v1.ListAuditEntriesResponse")
+	proto.RegisterType((*StreamEventsRequest)(nil), "roastedbot.botadmin.v1.StreamEventsRequest")
+	proto.RegisterType((*Event)(nil), "roastedbot.botadmin.v1.Event")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// BotAdminClient is the client API for BotAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type BotAdminClient interface {
+	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
+	GetChannel(ctx context.Context, in *GetChannelRequest, opts ...grpc.CallOption) (*Channel, error)
+	JoinChannel(ctx context.Context, in *JoinChannelRequest, opts ...grpc.CallOption) (*Channel, error)
+	PartChannel(ctx context.Context, in *PartChannelRequest, opts ...grpc.CallOption) (*PartChannelResponse, error)
+	SetModuleEnabled(ctx context.Context, in *SetModuleEnabledRequest, opts ...grpc.CallOption) (*Module, error)
+	UpdateCommand(ctx context.Context, in *UpdateCommandRequest, opts ...grpc.CallOption) (*Command, error)
+	ListCustomCommands(ctx context.Context, in *ListCustomCommandsRequest, opts ...grpc.CallOption) (*ListCustomCommandsResponse, error)
+	SetCustomCommand(ctx context.Context, in *SetCustomCommandRequest, opts ...grpc.CallOption) (*CustomCommand, error)
+	RemoveCustomCommand(ctx context.Context, in *RemoveCustomCommandRequest, opts ...grpc.CallOption) (*RemoveCustomCommandResponse, error)
+	Say(ctx context.Context, in *SayRequest, opts ...grpc.CallOption) (*SayResponse, error)
+	// ListAuditEntries returns the newest entries of the audit log.
+	ListAuditEntries(ctx context.Context, in *ListAuditEntriesRequest, opts ...grpc.CallOption) (*ListAuditEntriesResponse, error)
+	// StreamEvents streams the bot's events until the client cancels the call.
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (BotAdmin_StreamEventsClient, error)
+}
+
+type botAdminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBotAdminClient(cc *grpc.ClientConn) BotAdminClient {
+	return &botAdminClient{cc}
+}
+
+func (c *botAdminClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
+	out := new(ListChannelsResponse)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/ListChannels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) GetChannel(ctx context.Context, in *GetChannelRequest, opts ...grpc.CallOption) (*Channel, error) {
+	out := new(Channel)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/GetChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) JoinChannel(ctx context.Context, in *JoinChannelRequest, opts ...grpc.CallOption) (*Channel, error) {
+	out := new(Channel)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/JoinChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) PartChannel(ctx context.Context, in *PartChannelRequest, opts ...grpc.CallOption) (*PartChannelResponse, error) {
+	out := new(PartChannelResponse)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/PartChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) SetModuleEnabled(ctx context.Context, in *SetModuleEnabledRequest, opts ...grpc.CallOption) (*Module, error) {
+	out := new(Module)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/SetModuleEnabled", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) UpdateCommand(ctx context.Context, in *UpdateCommandRequest, opts ...grpc.CallOption) (*Command, error) {
+	out := new(Command)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/UpdateCommand", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) ListCustomCommands(ctx context.Context, in *ListCustomCommandsRequest, opts ...grpc.CallOption) (*ListCustomCommandsResponse, error) {
+	out := new(ListCustomCommandsResponse)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/ListCustomCommands", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) SetCustomCommand(ctx context.Context, in *SetCustomCommandRequest, opts ...grpc.CallOption) (*CustomCommand, error) {
+	out := new(CustomCommand)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/SetCustomCommand", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) RemoveCustomCommand(ctx context.Context, in *RemoveCustomCommandRequest, opts ...grpc.CallOption) (*RemoveCustomCommandResponse, error) {
+	out := new(RemoveCustomCommandResponse)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/RemoveCustomCommand", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) Say(ctx context.Context, in *SayRequest, opts ...grpc.CallOption) (*SayResponse, error) {
+	out := new(SayResponse)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/Say", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) ListAuditEntries(ctx context.Context, in *ListAuditEntriesRequest, opts ...grpc.CallOption) (*ListAuditEntriesResponse, error) {
+	out := new(ListAuditEntriesResponse)
+	err := c.cc.Invoke(ctx, "/roastedbot.botadmin.v1.BotAdmin/ListAuditEntries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *botAdminClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (BotAdmin_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_BotAdmin_serviceDesc.Streams[0], "/roastedbot.botadmin.v1.BotAdmin/StreamEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &botAdminStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BotAdmin_StreamEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type botAdminStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *botAdminStreamEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BotAdminServer is the server API for BotAdmin service.
+type BotAdminServer interface {
+	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
+	GetChannel(context.Context, *GetChannelRequest) (*Channel, error)
+	JoinChannel(context.Context, *JoinChannelRequest) (*Channel, error)
+	PartChannel(context.Context, *PartChannelRequest) (*PartChannelResponse, error)
+	SetModuleEnabled(context.Context, *SetModuleEnabledRequest) (*Module, error)
+	UpdateCommand(context.Context, *UpdateCommandRequest) (*Command, error)
+	ListCustomCommands(context.Context, *ListCustomCommandsRequest) (*ListCustomCommandsResponse, error)
+	SetCustomCommand(context.Context, *SetCustomCommandRequest) (*CustomCommand, error)
+	RemoveCustomCommand(context.Context, *RemoveCustomCommandRequest) (*RemoveCustomCommandResponse, error)
+	Say(context.Context, *SayRequest) (*SayResponse, error)
+	// ListAuditEntries returns the newest entries of the audit log.
+	ListAuditEntries(context.Context, *ListAuditEntriesRequest) (*ListAuditEntriesResponse, error)
+	// StreamEvents streams the bot's events until the client cancels the call.
+	StreamEvents(*StreamEventsRequest, BotAdmin_StreamEventsServer) error
+}
+
+func RegisterBotAdminServer(s *grpc.Server, srv BotAdminServer) {
+	s.RegisterService(&_BotAdmin_serviceDesc, srv)
+}
+
+func _BotAdmin_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).ListChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/ListChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).ListChannels(ctx, req.(*ListChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_GetChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).GetChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/GetChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).GetChannel(ctx, req.(*GetChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_JoinChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).JoinChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/JoinChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).JoinChannel(ctx, req.(*JoinChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_PartChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PartChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).PartChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/PartChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).PartChannel(ctx, req.(*PartChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_SetModuleEnabled_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetModuleEnabledRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).SetModuleEnabled(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/SetModuleEnabled",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).SetModuleEnabled(ctx, req.(*SetModuleEnabledRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_UpdateCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).UpdateCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/UpdateCommand",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).UpdateCommand(ctx, req.(*UpdateCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_ListCustomCommands_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCustomCommandsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).ListCustomCommands(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/ListCustomCommands",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).ListCustomCommands(ctx, req.(*ListCustomCommandsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_SetCustomCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetCustomCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).SetCustomCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/SetCustomCommand",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).SetCustomCommand(ctx, req.(*SetCustomCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_RemoveCustomCommand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCustomCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).RemoveCustomCommand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/RemoveCustomCommand",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).RemoveCustomCommand(ctx, req.(*RemoveCustomCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_Say_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).Say(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/Say",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).Say(ctx, req.(*SayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_ListAuditEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BotAdminServer).ListAuditEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/roastedbot.botadmin.v1.BotAdmin/ListAuditEntries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BotAdminServer).ListAuditEntries(ctx, req.(*ListAuditEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BotAdmin_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BotAdminServer).StreamEvents(m, &botAdminStreamEventsServer{stream})
+}
+
+type BotAdmin_StreamEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type botAdminStreamEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *botAdminStreamEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _BotAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "roastedbot.botadmin.v1.BotAdmin",
+	HandlerType: (*BotAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListChannels",
+			Handler:    _BotAdmin_ListChannels_Handler,
+		},
+		{
+			MethodName: "GetChannel",
+			Handler:    _BotAdmin_GetChannel_Handler,
+		},
+		{
+			MethodName: "JoinChannel",
+			Handler:    _BotAdmin_JoinChannel_Handler,
+		},
+		{
+			MethodName: "PartChannel",
+			Handler:    _BotAdmin_PartChannel_Handler,
+		},
+		{
+			MethodName: "SetModuleEnabled",
+			Handler:    _BotAdmin_SetModuleEnabled_Handler,
+		},
+		{
+			MethodName: "UpdateCommand",
+			Handler:    _BotAdmin_UpdateCommand_Handler,
+		},
+		{
+			MethodName: "ListCustomCommands",
+			Handler:    _BotAdmin_ListCustomCommands_Handler,
+		},
+		{
+			MethodName: "SetCustomCommand",
+			Handler:    _BotAdmin_SetCustomCommand_Handler,
+		},
+		{
+			MethodName: "RemoveCustomCommand",
+			Handler:    _BotAdmin_RemoveCustomCommand_Handler,
+		},
+		{
+			MethodName: "Say",
+			Handler:    _BotAdmin_Say_Handler,
+		},
+		{
+			MethodName: "ListAuditEntries",
+			Handler:    _BotAdmin_ListAuditEntries_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _BotAdmin_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "botadmin.proto",
+}
+
+func init() { proto.RegisterFile("botadmin.proto", fileDescriptor_botadmin_91753dac5042437f) }
+
+var fileDescriptor_botadmin_91753dac5042437f = []byte{
+	// 1180 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x5f, 0x6f, 0xdc, 0x44,
+	0x10, 0xd7, 0x9d, 0x73, 0xc9, 0x79, 0x2e, 0x09, 0x61, 0x93, 0xb6, 0xc6, 0x25, 0x25, 0x75, 0x55,
+	0x11, 0x28, 0x84, 0x36, 0x15, 0x12, 0x50, 0x1e, 0x48, 0xa3, 0xa8, 0x02, 0xb5, 0x50, 0xf9, 0x14,
+	0xa9, 0x2a, 0x88, 0x93, 0xef, 0x3c, 0x69, 0x5d, 0x6c, 0xef, 0x75, 0x77, 0x9d, 0x70, 0x42, 0xf0,
+	0xc6, 0x23, 0x6f, 0x3c, 0xf0, 0xc4, 0xc7, 0xe1, 0xa3, 0xf0, 0x39, 0xd0, 0xae, 0xc7, 0x77, 0xbe,
+	0x9c, 0x7d, 0x97, 0x88, 0xbe, 0xed, 0x8c, 0xe7, 0xff, 0xfc, 0x76, 0x66, 0x0d, 0xeb, 0x7d, 0xae,
+	0x82, 0x30, 0x89, 0xd2, 0xbd, 0xa1, 0xe0, 0x8a, 0xb3, 0xab, 0x82, 0x07, 0x52, 0x61, 0xd8, 0xe7,
+	0x6a, 0x6f, 0xfc, 0xe9, 0xf4, 0x9e, 0xf7, 0x67, 0x03, 0x6c, 0x9f, 0xf3, 0xa4, 0xab, 0x02, 0x85,
+	0x6c, 0x1b, 0x00, 0x13, 0xae, 0xb0, 0xc7, 0xd3, 0x78, 0xe4, 0x34, 0x76, 0x1a, 0xbb, 0x6d, 0xdf,
+	0x36, 0x9c, 0xef, 0xd2, 0x78, 0xc4, 0x6e, 0xc3, 0xfa, 0x09, 0x8f, 0x63, 0x7e, 0x86, 0x42, 0xe6,
+	0x22, 0xcd, 0x9d, 0xc6, 0x6e, 0xcb, 0x5f, 0x1b, 0x73, 0x8d, 0xd8, 0x06, 0x58, 0xe2, 0xf3, 0x9f,
+	0x1c, 0xcb, 0xa8, 0xeb, 0x23, 0x63, 0xb0, 0x24, 0x63, 0x7e, 0xe6, 0x2c, 0x19, 0x71, 0x73, 0x66,
+	0xd7, 0xc1, 0x96, 0x59, 0x9f, 0xec, 0xb4, 0x8c, 0x6c, 0x5b, 0x33, 0xb4, 0x09, 0xef, 0x57, 0xb0,
+	0x8f, 0x25, 0x8a, 0x3c, 0xaa, 0x1d, 0xe8, 0xf4, 0x05, 0x0f, 0xc2, 0x81, 0xce, 0x40, 0x50, 0x58,
+	0x65, 0x16, 0x7b, 0x17, 0xec, 0x84, 0x87, 0x28, 0x02, 0xc5, 0x85, 0x89, 0xa9, 0xed, 0x4f, 0x18,
+	0xec, 0x06, 0x80, 0x36, 0x3c, 0x10, 0x51, 0x1f, 0x05, 0x85, 0x55, 0xe2, 0xe8, 0x78, 0x4f, 0xa3,
+	0xa1, 0x09, 0xae, 0xed, 0xeb, 0xa3, 0xf7, 0x00, 0x3a, 0x3e, 0x4a, 0x25, 0xa2, 0x81, 0x8a, 0x78,
+	0xca, 0xae, 0xc2, 0xb2, 0xc0, 0x40, 0xf2, 0xd4, 0xf8, 0xb6, 0x7d, 0xa2, 0xd8, 0x16, 0xb4, 0xb2,
+	0x54, 0x45, 0xb1, 0x71, 0x69, 0xfb, 0x39, 0xe1, 0xfd, 0x61, 0xc1, 0xca, 0xe1, 0xcb, 0x20, 0x4d,
+	0x31, 0xd6, 0x89, 0xa7, 0x41, 0x82, 0xa4, 0x67, 0xce, 0xec, 0x2b, 0x00, 0xc1, 0x79, 0xd2, 0x93,
+	0x3a, 0x39, 0xa3, 0xda, 0xd9, 0xbf, 0xb9, 0x57, 0xdd, 0x9f, 0xbd, 0x71, 0x6f, 0x7c, 0x5b, 0x14,
+	0x47, 0x6d, 0x21, 0x93, 0x28, 0xc8, 0x82, 0x35, 0xdf, 0xc2, 0xb8, 0x8e, 0xbe, 0x9d, 0x15, 0x47,
+	0xdd, 0x68, 0x81, 0xc3, 0x78, 0xd4, 0xd3, 0x55, 0x32, 0x99, 0xdb, 0xbe, 0x6d, 0x38, 0x4f, 0x78,
+	0x88, 0xec, 0x7d, 0x78, 0x4b, 0x50, 0xfe, 0x18, 0xe6, 0x32, 0x2d, 0x23, 0xb3, 0x3e, 0x61, 0x1b,
+	0xc1, 0x23, 0xe8, 0x88, 0x49, 0xa1, 0x9c, 0x65, 0x13, 0xca, 0xad, 0xda, 0x64, 0x26, 0xa2, 0x7e,
+	0x59, 0x8f, 0xdd, 0x84, 0xd5, 0xd7, 0x19, 0x66, 0xd8, 0x8b, 0x31, 0x7d, 0xa1, 0x5e, 0x3a, 0x2b,
+	0x06, 0x27, 0x1d, 0xc3, 0x7b, 0x6c, 0x58, 0xec, 0x33, 0x58, 0x49, 0x78, 0x98, 0xc5, 0x28, 0x9d,
+	0xf6, 0x8e, 0xb5, 0xdb, 0xd9, 0xbf, 0x51, 0xe7, 0xe5, 0x89, 0x11, 0xf3, 0x0b, 0x71, 0x4f, 0xc2,
+	0x72, 0xce, 0xaa, 0xec, 0x86, 0x03, 0x2b, 0x98, 0x06, 0xfd, 0x18, 0x43, 0x02, 0x4e, 0x41, 0xb2,
+	0x07, 0xd0, 0x1e, 0xf0, 0x24, 0x09, 0xd2, 0x50, 0x3a, 0x96, 0x71, 0xf9, 0x5e, 0x9d, 0xcb, 0xc3,
+	0x5c, 0xce, 0x1f, 0x2b, 0x78, 0x7f, 0x37, 0x60, 0x85, 0xb8, 0x95, 0x6e, 0x37, 0xc0, 0xca, 0x24,
+	0x12, 0x70, 0xf4, 0xb1, 0x1c, 0x88, 0x35, 0x1d, 0x88, 0xab, 0x03, 0xe1, 0x71, 0xc8, 0xcf, 0x52,
+	0x6a, 0xd5, 0x98, 0xd6, 0xb7, 0x28, 0x0e, 0xa4, 0xea, 0x65, 0x12, 0x43, 0xea, 0x51, 0x5b, 0x33,
+	0x8e, 0x65, 0xae, 0x28, 0x50, 0x0e, 0x79, 0x2a, 0xd1, 0xb4, 0xc6, 0xf6, 0xc7, 0xb4, 0xf7, 0x3d,
+	0xac, 0x1d, 0x66, 0x52, 0xf1, 0x64, 0x5e, 0x94, 0x65, 0x03, 0xcd, 0x69, 0x03, 0x53, 0x51, 0x59,
+	0xd3, 0x51, 0x79, 0x57, 0x60, 0xf3, 0x71, 0x24, 0x15, 0xdd, 0x02, 0xe9, 0xe3, 0xeb, 0x0c, 0xa5,
+	0xf2, 0xba, 0xb0, 0x35, 0xcd, 0x26, 0x53, 0xba, 0xd2, 0xc4, 0x73, 0x1a, 0x0b, 0x2a, 0x9d, 0xcb,
+	0xf9, 0x63, 0x05, 0xef, 0x63, 0x78, 0xfb, 0x11, 0x16, 0x36, 0xc9, 0x93, 0x2e, 0x26, 0x09, 0x50,
+	0x3e, 0x05, 0xe9, 0xed, 0x01, 0xfb, 0x86, 0x47, 0xe9, 0x65, 0xe4, 0x9f, 0x06, 0xe2, 0xe2, 0xf6,
+	0xaf, 0xc0, 0xe6, 0x94, 0x3c, 0x95, 0x1b, 0xe1, 0x5a, 0x17, 0x55, 0x8e, 0xc3, 0xa3, 0xbc, 0xaf,
+	0x0b, 0x6d, 0xe9, 0xb9, 0x93, 0x83, 0x98, 0x8a, 0x4f, 0x54, 0x3d, 0x54, 0xbc, 0xbf, 0x1a, 0xb0,
+	0x75, 0x3c, 0x0c, 0x03, 0x85, 0x05, 0x24, 0xff, 0x8f, 0x13, 0x42, 0x33, 0xb5, 0xb7, 0x20, 0xcb,
+	0xee, 0x73, 0x38, 0x56, 0x22, 0xb5, 0x75, 0x0e, 0x13, 0x9f, 0xc2, 0x3b, 0xa6, 0xf9, 0x65, 0xd0,
+	0xc9, 0xc5, 0xf5, 0xec, 0x81, 0x5b, 0xa5, 0x46, 0xc8, 0x39, 0x28, 0xdd, 0xd1, 0x1c, 0x39, 0xb7,
+	0x6b, 0x91, 0x53, 0xb6, 0x50, 0xba, 0xa9, 0xbf, 0x37, 0x4c, 0x6b, 0xa6, 0x3f, 0x2f, 0xac, 0x5a,
+	0xa9, 0x3a, 0xcd, 0xe9, 0xea, 0x94, 0xef, 0x8c, 0x35, 0xe7, 0xce, 0x9c, 0xbb, 0xc9, 0xde, 0x53,
+	0x70, 0x7d, 0x4c, 0xf8, 0x29, 0xbe, 0xa9, 0x48, 0xbc, 0x6d, 0xb8, 0x5e, 0x69, 0x91, 0x20, 0xf9,
+	0x05, 0x40, 0x37, 0x18, 0x2d, 0x76, 0xc0, 0x60, 0x49, 0xe1, 0xcf, 0x8a, 0xac, 0x9b, 0xb3, 0xb7,
+	0x06, 0x1d, 0xa3, 0x4b, 0xa6, 0xfe, 0x69, 0x00, 0x1c, 0x64, 0x61, 0xa4, 0x8e, 0x52, 0x25, 0x46,
+	0x46, 0x23, 0x9a, 0x8c, 0x12, 0x7d, 0xd6, 0xbb, 0x32, 0x18, 0x14, 0xeb, 0xd9, 0xf6, 0x73, 0x42,
+	0x83, 0x4f, 0xf2, 0x4c, 0x0c, 0x8a, 0x52, 0x11, 0xa5, 0xf9, 0x41, 0xbe, 0x52, 0xf2, 0x32, 0x11,
+	0x55, 0x8e, 0xb2, 0x35, 0x03, 0x63, 0x15, 0x88, 0x17, 0xa8, 0x68, 0xd2, 0x11, 0xa5, 0xf9, 0x7d,
+	0x3c, 0xe1, 0x02, 0xcd, 0x52, 0xb1, 0x7d, 0xa2, 0x4c, 0x3c, 0x27, 0xfa, 0x39, 0xd1, 0xa6, 0x78,
+	0x34, 0xe1, 0x9d, 0xc1, 0x35, 0x8d, 0xb6, 0x71, 0x2e, 0x11, 0x2e, 0x86, 0xe8, 0x25, 0x53, 0xdb,
+	0x82, 0x56, 0x1c, 0x25, 0x91, 0xa2, 0xc7, 0x50, 0x4e, 0x78, 0xcf, 0xc0, 0x99, 0x75, 0x4c, 0xa8,
+	0xf9, 0x52, 0xdf, 0x37, 0xc3, 0x22, 0x8c, 0x7b, 0x75, 0x18, 0x9f, 0xf4, 0xc0, 0x2f, 0x54, 0xbc,
+	0x47, 0xb0, 0xd9, 0x55, 0x02, 0x83, 0xe4, 0xe8, 0x14, 0x53, 0x35, 0x4e, 0xc7, 0x3d, 0x37, 0x73,
+	0xed, 0xc9, 0x48, 0xd5, 0x21, 0xaa, 0xd1, 0x10, 0xa5, 0xd3, 0x34, 0x1f, 0x72, 0xc2, 0xfb, 0x1a,
+	0x5a, 0xc6, 0x84, 0x69, 0xef, 0x68, 0x38, 0x69, 0xef, 0x68, 0x88, 0xe5, 0xea, 0x34, 0x67, 0xe0,
+	0xf3, 0x4a, 0x3f, 0x9d, 0xf2, 0x2a, 0x98, 0xf3, 0xfe, 0xbf, 0x36, 0xb4, 0x1f, 0x72, 0x75, 0xa0,
+	0xe3, 0x66, 0x11, 0xac, 0x96, 0xb7, 0x02, 0xbb, 0x53, 0x97, 0x5d, 0xc5, 0x4a, 0x71, 0x3f, 0xba,
+	0x98, 0x30, 0x55, 0xf2, 0x19, 0xc0, 0x64, 0x57, 0xb0, 0x0f, 0xea, 0x74, 0x67, 0xf6, 0x89, 0xbb,
+	0x68, 0x1f, 0xb1, 0xe7, 0xd0, 0x29, 0xad, 0x15, 0xf6, 0x61, 0x9d, 0xfc, 0xec, 0xee, 0x59, 0x6c,
+	0xfb, 0x04, 0x3a, 0xa5, 0x95, 0x52, 0x6f, 0x7b, 0x76, 0x4f, 0xb9, 0x77, 0x2e, 0x24, 0x4b, 0xd5,
+	0x19, 0xc0, 0xc6, 0xf9, 0x1d, 0xc5, 0x3e, 0xa9, 0x33, 0x50, 0xb3, 0xcd, 0xdc, 0x05, 0xcf, 0x32,
+	0xf6, 0x23, 0xac, 0x4d, 0x2d, 0x28, 0x56, 0xdb, 0xc1, 0xaa, 0x3d, 0xe6, 0x2e, 0x7a, 0x82, 0xb1,
+	0x5f, 0x80, 0xcd, 0xee, 0x0b, 0x76, 0x6f, 0x2e, 0x4c, 0xaa, 0x56, 0x92, 0xbb, 0x7f, 0x19, 0x15,
+	0xaa, 0xe0, 0x2b, 0x53, 0xc1, 0xa9, 0x8f, 0x73, 0x2b, 0x58, 0x35, 0xea, 0xdd, 0x8b, 0x6d, 0x30,
+	0xf6, 0x1b, 0x6c, 0x56, 0x4c, 0x77, 0xb6, 0x5f, 0xff, 0xf8, 0xae, 0x5b, 0x2e, 0xee, 0xfd, 0x4b,
+	0xe9, 0x50, 0xae, 0xdf, 0x82, 0xd5, 0x0d, 0x46, 0xac, 0x76, 0x16, 0x4d, 0x76, 0x8b, 0x7b, 0x6b,
+	0xae, 0x0c, 0xd9, 0xcb, 0x60, 0xe3, 0xfc, 0x04, 0xac, 0xaf, 0x5d, 0xcd, 0x90, 0x76, 0xef, 0x5e,
+	0x5c, 0x81, 0xdc, 0xfe, 0x00, 0xab, 0xe5, 0xf1, 0x58, 0x3f, 0x7d, 0x2a, 0x86, 0xa8, 0xbb, 0x5d,
+	0x27, 0x6c, 0xc4, 0xee, 0x36, 0x1e, 0x2e, 0x3d, 0x6f, 0x0e, 0xfb, 0xfd, 0x65, 0xf3, 0x0f, 0x7e,
+	0xff, 0xbf, 0x01, 0x00, 0x4f, 0x71, 0x19, 0x84, 0x95, 0x0f, 0x00, 0x00,
+}
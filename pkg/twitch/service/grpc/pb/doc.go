@@ -0,0 +1,4 @@
+// Package pb holds the generated protobuf and gRPC code of the BotAdmin service.
+package pb
+
+//go:generate protoc --go_out=plugins=grpc:. botadmin.proto
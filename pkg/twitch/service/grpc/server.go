@@ -0,0 +1,378 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/event"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+	"github.com/brattonross/roastedbot/pkg/twitch/service/grpc/pb"
+)
+
+// eventBuffer is the number of events that can wait to be sent on a stream.
+// Events are dropped when a client cannot keep up.
+const eventBuffer = 64
+
+// maxMessageLength is the longest chat message that twitch accepts.
+const maxMessageLength = 500
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// server implements the BotAdmin service.
+type server struct {
+	client *twitch.Client
+	log    *log.Logger
+	tokens *auth.Tokens
+}
+
+// NewServer creates a gRPC server with the BotAdmin service registered.
+// Calls are authenticated with the given API tokens.
+func NewServer(client *twitch.Client, tokens *auth.Tokens, logger *log.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	if logger == nil {
+		logger = log.StandardLogger()
+	}
+	s := &server{
+		client: client,
+		log:    logger,
+		tokens: tokens,
+	}
+	opts = append(opts, grpc.UnaryInterceptor(s.unary), grpc.StreamInterceptor(s.stream))
+	g := grpc.NewServer(opts...)
+	pb.RegisterBotAdminServer(g, s)
+	return g
+}
+
+func (s *server) ListChannels(ctx context.Context, req *pb.ListChannelsRequest) (*pb.ListChannelsResponse, error) {
+	if err := authorize(ctx, auth.ScopeRead, ""); err != nil {
+		return nil, err
+	}
+	resp := &pb.ListChannelsResponse{}
+	for _, ch := range s.client.Channels() {
+		if authorize(ctx, auth.ScopeRead, ch.Name) != nil {
+			continue
+		}
+		resp.Channels = append(resp.Channels, newChannel(&ch))
+	}
+	sort.Slice(resp.Channels, func(i, j int) bool { return resp.Channels[i].Name < resp.Channels[j].Name })
+	return resp, nil
+}
+
+func (s *server) GetChannel(ctx context.Context, req *pb.GetChannelRequest) (*pb.Channel, error) {
+	if err := authorize(ctx, auth.ScopeRead, req.Channel); err != nil {
+		return nil, err
+	}
+	ch, err := s.client.Channel(req.Channel)
+	if err != nil {
+		return nil, clientError(err)
+	}
+	return newChannel(ch), nil
+}
+
+func (s *server) JoinChannel(ctx context.Context, req *pb.JoinChannelRequest) (*pb.Channel, error) {
+	if req.Channel == "" {
+		return nil, status.Error(codes.InvalidArgument, "channel is required")
+	}
+	name, err := twitch.ChannelName(req.Channel)
+	if err != nil {
+		return nil, clientError(err)
+	}
+	if err := authorize(ctx, auth.ScopeGlobalAdmin, name); err != nil {
+		return nil, err
+	}
+	if err := s.client.JoinChannel(name); err != nil {
+		return nil, clientError(err)
+	}
+	s.record(ctx, audit.Entry{Action: audit.ActionChannelJoin, Channel: name, Before: "parted", After: "joined"})
+	ch, err := s.client.Channel(name)
+	if err != nil {
+		return nil, clientError(err)
+	}
+	return newChannel(ch), nil
+}
+
+func (s *server) PartChannel(ctx context.Context, req *pb.PartChannelRequest) (*pb.PartChannelResponse, error) {
+	if err := authorize(ctx, auth.ScopeGlobalAdmin, req.Channel); err != nil {
+		return nil, err
+	}
+	if err := s.client.PartChannel(req.Channel); err != nil {
+		return nil, clientError(err)
+	}
+	s.record(ctx, audit.Entry{Action: audit.ActionChannelPart, Channel: req.Channel, Before: "joined", After: "parted"})
+	return &pb.PartChannelResponse{}, nil
+}
+
+func (s *server) SetModuleEnabled(ctx context.Context, req *pb.SetModuleEnabledRequest) (*pb.Module, error) {
+	if err := authorize(ctx, auth.ScopeChannelAdmin, req.Channel); err != nil {
+		return nil, err
+	}
+	ch, m, err := s.module(req.Channel, req.Module)
+	if err != nil {
+		return nil, err
+	}
+	before := audit.EnabledState(ch.IsModuleEnabled(m.Name))
+	action := audit.ActionModuleEnable
+	if req.Enabled {
+		err = s.client.EnableModule(req.Channel, req.Module)
+	} else {
+		action = audit.ActionModuleDisable
+		err = s.client.DisableModule(req.Channel, req.Module)
+	}
+	if err != nil {
+		return nil, clientError(err)
+	}
+	s.record(ctx, audit.Entry{Action: action, Channel: ch.Name, Target: m.Name, Before: before, After: audit.EnabledState(req.Enabled)})
+	return newModule(ch, m), nil
+}
+
+func (s *server) UpdateCommand(ctx context.Context, req *pb.UpdateCommandRequest) (*pb.Command, error) {
+	if err := authorize(ctx, auth.ScopeChannelAdmin, req.Channel); err != nil {
+		return nil, err
+	}
+	ch, m, err := s.module(req.Channel, req.Module)
+	if err != nil {
+		return nil, err
+	}
+	c, err := m.Command(req.Command)
+	if err != nil {
+		return nil, clientError(err)
+	}
+	target := m.Name + "/" + c.Name
+	beforeCooldown := c.Cooldown
+	beforeEnabled := audit.EnabledState(m.IsCommandEnabled(c.Name))
+
+	// The request is validated before any of it is applied.
+	if req.Enabled != "" && req.Enabled != "true" && req.Enabled != "false" {
+		return nil, status.Errorf(codes.InvalidArgument, "enabled must be \"true\" or \"false\", got '%s'", req.Enabled)
+	}
+	var cooldown time.Duration
+	if req.Cooldown != "" {
+		if cooldown, err = twitch.ParseCooldown(req.Cooldown); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	action := ""
+	switch req.Enabled {
+	case "true":
+		action = audit.ActionCommandEnable
+		err = s.client.EnableCommand(req.Channel, req.Module, req.Command)
+	case "false":
+		action = audit.ActionCommandDisable
+		err = s.client.DisableCommand(req.Channel, req.Module, req.Command)
+	}
+	if err != nil {
+		return nil, clientError(err)
+	}
+	if action != "" {
+		s.record(ctx, audit.Entry{Action: action, Channel: ch.Name, Target: target, Before: beforeEnabled, After: audit.EnabledState(req.Enabled == "true")})
+	}
+	if req.Cooldown != "" {
+		if err := s.client.SetCommandCooldown(req.Channel, req.Module, req.Command, cooldown); err != nil {
+			return nil, clientError(err)
+		}
+		s.record(ctx, audit.Entry{Action: audit.ActionCommandCooldown, Channel: ch.Name, Target: target, Before: beforeCooldown.String(), After: cooldown.String()})
+	}
+
+	c, err = m.Command(req.Command)
+	if err != nil {
+		return nil, clientError(err)
+	}
+	return newCommand(m, *c), nil
+}
+
+func (s *server) ListCustomCommands(ctx context.Context, req *pb.ListCustomCommandsRequest) (*pb.ListCustomCommandsResponse, error) {
+	if err := authorize(ctx, auth.ScopeRead, req.Channel); err != nil {
+		return nil, err
+	}
+	commands, err := s.client.CustomCommands(req.Channel)
+	if err != nil {
+		return nil, clientError(err)
+	}
+	resp := &pb.ListCustomCommandsResponse{}
+	for _, c := range commands {
+		resp.Commands = append(resp.Commands, newCustomCommand(c))
+	}
+	sort.Slice(resp.Commands, func(i, j int) bool { return resp.Commands[i].Name < resp.Commands[j].Name })
+	return resp, nil
+}
+
+func (s *server) SetCustomCommand(ctx context.Context, req *pb.SetCustomCommandRequest) (*pb.CustomCommand, error) {
+	if err := authorize(ctx, auth.ScopeChannelAdmin, req.Channel); err != nil {
+		return nil, err
+	}
+	cooldown, err := twitch.ParseCooldown(req.Cooldown)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var before string
+	if c, err := s.client.CustomCommand(req.Channel, req.Command); err == nil {
+		before = c.Response
+	}
+	if err := s.client.SetCustomCommand(req.Channel, req.Command, req.Response, cooldown); err != nil {
+		return nil, clientError(err)
+	}
+	s.record(ctx, audit.Entry{Action: audit.ActionCustomCommandSet, Channel: req.Channel, Target: strings.ToLower(req.Command), Before: before, After: req.Response})
+	return &pb.CustomCommand{
+		Name:     strings.ToLower(req.Command),
+		Response: req.Response,
+		Cooldown: cooldown.String(),
+	}, nil
+}
+
+func (s *server) RemoveCustomCommand(ctx context.Context, req *pb.RemoveCustomCommandRequest) (*pb.RemoveCustomCommandResponse, error) {
+	if err := authorize(ctx, auth.ScopeChannelAdmin, req.Channel); err != nil {
+		return nil, err
+	}
+	var before string
+	if c, err := s.client.CustomCommand(req.Channel, req.Command); err == nil {
+		before = c.Response
+	}
+	if err := s.client.RemoveCustomCommand(req.Channel, req.Command); err != nil {
+		return nil, clientError(err)
+	}
+	s.record(ctx, audit.Entry{Action: audit.ActionCustomCommandRemove, Channel: req.Channel, Target: strings.ToLower(req.Command), Before: before})
+	return &pb.RemoveCustomCommandResponse{}, nil
+}
+
+func (s *server) Say(ctx context.Context, req *pb.SayRequest) (*pb.SayResponse, error) {
+	if err := authorize(ctx, auth.ScopeChannelAdmin, req.Channel); err != nil {
+		return nil, err
+	}
+	if req.Text == "" || len(req.Text) > maxMessageLength {
+		return nil, status.Errorf(codes.InvalidArgument, "text must be between 1 and %d characters", maxMessageLength)
+	}
+	if _, err := s.client.Channel(req.Channel); err != nil {
+		return nil, clientError(err)
+	}
+	s.client.Say(req.Channel, req.Text)
+	return &pb.SayResponse{}, nil
+}
+
+func (s *server) ListAuditEntries(ctx context.Context, req *pb.ListAuditEntriesRequest) (*pb.ListAuditEntriesResponse, error) {
+	if err := authorize(ctx, auth.ScopeChannelAdmin, strings.ToLower(req.Channel)); err != nil {
+		return nil, err
+	}
+	q := audit.Query{
+		Actor:   req.Actor,
+		Channel: strings.ToLower(req.Channel),
+		Source:  audit.Source(req.Source),
+		Limit:   int(req.Limit),
+	}
+	if p, ok := ctx.Value(principalKey).(*auth.Principal); ok {
+		q.Channels = p.Channels
+	}
+	if q.Limit < 0 || q.Limit > maxAuditLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", maxAuditLimit)
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultAuditLimit
+	}
+	resp := &pb.ListAuditEntriesResponse{}
+	for _, e := range s.client.Audit.Query(q) {
+		resp.Entries = append(resp.Entries, newAuditEntry(e))
+	}
+	return resp, nil
+}
+
+func (s *server) StreamEvents(req *pb.StreamEventsRequest, stream pb.BotAdmin_StreamEventsServer) error {
+	ctx := stream.Context()
+	if err := authorize(ctx, auth.ScopeRead, ""); err != nil {
+		return err
+	}
+	channels := make(map[string]bool)
+	for _, c := range req.Channels {
+		c = strings.ToLower(c)
+		if err := authorize(ctx, auth.ScopeRead, c); err != nil {
+			return err
+		}
+		channels[c] = true
+	}
+	types := []event.Type{}
+	for _, t := range req.Types {
+		if !event.Type(t).Valid() {
+			return status.Errorf(codes.InvalidArgument, "unknown event type '%s'", t)
+		}
+		types = append(types, event.Type(t))
+	}
+
+	events := make(chan event.Event, eventBuffer)
+	unsubscribe := s.client.Events.Subscribe(func(e event.Event) {
+		channel := e.ChannelName()
+		if channel != "" && (authorize(ctx, auth.ScopeRead, channel) != nil || (len(channels) > 0 && !channels[channel])) {
+			return
+		}
+		select {
+		case events <- e:
+		default:
+		}
+	}, types...)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-events:
+			b, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			err = stream.Send(&pb.Event{
+				Type:    string(e.Type()),
+				Channel: e.ChannelName(),
+				Json:    string(b),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// record adds an action taken through the service to the audit log.
+func (s *server) record(ctx context.Context, e audit.Entry) {
+	e.Actor = principalName(ctx)
+	e.Source = audit.SourceGRPC
+	if err := s.client.Audit.Record(e); err != nil {
+		s.log.WithField("error", err).Error("failed to record audit entry")
+	}
+}
+
+// module looks up a module in a channel.
+func (s *server) module(channel, module string) (*twitch.Channel, *twitch.Module, error) {
+	ch, err := s.client.Channel(channel)
+	if err != nil {
+		return nil, nil, clientError(err)
+	}
+	m, err := ch.Module(module)
+	if err != nil {
+		return nil, nil, clientError(err)
+	}
+	return ch, m, nil
+}
+
+// clientError converts an error returned by the twitch Client to a status.
+func clientError(err error) error {
+	switch {
+	case errors.Is(err, twitch.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, twitch.ErrExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tirc "github.com/gempir/go-twitch-irc"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/event"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+	"github.com/brattonross/roastedbot/pkg/twitch/service/grpc/pb"
+)
+
+func newTestServer(t *testing.T) (*twitch.Client, pb.BotAdminClient) {
+	client := twitch.NewClient("bot", tirc.NewClient("bot", "oauth:token"))
+	if err := client.AddChannel("test"); err != nil {
+		t.Fatal(err)
+	}
+	client.AddCommand("test", "general", &twitch.Command{Name: "ping", Use: "ping"})
+
+	tokens, err := auth.NewTokens([]auth.Token{
+		{Name: "admin", Hash: auth.HashToken("admin"), Scopes: []auth.Scope{auth.ScopeGlobalAdmin}},
+		{Name: "reader", Hash: auth.HashToken("reader"), Scopes: []auth.Scope{auth.ScopeRead}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := log.New()
+	logger.Out = ioutil.Discard
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(client, tokens, logger)
+	go s.Serve(l)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return client, pb.NewBotAdminClient(conn)
+}
+
+func as(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestListChannels(t *testing.T) {
+	_, c := newTestServer(t)
+
+	resp, err := c.ListChannels(as("reader"), &pb.ListChannelsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Channels) != 1 || resp.Channels[0].Name != "test" {
+		t.Fatalf("expected channel 'test', got %v", resp.Channels)
+	}
+	if m := resp.Channels[0].Modules; len(m) != 1 || m[0].Commands[0].Name != "ping" {
+		t.Errorf("expected module 'general' with command 'ping', got %v", m)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	_, c := newTestServer(t)
+
+	_, err := c.ListChannels(context.Background(), &pb.ListChannelsRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without a token, got %v", err)
+	}
+	_, err = c.SetModuleEnabled(as("reader"), &pb.SetModuleEnabledRequest{Channel: "test", Module: "general", Enabled: true})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied for a read token, got %v", err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	client, c := newTestServer(t)
+
+	m, err := c.SetModuleEnabled(as("admin"), &pb.SetModuleEnabledRequest{Channel: "test", Module: "general", Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Enabled {
+		t.Error("expected module to be enabled")
+	}
+
+	cmd, err := c.UpdateCommand(as("admin"), &pb.UpdateCommandRequest{Channel: "test", Module: "general", Command: "ping", Enabled: "true", Cooldown: "30s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmd.Enabled || cmd.Cooldown != "30s" {
+		t.Errorf("expected enabled command with 30s cooldown, got %v", cmd)
+	}
+	_, err = c.UpdateCommand(as("admin"), &pb.UpdateCommandRequest{Channel: "test", Module: "general", Command: "ping", Enabled: "false", Cooldown: "-1s"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a negative cooldown, got %v", err)
+	}
+	if cmd, _ := c.UpdateCommand(as("admin"), &pb.UpdateCommandRequest{Channel: "test", Module: "general", Command: "ping"}); cmd == nil || !cmd.Enabled {
+		t.Errorf("expected the command to stay enabled, got %v", cmd)
+	}
+
+	audit, err := c.ListAuditEntries(as("admin"), &pb.ListAuditEntriesRequest{Channel: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(audit.Entries) != 3 || audit.Entries[2].Action != "module.enable" || audit.Entries[2].Actor != "admin" || audit.Entries[2].Source != "grpc" {
+		t.Errorf("expected the changes to be audited, got %v", audit.Entries)
+	}
+
+	_, err = c.PartChannel(as("admin"), &pb.PartChannelRequest{Channel: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for a missing channel, got %v", err)
+	}
+	if _, err := client.Channel("test"); err != nil {
+		t.Errorf("expected channel to remain, got %v", err)
+	}
+}
+
+func TestJoinChannel(t *testing.T) {
+	client, c := newTestServer(t)
+
+	ch, err := c.JoinChannel(as("admin"), &pb.JoinChannelRequest{Channel: "#Foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ch.Name != "foo" {
+		t.Errorf("expected channel 'foo', got '%s'", ch.Name)
+	}
+	if _, err := client.Channel("foo"); err != nil {
+		t.Errorf("expected the channel to be joined, got %v", err)
+	}
+
+	_, err = c.JoinChannel(as("admin"), &pb.JoinChannelRequest{Channel: "foo\r\nPART #test"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for an invalid name, got %v", err)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	client, c := newTestServer(t)
+
+	ctx, cancel := context.WithTimeout(as("reader"), time.Second*5)
+	defer cancel()
+	stream, err := c.StreamEvents(ctx, &pb.StreamEventsRequest{Types: []string{string(event.TypeModuleToggled)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The subscription is made when the server receives the call,
+	// so keep publishing until the first event arrives.
+	received := make(chan *pb.Event)
+	go func() {
+		e, err := stream.Recv()
+		if err == nil {
+			received <- e
+		}
+	}()
+	for {
+		client.Events.Publish(event.ModuleToggled{Channel: "test", Module: "general", Enabled: true})
+		select {
+		case e := <-received:
+			if e.Type != string(event.TypeModuleToggled) || e.Channel != "test" || !strings.Contains(e.Json, `"module":"general"`) {
+				t.Errorf("unexpected event %v", e)
+			}
+			return
+		case <-time.After(time.Millisecond * 20):
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for event")
+		}
+	}
+}
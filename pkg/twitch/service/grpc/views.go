@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"sort"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+	"github.com/brattonross/roastedbot/pkg/twitch/service/grpc/pb"
+)
+
+func newChannel(ch *twitch.Channel) *pb.Channel {
+	room := ch.RoomState()
+	user := ch.UserState()
+	c := &pb.Channel{
+		Name: ch.Name,
+		RoomState: &pb.RoomState{
+			EmoteOnly:     room.EmoteOnly,
+			FollowersOnly: int32(room.FollowersOnly),
+			R9K:           room.R9K,
+			Slow:          int32(room.Slow),
+			SubsOnly:      room.SubsOnly,
+		},
+		UserState: &pb.UserState{
+			Broadcaster: user.Broadcaster,
+			Moderator:   user.Moderator,
+			Subscriber:  user.Subscriber,
+			Vip:         user.VIP,
+		},
+		ReplyMode:      ch.ReplyMode(),
+		RestrictedMode: ch.RestrictedMode(),
+		QueueLength:    int32(ch.QueueLength()),
+	}
+	if r := ch.Restriction(); r.Reason != "" {
+		c.Restriction = &pb.Restriction{Reason: r.Reason}
+		if !r.Permanent() {
+			c.Restriction.Until = r.Until.Format(time.RFC3339)
+		}
+	}
+	for _, m := range ch.Modules() {
+		c.Modules = append(c.Modules, newModule(ch, &m))
+	}
+	sort.Slice(c.Modules, func(i, j int) bool { return c.Modules[i].Name < c.Modules[j].Name })
+	return c
+}
+
+func newModule(ch *twitch.Channel, m *twitch.Module) *pb.Module {
+	v := &pb.Module{
+		Name:    m.Name,
+		Enabled: ch.IsModuleEnabled(m.Name),
+	}
+	for _, c := range m.Commands() {
+		v.Commands = append(v.Commands, newCommand(m, c))
+	}
+	sort.Slice(v.Commands, func(i, j int) bool { return v.Commands[i].Name < v.Commands[j].Name })
+	return v
+}
+
+func newCommand(m *twitch.Module, c twitch.Command) *pb.Command {
+	v := &pb.Command{
+		Name:     c.Name,
+		Use:      c.Use,
+		Enabled:  m.IsCommandEnabled(c.Name),
+		Cooldown: c.Cooldown.String(),
+		Response: c.Response,
+	}
+	if !c.LastUsed.IsZero() {
+		v.LastUsed = c.LastUsed.Format(time.RFC3339)
+	}
+	return v
+}
+
+func newCustomCommand(c twitch.Command) *pb.CustomCommand {
+	return &pb.CustomCommand{
+		Name:     c.Name,
+		Response: c.Response,
+		Cooldown: c.Cooldown.String(),
+	}
+}
+
+func newAuditEntry(e audit.Entry) *pb.AuditEntry {
+	return &pb.AuditEntry{
+		Time:    e.Time.Format(time.RFC3339),
+		Actor:   e.Actor,
+		Source:  string(e.Source),
+		Action:  e.Action,
+		Channel: e.Channel,
+		Target:  e.Target,
+		Before:  e.Before,
+		After:   e.After,
+	}
+}
@@ -0,0 +1,372 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// maxMessageLength is the longest chat message that twitch accepts.
+const maxMessageLength = 500
+
+// api serves the versioned management API.
+type api struct {
+	client *twitch.Client
+}
+
+func (a *api) routes(r *mux.Router) {
+	read := func(h http.HandlerFunc) http.HandlerFunc { return require(auth.ScopeRead, h) }
+	channelAdmin := func(h http.HandlerFunc) http.HandlerFunc { return require(auth.ScopeChannelAdmin, h) }
+	globalAdmin := func(h http.HandlerFunc) http.HandlerFunc { return require(auth.ScopeGlobalAdmin, h) }
+
+	r.HandleFunc("/me", a.me).Methods(http.MethodGet)
+	r.HandleFunc("/audit", channelAdmin(a.listAudit)).Methods(http.MethodGet)
+	r.HandleFunc("/events", read(a.streamEvents)).Methods(http.MethodGet)
+	r.HandleFunc("/search", channelAdmin(a.search)).Methods(http.MethodGet)
+	r.HandleFunc("/channels", read(a.listChannels)).Methods(http.MethodGet)
+	r.HandleFunc("/channels", globalAdmin(a.joinChannel)).Methods(http.MethodPost)
+	r.HandleFunc("/channels/{channel}", read(a.getChannel)).Methods(http.MethodGet)
+	r.HandleFunc("/channels/{channel}", globalAdmin(a.partChannel)).Methods(http.MethodDelete)
+
+	r.HandleFunc("/channels/{channel}/messages", channelAdmin(a.say)).Methods(http.MethodPost)
+	r.HandleFunc("/channels/{channel}/logs", channelAdmin(a.exportLogs)).Methods(http.MethodGet)
+
+	r.HandleFunc("/channels/{channel}/modules", read(a.listModules)).Methods(http.MethodGet)
+	r.HandleFunc("/channels/{channel}/modules/{module}", read(a.getModule)).Methods(http.MethodGet)
+	r.HandleFunc("/channels/{channel}/modules/{module}", channelAdmin(a.updateModule)).Methods(http.MethodPatch)
+	r.HandleFunc("/channels/{channel}/modules/{module}/commands", read(a.listCommands)).Methods(http.MethodGet)
+	r.HandleFunc("/channels/{channel}/modules/{module}/commands/{command}", read(a.getCommand)).Methods(http.MethodGet)
+	r.HandleFunc("/channels/{channel}/modules/{module}/commands/{command}", channelAdmin(a.updateCommand)).Methods(http.MethodPatch)
+
+	r.HandleFunc("/channels/{channel}/custom-commands", read(a.listCustomCommands)).Methods(http.MethodGet)
+	r.HandleFunc("/channels/{channel}/custom-commands/{command}", channelAdmin(a.putCustomCommand)).Methods(http.MethodPut)
+	r.HandleFunc("/channels/{channel}/custom-commands/{command}", channelAdmin(a.deleteCustomCommand)).Methods(http.MethodDelete)
+}
+
+// me describes the principal that made the request.
+func (a *api) me(w http.ResponseWriter, r *http.Request) {
+	p := principal(r)
+	writeJSON(w, http.StatusOK, principalView{
+		Name:     p.Name,
+		Scopes:   p.Scopes,
+		Channels: p.Channels,
+	})
+}
+
+func (a *api) listChannels(w http.ResponseWriter, r *http.Request) {
+	p := principal(r)
+	views := []channelView{}
+	for _, ch := range a.client.Channels() {
+		if !p.CanAccess(ch.Name) {
+			continue
+		}
+		views = append(views, newChannelView(&ch))
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (a *api) joinChannel(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		writeError(w, http.StatusBadRequest, "bad_request", "name is required")
+		return
+	}
+	name, err := twitch.ChannelName(body.Name)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	if !principal(r).CanAccess(name) {
+		writeError(w, http.StatusForbidden, "forbidden", "this token cannot access channel '"+name+"'")
+		return
+	}
+
+	if err := a.client.JoinChannel(name); err != nil {
+		writeClientError(w, err)
+		return
+	}
+	a.record(r, audit.Entry{Action: audit.ActionChannelJoin, Channel: name, Before: "parted", After: "joined"})
+	ch, err := a.client.Channel(name)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, newChannelView(ch))
+}
+
+func (a *api) getChannel(w http.ResponseWriter, r *http.Request) {
+	ch, err := a.client.Channel(mux.Vars(r)["channel"])
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newChannelView(ch))
+}
+
+func (a *api) partChannel(w http.ResponseWriter, r *http.Request) {
+	channel := mux.Vars(r)["channel"]
+	if err := a.client.PartChannel(channel); err != nil {
+		writeClientError(w, err)
+		return
+	}
+	a.record(r, audit.Entry{Action: audit.ActionChannelPart, Channel: channel, Before: "joined", After: "parted"})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) say(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Text string `json:"text"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if body.Text == "" || len(body.Text) > maxMessageLength {
+		writeError(w, http.StatusBadRequest, "bad_request", fmt.Sprintf("text must be between 1 and %d characters", maxMessageLength))
+		return
+	}
+	ch, err := a.client.Channel(mux.Vars(r)["channel"])
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+
+	// Messages are queued, so they may not have been sent yet.
+	a.client.Say(ch.Name, body.Text)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (a *api) listModules(w http.ResponseWriter, r *http.Request) {
+	ch, err := a.client.Channel(mux.Vars(r)["channel"])
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newChannelView(ch).Modules)
+}
+
+func (a *api) getModule(w http.ResponseWriter, r *http.Request) {
+	ch, m, ok := a.module(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newModuleView(ch, m))
+}
+
+func (a *api) updateModule(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	ch, m, ok := a.module(w, r)
+	if !ok {
+		return
+	}
+
+	if body.Enabled != nil {
+		before := audit.EnabledState(ch.IsModuleEnabled(m.Name))
+		var err error
+		action := audit.ActionModuleEnable
+		if *body.Enabled {
+			err = a.client.EnableModule(ch.Name, m.Name)
+		} else {
+			action = audit.ActionModuleDisable
+			err = a.client.DisableModule(ch.Name, m.Name)
+		}
+		if err != nil {
+			writeClientError(w, err)
+			return
+		}
+		a.record(r, audit.Entry{Action: action, Channel: ch.Name, Target: m.Name, Before: before, After: audit.EnabledState(*body.Enabled)})
+	}
+	writeJSON(w, http.StatusOK, newModuleView(ch, m))
+}
+
+func (a *api) listCommands(w http.ResponseWriter, r *http.Request) {
+	ch, m, ok := a.module(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newModuleView(ch, m).Commands)
+}
+
+func (a *api) getCommand(w http.ResponseWriter, r *http.Request) {
+	_, m, ok := a.module(w, r)
+	if !ok {
+		return
+	}
+	c, err := m.Command(mux.Vars(r)["command"])
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCommandView(m, *c))
+}
+
+func (a *api) updateCommand(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Enabled  *bool   `json:"enabled"`
+		Cooldown *string `json:"cooldown"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	ch, m, ok := a.module(w, r)
+	if !ok {
+		return
+	}
+	command := mux.Vars(r)["command"]
+	c, err := m.Command(command)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	target := m.Name + "/" + c.Name
+	beforeCooldown := c.Cooldown
+
+	// The request is validated before any of it is applied.
+	var cooldown time.Duration
+	if body.Cooldown != nil {
+		if cooldown, err = twitch.ParseCooldown(*body.Cooldown); err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+			return
+		}
+	}
+
+	if body.Enabled != nil {
+		before := audit.EnabledState(m.IsCommandEnabled(command))
+		action := audit.ActionCommandEnable
+		if *body.Enabled {
+			err = a.client.EnableCommand(ch.Name, m.Name, command)
+		} else {
+			action = audit.ActionCommandDisable
+			err = a.client.DisableCommand(ch.Name, m.Name, command)
+		}
+		if err != nil {
+			writeClientError(w, err)
+			return
+		}
+		a.record(r, audit.Entry{Action: action, Channel: ch.Name, Target: target, Before: before, After: audit.EnabledState(*body.Enabled)})
+	}
+	if body.Cooldown != nil {
+		if err := a.client.SetCommandCooldown(ch.Name, m.Name, command, cooldown); err != nil {
+			writeClientError(w, err)
+			return
+		}
+		a.record(r, audit.Entry{Action: audit.ActionCommandCooldown, Channel: ch.Name, Target: target, Before: beforeCooldown.String(), After: cooldown.String()})
+	}
+
+	c, err = m.Command(command)
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newCommandView(m, *c))
+}
+
+func (a *api) listCustomCommands(w http.ResponseWriter, r *http.Request) {
+	commands, err := a.client.CustomCommands(mux.Vars(r)["channel"])
+	if err != nil {
+		writeClientError(w, err)
+		return
+	}
+	views := []customCommandView{}
+	for _, c := range commands {
+		views = append(views, customCommandView{
+			Name:     c.Name,
+			Response: c.Response,
+			Cooldown: c.Cooldown.String(),
+		})
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (a *api) putCustomCommand(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Response string `json:"response"`
+		Cooldown string `json:"cooldown"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	cooldown, err := twitch.ParseCooldown(body.Cooldown)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+	var before string
+	if c, err := a.client.CustomCommand(vars["channel"], vars["command"]); err == nil {
+		before = c.Response
+	}
+	if err := a.client.SetCustomCommand(vars["channel"], vars["command"], body.Response, cooldown); err != nil {
+		writeClientError(w, err)
+		return
+	}
+	a.record(r, audit.Entry{Action: audit.ActionCustomCommandSet, Channel: vars["channel"], Target: strings.ToLower(vars["command"]), Before: before, After: body.Response})
+	writeJSON(w, http.StatusOK, customCommandView{
+		Name:     strings.ToLower(vars["command"]),
+		Response: body.Response,
+		Cooldown: cooldown.String(),
+	})
+}
+
+func (a *api) deleteCustomCommand(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	var before string
+	if c, err := a.client.CustomCommand(vars["channel"], vars["command"]); err == nil {
+		before = c.Response
+	}
+	if err := a.client.RemoveCustomCommand(vars["channel"], vars["command"]); err != nil {
+		writeClientError(w, err)
+		return
+	}
+	a.record(r, audit.Entry{Action: audit.ActionCustomCommandRemove, Channel: vars["channel"], Target: strings.ToLower(vars["command"]), Before: before})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// module looks up the channel and module named in the request,
+// writing an error response if either does not exist.
+func (a *api) module(w http.ResponseWriter, r *http.Request) (*twitch.Channel, *twitch.Module, bool) {
+	vars := mux.Vars(r)
+	ch, err := a.client.Channel(vars["channel"])
+	if err != nil {
+		writeClientError(w, err)
+		return nil, nil, false
+	}
+	m, err := ch.Module(vars["module"])
+	if err != nil {
+		writeClientError(w, err)
+		return nil, nil, false
+	}
+	return ch, m, true
+}
+
+// decode reads the JSON request body into v, writing an error response if it is invalid.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	d := json.NewDecoder(r.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_body", fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,283 @@
+package http
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tirc "github.com/gempir/go-twitch-irc"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+func newTestAPI(t *testing.T) (*twitch.Client, http.Handler) {
+	client := twitch.NewClient("bot", tirc.NewClient("bot", "oauth:token"))
+	if err := client.AddChannel("test"); err != nil {
+		t.Fatal(err)
+	}
+	err := client.AddCommand("test", "general", &twitch.Command{
+		Name: "ping",
+		Use:  "ping",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, NewHandler(client, Options{
+		AllowedOrigins: []string{"http://localhost:8080"},
+		Log:            quietLogger(),
+		Tokens:         testTokens(t),
+	})
+}
+
+func testTokens(t *testing.T) *auth.Tokens {
+	tokens, err := auth.NewTokens([]auth.Token{
+		{Name: "admin", Hash: auth.HashToken("admin"), Scopes: []auth.Scope{auth.ScopeGlobalAdmin}},
+		{Name: "reader", Hash: auth.HashToken("reader"), Scopes: []auth.Scope{auth.ScopeRead}},
+		{Name: "other", Hash: auth.HashToken("other"), Scopes: []auth.Scope{auth.ScopeChannelAdmin}, Channels: []string{"other"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func quietLogger() *log.Logger {
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	return logger
+}
+
+func request(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	return requestAs(h, "admin", method, path, body)
+}
+
+func requestAs(h http.Handler, token, method, path, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestAPI_ListChannels(t *testing.T) {
+	_, h := newTestAPI(t)
+
+	w := request(h, http.MethodGet, "/api/v1/channels", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var channels []channelView
+	if err := json.NewDecoder(w.Body).Decode(&channels); err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 1 || channels[0].Name != "test" {
+		t.Fatalf("expected channel 'test', got %+v", channels)
+	}
+	if len(channels[0].Modules) != 1 || channels[0].Modules[0].Commands[0].Name != "ping" {
+		t.Errorf("expected module 'general' with command 'ping', got %+v", channels[0].Modules)
+	}
+}
+
+func TestAPI_UpdateModule(t *testing.T) {
+	client, h := newTestAPI(t)
+
+	w := request(h, http.MethodPatch, "/api/v1/channels/test/modules/general", `{"enabled":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	ch, _ := client.Channel("test")
+	if !ch.IsModuleEnabled("general") {
+		t.Error("expected module to be enabled")
+	}
+}
+
+func TestAPI_UpdateCommand(t *testing.T) {
+	client, h := newTestAPI(t)
+
+	w := request(h, http.MethodPatch, "/api/v1/channels/test/modules/general/commands/ping", `{"enabled":true,"cooldown":"30s"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	var c commandView
+	if err := json.NewDecoder(w.Body).Decode(&c); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Enabled || c.Cooldown != "30s" {
+		t.Errorf("expected enabled command with 30s cooldown, got %+v", c)
+	}
+
+	w = request(h, http.MethodPatch, "/api/v1/channels/test/modules/general/commands/ping", `{"cooldown":"soon"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+
+	// An invalid cooldown leaves the rest of the request unapplied.
+	w = request(h, http.MethodPatch, "/api/v1/channels/test/modules/general/commands/ping", `{"enabled":false,"cooldown":"-1s"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+	ch, _ := client.Channel("test")
+	general, _ := ch.Module("general")
+	if !general.IsCommandEnabled("ping") {
+		t.Error("expected the command to stay enabled")
+	}
+}
+
+func TestAPI_CustomCommands(t *testing.T) {
+	client, h := newTestAPI(t)
+
+	w := request(h, http.MethodPut, "/api/v1/channels/test/custom-commands/discord", `{"response":"discord.gg/test"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	commands, _ := client.CustomCommands("test")
+	if len(commands) != 1 || commands[0].Response != "discord.gg/test" {
+		t.Fatalf("expected custom command to be stored, got %+v", commands)
+	}
+
+	w = request(h, http.MethodDelete, "/api/v1/channels/test/custom-commands/discord", "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d", w.Code)
+	}
+	w = request(h, http.MethodDelete, "/api/v1/channels/test/custom-commands/discord", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+}
+
+func TestAPI_Errors(t *testing.T) {
+	_, h := newTestAPI(t)
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		code               string
+	}{
+		{http.MethodGet, "/api/v1/channels/missing", "", http.StatusNotFound, "not_found"},
+		{http.MethodGet, "/api/v1/channels/test/modules/missing", "", http.StatusNotFound, "not_found"},
+		{http.MethodPost, "/api/v1/channels", `{"name":"test"}`, http.StatusConflict, "conflict"},
+		{http.MethodPost, "/api/v1/channels", `{"channel":"test"}`, http.StatusBadRequest, "invalid_body"},
+		{http.MethodPut, "/api/v1/channels", "", http.StatusMethodNotAllowed, "method_not_allowed"},
+		{http.MethodGet, "/api/v1/nothing", "", http.StatusNotFound, "not_found"},
+	}
+	for _, tt := range tests {
+		w := request(h, tt.method, tt.path, tt.body)
+		if w.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.status, w.Code)
+			continue
+		}
+		var resp errorResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Errorf("%s %s: %v", tt.method, tt.path, err)
+			continue
+		}
+		if resp.Error.Code != tt.code {
+			t.Errorf("%s %s: expected code '%s', got '%s'", tt.method, tt.path, tt.code, resp.Error.Code)
+		}
+	}
+}
+
+func TestAPI_Authorization(t *testing.T) {
+	_, h := newTestAPI(t)
+
+	tests := []struct {
+		token, method, path, body string
+		status                    int
+	}{
+		{"", http.MethodGet, "/api/v1/channels", "", http.StatusUnauthorized},
+		{"wrong", http.MethodGet, "/api/v1/channels", "", http.StatusUnauthorized},
+		{"reader", http.MethodGet, "/api/v1/channels/test", "", http.StatusOK},
+		{"reader", http.MethodPatch, "/api/v1/channels/test/modules/general", `{"enabled":true}`, http.StatusForbidden},
+		{"other", http.MethodGet, "/api/v1/channels/test", "", http.StatusForbidden},
+		{"other", http.MethodPost, "/api/v1/channels", `{"name":"other"}`, http.StatusForbidden},
+		{"reader", http.MethodGet, "/channels", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := requestAs(h, tt.token, tt.method, tt.path, tt.body)
+		if w.Code != tt.status {
+			t.Errorf("%s %s as '%s': expected status %d, got %d", tt.method, tt.path, tt.token, tt.status, w.Code)
+		}
+	}
+
+	w := requestAs(h, "other", http.MethodGet, "/api/v1/channels", "")
+	var channels []channelView
+	if err := json.NewDecoder(w.Body).Decode(&channels); err != nil {
+		t.Fatal(err)
+	}
+	if len(channels) != 0 {
+		t.Errorf("expected channels outside of the token's channels to be hidden, got %+v", channels)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	_, h := newTestAPI(t)
+
+	r := httptest.NewRequest(http.MethodOptions, "/api/v1/channels", nil)
+	r.Header.Set("Origin", "http://localhost:8080")
+	r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected preflight status 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:8080" {
+		t.Errorf("expected allowed origin, got '%s'", got)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/api/v1/channels", nil)
+	r.Header.Set("Origin", "http://evil.example")
+	r.Header.Set("Authorization", "Bearer admin")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("expected no allowed origin for unlisted origin, got '%s'", got)
+	}
+}
+
+func TestAPI_JoinChannel(t *testing.T) {
+	client, h := newTestAPI(t)
+
+	w := request(h, http.MethodPost, "/api/v1/channels", `{"name":"#Foo"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body)
+	}
+	if _, err := client.Channel("foo"); err != nil {
+		t.Errorf("expected the channel to be joined as 'foo', got %v", err)
+	}
+	if entries := client.Audit.Query(audit.Query{}); len(entries) != 1 || entries[0].Channel != "foo" {
+		t.Errorf("expected the join of 'foo' to be audited, got %v", entries)
+	}
+
+	w = request(h, http.MethodPost, "/api/v1/channels", `{"name":"foo\r\nPRIVMSG #bar :hi"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for an invalid name, got %d", http.StatusBadRequest, w.Code)
+	}
+	if len(client.Channels()) != 2 {
+		t.Errorf("expected no channel to be joined, got %d channels", len(client.Channels()))
+	}
+}
+
+func TestAPI_Say(t *testing.T) {
+	_, h := newTestAPI(t)
+
+	w := request(h, http.MethodPost, "/api/v1/channels/test/messages", `{"text":"hello"}`)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status 202, got %d: %s", w.Code, w.Body)
+	}
+	w = request(h, http.MethodPost, "/api/v1/channels/test/messages", `{"text":""}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+	w = requestAs(h, "reader", http.MethodPost, "/api/v1/channels/test/messages", `{"text":"hello"}`)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", w.Code)
+	}
+}
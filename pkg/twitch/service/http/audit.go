@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// listAudit returns the newest entries of the audit log. Entries can be
+// filtered with the "channel", "actor" and "source" query parameters.
+func (a *api) listAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	p := principal(r)
+	q := audit.Query{
+		Actor:    query.Get("actor"),
+		Channel:  strings.ToLower(query.Get("channel")),
+		Source:   audit.Source(query.Get("source")),
+		Channels: p.Channels,
+		Limit:    defaultAuditLimit,
+	}
+	if q.Channel != "" && !p.CanAccess(q.Channel) {
+		writeError(w, http.StatusForbidden, "forbidden", "this token cannot access channel '"+q.Channel+"'")
+		return
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			writeError(w, http.StatusBadRequest, "bad_request", "limit must be between 1 and "+strconv.Itoa(maxAuditLimit))
+			return
+		}
+		q.Limit = limit
+	}
+	writeJSON(w, http.StatusOK, a.client.Audit.Query(q))
+}
+
+// record adds an action taken through the API to the audit log.
+func (a *api) record(r *http.Request, e audit.Entry) {
+	e.Actor = principal(r).Name
+	e.Source = audit.SourceHTTP
+	if err := a.client.Audit.Record(e); err != nil {
+		a.client.Log.WithField("error", err).Error("failed to record audit entry")
+	}
+}
@@ -0,0 +1,50 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+)
+
+func TestAPI_Audit(t *testing.T) {
+	client, h := newTestAPI(t)
+	client.Audit.Record(audit.Entry{Actor: "roastedb", Source: audit.SourceChat, Action: audit.ActionChannelJoin, Channel: "other"})
+
+	request(h, http.MethodPatch, "/api/v1/channels/test/modules/general", `{"enabled":true}`)
+	request(h, http.MethodPatch, "/api/v1/channels/test/modules/general/commands/ping", `{"cooldown":"30s"}`)
+
+	w := request(h, http.MethodGet, "/api/v1/audit?channel=test", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	var entries []audit.Entry
+	if err := json.NewDecoder(w.Body).Decode(&entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %+v", entries)
+	}
+	cooldown, module := entries[0], entries[1]
+	if cooldown.Action != audit.ActionCommandCooldown || cooldown.Target != "general/ping" || cooldown.Before != "0s" || cooldown.After != "30s" {
+		t.Errorf("unexpected cooldown entry %+v", cooldown)
+	}
+	if module.Actor != "admin" || module.Source != audit.SourceHTTP || module.Before != "disabled" || module.After != "enabled" {
+		t.Errorf("unexpected module entry %+v", module)
+	}
+
+	w = requestAs(h, "other", http.MethodGet, "/api/v1/audit", "")
+	if err := json.NewDecoder(w.Body).Decode(&entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Channel != "other" {
+		t.Errorf("expected only entries for the token's channels, got %+v", entries)
+	}
+	if w := requestAs(h, "other", http.MethodGet, "/api/v1/audit?channel=test", ""); w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", w.Code)
+	}
+	if w := requestAs(h, "reader", http.MethodGet, "/api/v1/audit", ""); w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 for a read token, got %d", w.Code)
+	}
+}
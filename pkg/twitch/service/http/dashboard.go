@@ -0,0 +1,79 @@
+package http
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// hashedAsset matches the names of build assets that contain a content hash,
+// such as "js/app.1b2c3d4e.js", which can be cached forever.
+var hashedAsset = regexp.MustCompile(`\.[0-9a-f]{8,}\.(js|css|png|jpe?g|gif|svg|woff2?|ttf|eot|map)$`)
+
+// dashboardConfig is the runtime configuration of the dashboard.
+type dashboardConfig struct {
+	// APIBaseURL is the URL of the versioned API.
+	APIBaseURL string `json:"apiBaseUrl"`
+	// LoginURL is where users are sent to log in, or empty if login is disabled.
+	LoginURL string `json:"loginUrl,omitempty"`
+}
+
+// dashboardConfigHandler tells the dashboard where to find the API.
+func dashboardConfigHandler(config dashboardConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+		writeJSON(w, http.StatusOK, config)
+	}
+}
+
+// dashboard serves the files of the single page dashboard. Paths that are not
+// files are answered with index.html so that vue-router's history mode works.
+func dashboard(files fs.FS) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			methodNotAllowed(w, r)
+			return
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name == "" {
+			name = "index.html"
+		}
+		if info, err := fs.Stat(files, name); err != nil || info.IsDir() {
+			// Missing assets are real 404s, anything else is a route of the dashboard.
+			if path.Ext(name) != "" {
+				notFound(w, r)
+				return
+			}
+			name = "index.html"
+		}
+
+		if hashedAsset.MatchString(name) {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		serveFile(w, r, files, name)
+	}
+}
+
+func serveFile(w http.ResponseWriter, r *http.Request, files fs.FS, name string) {
+	f, err := files.Open(name)
+	if err != nil {
+		notFound(w, r)
+		return
+	}
+	defer f.Close()
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "internal", "unable to read "+name)
+		return
+	}
+	// Embedded files have no modification time, so
+	// caching relies on the Cache-Control headers.
+	http.ServeContent(w, r, name, time.Time{}, rs)
+}
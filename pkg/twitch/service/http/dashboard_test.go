@@ -0,0 +1,79 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	tirc "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+func newDashboard() http.Handler {
+	client := twitch.NewClient("bot", tirc.NewClient("bot", "oauth:token"))
+	return NewHandler(client, Options{
+		BaseURL: "https://bot.example/",
+		Dashboard: fstest.MapFS{
+			"index.html":            {Data: []byte("<html></html>")},
+			"js/app.1b2c3d4e.js":    {Data: []byte("app()")},
+			"service-worker.js":     {Data: []byte("sw()")},
+			"img/icons/favicon.ico": {Data: []byte("icon")},
+		},
+		Log: quietLogger(),
+	})
+}
+
+func TestDashboard(t *testing.T) {
+	h := newDashboard()
+
+	tests := []struct {
+		path         string
+		status       int
+		body         string
+		cacheControl string
+	}{
+		{"/", http.StatusOK, "<html></html>", "no-cache"},
+		{"/about", http.StatusOK, "<html></html>", "no-cache"},
+		{"/channels/test/modules", http.StatusOK, "<html></html>", "no-cache"},
+		{"/js/app.1b2c3d4e.js", http.StatusOK, "app()", "public, max-age=31536000, immutable"},
+		{"/service-worker.js", http.StatusOK, "sw()", "no-cache"},
+		{"/js/missing.js", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, w.Code)
+			continue
+		}
+		if tt.body != "" && w.Body.String() != tt.body {
+			t.Errorf("%s: expected body '%s', got '%s'", tt.path, tt.body, w.Body)
+		}
+		if got := w.Header().Get("Cache-Control"); tt.cacheControl != "" && got != tt.cacheControl {
+			t.Errorf("%s: expected Cache-Control '%s', got '%s'", tt.path, tt.cacheControl, got)
+		}
+	}
+}
+
+func TestDashboard_API(t *testing.T) {
+	h := newDashboard()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/channels", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected the api to still require authentication, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/config.json", nil))
+	var config dashboardConfig
+	if err := json.NewDecoder(w.Body).Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+	if config.APIBaseURL != "https://bot.example/api/v1" {
+		t.Errorf("expected api base url 'https://bot.example/api/v1', got '%s'", config.APIBaseURL)
+	}
+}
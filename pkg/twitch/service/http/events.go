@@ -0,0 +1,107 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+)
+
+const (
+	// eventBuffer is the number of events that can wait to be written to a
+	// stream. Events are dropped when a client cannot keep up.
+	eventBuffer = 64
+	// keepAliveInterval is how often a comment is written to idle streams
+	// so that proxies do not close them.
+	keepAliveInterval = time.Second * 30
+)
+
+// streamEvents streams the bot's events as server-sent events.
+// Streams can be filtered with any number of "channel" and "type"
+// query parameters, which may also be comma separated lists.
+// Events that do not belong to a channel are always sent.
+func (a *api) streamEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "internal", "streaming is not supported")
+		return
+	}
+	// Streams stay open for longer than the server's write timeout.
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	p := principal(r)
+	channels := make(map[string]bool)
+	for _, c := range queryList(r, "channel") {
+		c = strings.ToLower(c)
+		if !p.CanAccess(c) {
+			writeError(w, http.StatusForbidden, "forbidden", "this token cannot access channel '"+c+"'")
+			return
+		}
+		channels[c] = true
+	}
+	types := []event.Type{}
+	for _, t := range queryList(r, "type") {
+		if !event.Type(t).Valid() {
+			writeError(w, http.StatusBadRequest, "bad_request", "unknown event type '"+t+"'")
+			return
+		}
+		types = append(types, event.Type(t))
+	}
+
+	events := make(chan event.Event, eventBuffer)
+	unsubscribe := a.client.Events.Subscribe(func(e event.Event) {
+		channel := e.ChannelName()
+		if channel != "" && (!p.CanAccess(channel) || (len(channels) > 0 && !channels[channel])) {
+			return
+		}
+		select {
+		case events <- e:
+		default:
+		}
+	}, types...)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-stopping(r):
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case e := <-events:
+			b, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type(), b)
+		}
+		flusher.Flush()
+	}
+}
+
+// queryList returns the values of a query parameter,
+// splitting comma separated values.
+func queryList(r *http.Request, key string) []string {
+	values := []string{}
+	for _, v := range r.URL.Query()[key] {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+	}
+	return values
+}
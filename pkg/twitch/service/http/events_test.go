@@ -0,0 +1,79 @@
+package http
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+)
+
+func TestStreamEvents(t *testing.T) {
+	client, h := newTestAPI(t)
+	s := httptest.NewServer(h)
+	defer s.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL+"/api/v1/events?channel=test&type=module_toggled,connection_state_changed", nil)
+	req.Header.Set("Authorization", "Bearer admin")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected an event stream, got '%s'", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	if line := <-lines; line != ": connected" {
+		t.Fatalf("expected stream to start with a comment, got '%s'", line)
+	}
+	<-lines
+
+	now := time.Now()
+	client.Events.Publish(event.ModuleToggled{Channel: "other", Module: "general", Time: now})
+	client.Events.Publish(event.MessageReceived{Channel: "test", Text: "hi", Time: now})
+	client.Events.Publish(event.ModuleToggled{Channel: "test", Module: "general", Enabled: true, Time: now})
+	client.Events.Publish(event.ConnectionStateChanged{State: event.Connected, Time: now})
+
+	expected := []string{
+		"event: module_toggled",
+		`data: {"channel":"test","module":"general","enabled":true,`,
+		"",
+		"event: connection_state_changed",
+		`data: {"state":"connected",`,
+	}
+	for _, want := range expected {
+		select {
+		case line := <-lines:
+			if !strings.HasPrefix(line, want) || (want == "" && line != "") {
+				t.Fatalf("expected line starting with '%s', got '%s'", want, line)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for '%s'", want)
+		}
+	}
+}
+
+func TestStreamEvents_Forbidden(t *testing.T) {
+	_, h := newTestAPI(t)
+
+	w := requestAs(h, "other", http.MethodGet, "/api/v1/events?channel=test", "")
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", w.Code)
+	}
+	w = request(h, http.MethodGet, "/api/v1/events?type=nonsense", "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
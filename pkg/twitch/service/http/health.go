@@ -0,0 +1,40 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// healthResponse is the body of the health and readiness endpoints.
+type healthResponse struct {
+	Status string `json:"status"`
+	twitch.Health
+}
+
+// healthz reports whether the bot is working, for liveness probes.
+func healthz(client *twitch.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h := client.Health()
+		writeHealth(w, h, h.Healthy(time.Now()))
+	}
+}
+
+// readyz reports whether the bot is connected to twitch and
+// has joined its channels, for readiness probes.
+func readyz(client *twitch.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h := client.Health()
+		writeHealth(w, h, h.Ready())
+	}
+}
+
+func writeHealth(w http.ResponseWriter, h twitch.Health, ok bool) {
+	w.Header().Set("Cache-Control", "no-store")
+	if !ok {
+		writeJSON(w, http.StatusServiceUnavailable, healthResponse{Status: "unavailable", Health: h})
+		return
+	}
+	writeJSON(w, http.StatusOK, healthResponse{Status: "ok", Health: h})
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	_, h := newTestAPI(t)
+
+	w := requestAs(h, "", http.MethodGet, "/healthz", "")
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	w = requestAs(h, "", http.MethodGet, "/readyz", "")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d while disconnected, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+	var body struct {
+		Status    string `json:"status"`
+		Connected bool   `json:"connected"`
+		Channels  []struct {
+			Name   string `json:"name"`
+			Joined bool   `json:"joined"`
+		} `json:"channels"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != "unavailable" || body.Connected || len(body.Channels) != 1 || body.Channels[0].Name != "test" {
+		t.Errorf("unexpected readiness detail %+v", body)
+	}
+}
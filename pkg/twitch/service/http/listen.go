@@ -0,0 +1,201 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults for the zero values of ServerConfig.
+const (
+	DefaultAddress       = ":9001"
+	DefaultReadTimeout   = time.Second * 10
+	DefaultWriteTimeout  = time.Second * 30
+	DefaultIdleTimeout   = time.Minute * 2
+	DefaultShutdownGrace = time.Second * 10
+)
+
+// ServerConfig configures the HTTP server of the bot service.
+type ServerConfig struct {
+	Address string
+	// CertFile and KeyFile enable TLS using the given certificate.
+	CertFile string
+	KeyFile  string
+	// SelfSigned enables TLS using a certificate generated at startup.
+	// It is intended for development, as browsers will not trust it.
+	SelfSigned   bool
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownGrace is how long Shutdown waits for requests to finish
+	// before closing their connections.
+	ShutdownGrace time.Duration
+}
+
+// TLS determines if the server uses TLS.
+func (c ServerConfig) TLS() bool {
+	return c.SelfSigned || c.CertFile != ""
+}
+
+// Server is the HTTP server of the bot service.
+type Server struct {
+	config ServerConfig
+	server *http.Server
+	// stopping is closed when the server starts shutting down,
+	// so that long-lived streams can end.
+	stopping     chan struct{}
+	stoppingOnce *sync.Once
+}
+
+type stoppingKey struct{}
+
+// stopping returns a channel that is closed when the server
+// handling the request starts shutting down.
+func stopping(r *http.Request) <-chan struct{} {
+	ch, _ := r.Context().Value(stoppingKey{}).(chan struct{})
+	return ch
+}
+
+// NewServer creates a server for the handler.
+func NewServer(handler http.Handler, config ServerConfig) (*Server, error) {
+	if config.Address == "" {
+		config.Address = DefaultAddress
+	}
+	if config.ReadTimeout == 0 {
+		config.ReadTimeout = DefaultReadTimeout
+	}
+	if config.WriteTimeout == 0 {
+		config.WriteTimeout = DefaultWriteTimeout
+	}
+	if config.IdleTimeout == 0 {
+		config.IdleTimeout = DefaultIdleTimeout
+	}
+	if config.ShutdownGrace == 0 {
+		config.ShutdownGrace = DefaultShutdownGrace
+	}
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, fmt.Errorf("tls requires both a certificate file and a key file")
+	}
+	if config.SelfSigned && config.CertFile != "" {
+		return nil, fmt.Errorf("tls cannot use both a self-signed certificate and a certificate file")
+	}
+
+	s := &Server{
+		config:       config,
+		stopping:     make(chan struct{}),
+		stoppingOnce: &sync.Once{},
+	}
+	s.server = &http.Server{
+		Addr:              config.Address,
+		Handler:           handler,
+		ReadHeaderTimeout: config.ReadTimeout,
+		ReadTimeout:       config.ReadTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), stoppingKey{}, s.stopping)
+		},
+	}
+	if config.SelfSigned {
+		cert, err := selfSignedCertificate(config.Address)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create self-signed certificate: %v", err)
+		}
+		s.server.TLSConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+	}
+	return s, nil
+}
+
+// Config returns the configuration of the server, including any defaults.
+func (s *Server) Config() ServerConfig {
+	return s.config
+}
+
+// ListenAndServe serves requests until the server is shut down.
+func (s *Server) ListenAndServe() error {
+	l, err := net.Listen("tcp", s.config.Address)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve serves requests on the listener until the server is shut down.
+func (s *Server) Serve(l net.Listener) error {
+	var err error
+	if s.config.TLS() {
+		err = s.server.ServeTLS(l, s.config.CertFile, s.config.KeyFile)
+	} else {
+		err = s.server.Serve(l)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops the server, waiting up to the shutdown grace period for
+// requests to finish. Connections that are still open after that are closed.
+func (s *Server) Shutdown() error {
+	s.stoppingOnce.Do(func() { close(s.stopping) })
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownGrace)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		s.server.Close()
+		return fmt.Errorf("requests did not finish within %s: %w", s.config.ShutdownGrace, err)
+	}
+	return nil
+}
+
+// selfSignedCertificate creates a certificate for localhost and the host of the address.
+func selfSignedCertificate(address string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"roastedbot development"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour * 24 * 365),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if host, _, err := net.SplitHostPort(address); err == nil && host != "" {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if host != "localhost" {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServer_SelfSignedShutdown(t *testing.T) {
+	_, h := newTestAPI(t)
+	s, err := NewServer(h, ServerConfig{
+		SelfSigned:    true,
+		ShutdownGrace: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error)
+	go func() { served <- s.Serve(l) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	req, _ := http.NewRequest(http.MethodGet, "https://"+l.Addr().String()+"/api/v1/events", nil)
+	req.Header.Set("Authorization", "Bearer admin")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	// Open event streams must not hold up the shutdown.
+	start := time.Now()
+	if err := s.Shutdown(); err != nil {
+		t.Errorf("expected shutdown to finish gracefully, got %v", err)
+	}
+	if d := time.Since(start); d >= time.Second {
+		t.Errorf("expected shutdown to finish before the grace period, took %s", d)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("expected Serve to return nil after shutdown, got %v", err)
+	}
+}
+
+func TestNewServer_Invalid(t *testing.T) {
+	tests := []ServerConfig{
+		{CertFile: "cert.pem"},
+		{CertFile: "cert.pem", KeyFile: "key.pem", SelfSigned: true},
+	}
+	for _, c := range tests {
+		if _, err := NewServer(http.NotFoundHandler(), c); err == nil {
+			t.Errorf("expected error for %+v", c)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+const (
+	sessionCookie = "roastedbot_session"
+	stateCookie   = "roastedbot_oauth_state"
+	// stateDuration is how long users have to complete a login.
+	stateDuration = time.Minute * 10
+	// sessionDuration is how long users stay logged in.
+	sessionDuration = time.Hour * 24 * 7
+)
+
+// login lets dashboard users log in with their twitch account.
+type login struct {
+	log      *log.Logger
+	oauth    *auth.OAuth
+	sessions *auth.Sessions
+}
+
+func (l *login) routes(r *mux.Router) {
+	r.HandleFunc("/auth/login", l.login).Methods(http.MethodGet)
+	r.HandleFunc("/auth/callback", l.callback).Methods(http.MethodGet)
+	r.HandleFunc("/auth/logout", l.logout).Methods(http.MethodPost)
+}
+
+// login sends the user to twitch to log in.
+func (l *login) login(w http.ResponseWriter, r *http.Request) {
+	state, err := auth.RandomString()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal", "unable to start login")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    state,
+		Path:     "/auth",
+		Expires:  time.Now().Add(stateDuration),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, l.oauth.AuthCodeURL(state), http.StatusFound)
+}
+
+// callback completes a login once twitch sends the user back.
+func (l *login) callback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if e := q.Get("error"); e != "" {
+		writeError(w, http.StatusUnauthorized, "login_failed", "twitch login failed: "+e)
+		return
+	}
+	c, err := r.Cookie(stateCookie)
+	if err != nil || c.Value == "" || c.Value != q.Get("state") {
+		writeError(w, http.StatusBadRequest, "invalid_state", "the login has expired or was not started here, please try again")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:   stateCookie,
+		Path:   "/auth",
+		MaxAge: -1,
+	})
+
+	token, err := l.oauth.Exchange(r.Context(), q.Get("code"))
+	if err != nil {
+		l.log.WithField("error", err).Warn("unable to complete login")
+		writeError(w, http.StatusBadGateway, "login_failed", "unable to complete login with twitch")
+		return
+	}
+	user, err := l.oauth.User(r.Context(), token)
+	if err != nil {
+		l.log.WithField("error", err).Warn("unable to complete login")
+		writeError(w, http.StatusBadGateway, "login_failed", "unable to complete login with twitch")
+		return
+	}
+
+	id, session, err := l.sessions.Create(user)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal", "unable to create session")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    id,
+		Path:     "/",
+		Expires:  session.Expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	l.log.WithField("user", user.Login).Info("user logged in to the dashboard")
+	http.Redirect(w, r, l.oauth.Config.AfterLoginURL, http.StatusFound)
+}
+
+func (l *login) logout(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		l.sessions.Delete(c.Value)
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:   sessionCookie,
+		Path:   "/",
+		MaxAge: -1,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// sessionAuth authenticates dashboard users by their session cookie.
+// Users may manage the modules and commands of their own channel and
+// of the channels that they moderate.
+func sessionAuth(client *twitch.Client, sessions *auth.Sessions, allowed []string) authenticator {
+	return func(r *http.Request) (*auth.Principal, bool) {
+		c, err := r.Cookie(sessionCookie)
+		if err != nil {
+			return nil, false
+		}
+		// Cookies are sent with cross-site requests, so changes are
+		// only accepted from the dashboard's own origins.
+		if !safeMethod(r.Method) && !sameOrigin(r, allowed) {
+			return nil, false
+		}
+		session, ok := sessions.Get(c.Value)
+		if !ok {
+			return nil, false
+		}
+
+		login := session.User.Login
+		channels := []string{login}
+		for _, ch := range client.Channels() {
+			if ch.Name != login && ch.IsModerator(login) {
+				channels = append(channels, ch.Name)
+			}
+		}
+		return &auth.Principal{
+			Name:     login,
+			Scopes:   []auth.Scope{auth.ScopeChannelAdmin},
+			Channels: channels,
+		}, true
+	}
+}
+
+func safeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// sameOrigin determines if a request was made by a page on the server
+// itself or on one of the allowed origins.
+func sameOrigin(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return allowedExplicitly(allowed, origin)
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	tirc "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// newOAuthServer starts a stand-in for twitch's OAuth2 endpoints
+// that logs everyone in as the given user.
+func newOAuthServer(t *testing.T, login string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		http.Redirect(w, r, q.Get("redirect_uri")+"?code=code&state="+q.Get("state"), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "code" || r.FormValue("client_secret") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "access"})
+	})
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string][]auth.User{
+			"data": {{ID: "1", Login: login, DisplayName: login}},
+		})
+	})
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// loginAs completes the login flow and returns the session cookie.
+func loginAs(t *testing.T, h http.Handler, oauthURL string) *http.Cookie {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/login", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected login to redirect, got %d", w.Code)
+	}
+	location, _ := url.Parse(w.Header().Get("Location"))
+	if !strings.HasPrefix(location.String(), oauthURL) {
+		t.Fatalf("expected redirect to the oauth server, got %s", location)
+	}
+	state := w.Result().Cookies()[0]
+
+	r := httptest.NewRequest(http.MethodGet, "/auth/callback?code=code&state="+location.Query().Get("state"), nil)
+	r.AddCookie(state)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusFound {
+		t.Fatalf("expected callback to redirect, got %d: %s", w.Code, w.Body)
+	}
+	for _, c := range w.Result().Cookies() {
+		if c.Name == sessionCookie {
+			return c
+		}
+	}
+	t.Fatal("expected a session cookie")
+	return nil
+}
+
+func newLoginAPI(t *testing.T, login string) (*twitch.Client, http.Handler, string) {
+	s := newOAuthServer(t, login)
+	oauth, err := auth.NewOAuth(auth.LoginConfig{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://bot.example/auth/callback",
+		AuthURL:      s.URL + "/authorize",
+		TokenURL:     s.URL + "/token",
+		UsersURL:     s.URL + "/users",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := twitch.NewClient("bot", tirc.NewClient("bot", "oauth:token"))
+	for _, channel := range []string{"streamer", "other"} {
+		if err := client.AddChannel(channel); err != nil {
+			t.Fatal(err)
+		}
+		client.AddCommand(channel, "general", &twitch.Command{Name: "ping"})
+	}
+	return client, NewHandler(client, Options{Log: quietLogger(), Login: oauth}), s.URL
+}
+
+func TestLogin_Broadcaster(t *testing.T) {
+	_, h, oauthURL := newLoginAPI(t, "streamer")
+	session := loginAs(t, h, oauthURL)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/api/v1/channels/streamer/modules/general", http.StatusOK},
+		{"/api/v1/channels/other/modules/general", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPatch, tt.path, strings.NewReader(`{"enabled":true}`))
+		r.AddCookie(session)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tt.status {
+			t.Errorf("PATCH %s: expected status %d, got %d", tt.path, tt.status, w.Code)
+		}
+	}
+
+	// Changes from other sites must not be accepted with the session cookie.
+	r := httptest.NewRequest(http.MethodPatch, "/api/v1/channels/streamer/modules/general", strings.NewReader(`{"enabled":false}`))
+	r.Header.Set("Origin", "http://evil.example")
+	r.AddCookie(session)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected cross-site change to be rejected, got %d", w.Code)
+	}
+}
+
+func TestLogin_InvalidState(t *testing.T) {
+	_, h, _ := newLoginAPI(t, "streamer")
+
+	r := httptest.NewRequest(http.MethodGet, "/auth/callback?code=code&state=forged", nil)
+	r.AddCookie(&http.Cookie{Name: stateCookie, Value: "expected"})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestLogin_Logout(t *testing.T) {
+	_, h, oauthURL := newLoginAPI(t, "streamer")
+	session := loginAs(t, h, oauthURL)
+
+	r := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+	r.AddCookie(session)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	r = httptest.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	r.AddCookie(session)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected session to end after logout, got %d", w.Code)
+	}
+}
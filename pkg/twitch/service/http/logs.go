@@ -0,0 +1,156 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/brattonross/roastedbot/pkg/channelname"
+	"github.com/brattonross/roastedbot/pkg/chatlog"
+)
+
+const (
+	// maxLogDays is the greatest number of days that can be downloaded at once.
+	maxLogDays         = 366
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+// exportLogs downloads a channel's chat log for the dates from the "from"
+// parameter to the "to" parameter, inclusive, which both default to today.
+// The "format" parameter chooses between "jsonl", the default, and "txt".
+func (a *api) exportLogs(w http.ResponseWriter, r *http.Request) {
+	if a.client.ChatLog == nil {
+		writeError(w, http.StatusNotFound, "not_found", "chat logs are not enabled")
+		return
+	}
+	channel := strings.ToLower(mux.Vars(r)["channel"])
+	query := r.URL.Query()
+
+	today := time.Now().UTC().Format(chatlog.DateLayout)
+	from, err := parseDate(query.Get("from"), today)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "from "+err.Error())
+		return
+	}
+	to, err := parseDate(query.Get("to"), today)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "to "+err.Error())
+		return
+	}
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, "bad_request", "to cannot be before from")
+		return
+	}
+	if to.Sub(from) >= maxLogDays*24*time.Hour {
+		writeError(w, http.StatusBadRequest, "bad_request", fmt.Sprintf("at most %d days can be downloaded at once", maxLogDays))
+		return
+	}
+
+	format := chatlog.Format(query.Get("format"))
+	contentType := "application/x-ndjson"
+	switch format {
+	case "":
+		format = chatlog.FormatJSON
+	case chatlog.FormatJSON:
+	case chatlog.FormatText:
+		contentType = "text/plain; charset=utf-8"
+	default:
+		writeError(w, http.StatusBadRequest, "bad_request", "format must be 'jsonl' or 'txt'")
+		return
+	}
+
+	if !channelname.Valid(channel) {
+		writeError(w, http.StatusBadRequest, "bad_request", "'"+channel+"' is not a valid channel name")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s-%s.%s"`,
+		channel, from.Format(chatlog.DateLayout), to.Format(chatlog.DateLayout), format))
+	if err := a.client.ChatLog.Export(w, channel, format, from, to); err != nil {
+		a.client.Log.WithField("channel", channel).WithField("error", err).Error("failed to export chat log")
+	}
+}
+
+// search finds archived chat messages. The "channel", "user", "q", "from" and
+// "to" parameters filter the messages, where "q" is words that must all appear
+// in a message and "from" and "to" are dates or RFC 3339 times.
+func (a *api) search(w http.ResponseWriter, r *http.Request) {
+	if a.client.ChatLog == nil {
+		writeError(w, http.StatusNotFound, "not_found", "chat logs are not enabled")
+		return
+	}
+	query := r.URL.Query()
+	p := principal(r)
+	q := chatlog.Query{
+		Channel:  strings.ToLower(query.Get("channel")),
+		Username: query.Get("user"),
+		Text:     query.Get("q"),
+		Channels: p.Channels,
+		Limit:    defaultSearchLimit,
+	}
+	if q.Channel != "" && !p.CanAccess(q.Channel) {
+		writeError(w, http.StatusForbidden, "forbidden", "this token cannot access channel '"+q.Channel+"'")
+		return
+	}
+	var err error
+	if q.From, err = parseTime(query.Get("from"), false); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "from "+err.Error())
+		return
+	}
+	if q.To, err = parseTime(query.Get("to"), true); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", "to "+err.Error())
+		return
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			writeError(w, http.StatusBadRequest, "bad_request", "limit must be between 1 and "+strconv.Itoa(maxSearchLimit))
+			return
+		}
+		q.Limit = limit
+	}
+
+	res, err := a.client.ChatLog.Search(q)
+	if errors.Is(err, chatlog.ErrSearchDisabled) {
+		writeError(w, http.StatusNotFound, "not_found", "chat log search is not enabled")
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// parseTime parses an RFC 3339 time or a date. A date is the start of the
+// day, or the end of the day if end is true. An empty string is the zero time.
+func parseTime(s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(chatlog.DateLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be a date such as %s or an RFC 3339 time", chatlog.DateLayout)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t, nil
+}
+
+func parseDate(s, fallback string) (time.Time, error) {
+	if s == "" {
+		s = fallback
+	}
+	t, err := time.Parse(chatlog.DateLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be a date such as %s", chatlog.DateLayout)
+	}
+	return t, nil
+}
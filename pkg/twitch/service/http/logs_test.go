@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/chatlog"
+)
+
+func TestAPI_ExportLogs(t *testing.T) {
+	client, h := newTestAPI(t)
+	if w := request(h, http.MethodGet, "/api/v1/channels/test/logs", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 without a chat log, got %d", w.Code)
+	}
+
+	archive, err := chatlog.Open(chatlog.Options{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	archive.Write(":foo!foo@foo.tmi.twitch.tv PRIVMSG #test :hello")
+	client.ChatLog = archive
+
+	today := time.Now().UTC().Format(chatlog.DateLayout)
+	w := request(h, http.MethodGet, "/api/v1/channels/test/logs?format=txt&from=2020-01-01&to="+today, "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for too many days, got %d", w.Code)
+	}
+	w = request(h, http.MethodGet, "/api/v1/channels/test/logs?format=txt&to="+today, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	if !strings.HasSuffix(w.Body.String(), "#test foo: hello\n") {
+		t.Errorf("unexpected log %q", w.Body.String())
+	}
+	filename := `attachment; filename="test-` + today + "-" + today + `.txt"`
+	if d := w.Header().Get("Content-Disposition"); d != filename {
+		t.Errorf("expected disposition %s, got %s", filename, d)
+	}
+
+	if w := request(h, http.MethodGet, "/api/v1/channels/test/logs?from=yesterday", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid date, got %d", w.Code)
+	}
+	if w := requestAs(h, "other", http.MethodGet, "/api/v1/channels/test/logs", ""); w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 for another channel, got %d", w.Code)
+	}
+}
+
+func TestAPI_Search(t *testing.T) {
+	client, h := newTestAPI(t)
+	archive, err := chatlog.Open(chatlog.Options{Dir: t.TempDir(), Search: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	client.ChatLog = archive
+	for _, line := range []string{
+		":foo!foo@foo.tmi.twitch.tv PRIVMSG #test :see example.com",
+		":bar!bar@bar.tmi.twitch.tv PRIVMSG #other :example.com is down",
+		":foo!foo@foo.tmi.twitch.tv PRIVMSG #test :hello",
+	} {
+		archive.Write(line)
+	}
+
+	var res chatlog.Result
+	w := request(h, http.MethodGet, "/api/v1/search?q=example.com&limit=1", "")
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 2 || len(res.Matches) != 1 || res.Matches[0].Text != "example.com is down" {
+		t.Errorf("unexpected result %+v", res)
+	}
+
+	w = requestAs(h, "other", http.MethodGet, "/api/v1/search?user=foo", "")
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 0 {
+		t.Errorf("expected no matches outside the token's channels, got %+v", res)
+	}
+	if w := requestAs(h, "other", http.MethodGet, "/api/v1/search?channel=test", ""); w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", w.Code)
+	}
+	if w := request(h, http.MethodGet, "/api/v1/search?from=yesterday", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid time, got %d", w.Code)
+	}
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+)
+
+type contextKey int
+
+const principalKey contextKey = iota
+
+// principal returns the authenticated principal of the request.
+func principal(r *http.Request) *auth.Principal {
+	p, _ := r.Context().Value(principalKey).(*auth.Principal)
+	return p
+}
+
+// cors allows browsers on the allowed origins to call the handler.
+// An origin of "*" allows every origin.
+func cors(allowed []string, next http.Handler) http.Handler {
+	allow := func(origin string) bool {
+		for _, o := range allowed {
+			if o == "*" {
+				return true
+			}
+		}
+		return allowedExplicitly(allowed, origin)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if origin == "" || !allow(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		// Session cookies are only sent from origins that are listed explicitly.
+		if allowedExplicitly(allowed, origin) {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func allowedExplicitly(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if strings.EqualFold(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticator identifies the principal that made a request.
+type authenticator func(r *http.Request) (*auth.Principal, bool)
+
+// tokenAuth authenticates requests by their bearer token.
+func tokenAuth(tokens *auth.Tokens) authenticator {
+	return func(r *http.Request) (*auth.Principal, bool) {
+		h := r.Header.Get("Authorization")
+		if !strings.HasPrefix(h, "Bearer ") {
+			return nil, false
+		}
+		return tokens.Authenticate(strings.TrimPrefix(h, "Bearer "))
+	}
+}
+
+// authenticate rejects requests that none of the authenticators accept.
+func authenticate(next http.Handler, authenticators ...authenticator) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, a := range authenticators {
+			if p, ok := a(r); ok {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey, p)))
+				return
+			}
+		}
+		w.Header().Set("WWW-Authenticate", `Bearer realm="roastedbot"`)
+		writeError(w, http.StatusUnauthorized, "unauthorized", "a valid API token or session is required")
+	})
+}
+
+// statusRecorder records the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter.
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// logChanges logs every request that may change the state of the bot,
+// along with the principal that made it.
+func logChanges(logger *log.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		name := ""
+		if p := principal(r); p != nil {
+			name = p.Name
+		}
+		logger.WithFields(log.Fields{
+			"token":  name,
+			"method": r.Method,
+			"path":   r.URL.Path,
+			"status": rec.status,
+			"remote": r.RemoteAddr,
+		}).Info("api request")
+	})
+}
+
+// require rejects requests whose principal does not have the scope.
+// If the route names a channel the principal must also have access to it.
+func require(scope auth.Scope, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p := principal(r)
+		allowed := p != nil && p.Has(scope)
+		if channel, ok := mux.Vars(r)["channel"]; ok && allowed {
+			allowed = p.CanAccess(channel)
+		}
+		if !allowed {
+			writeError(w, http.StatusForbidden, "forbidden", "this token does not have the '"+string(scope)+"' scope for this resource")
+			return
+		}
+		h(w, r)
+	}
+}
@@ -2,23 +2,104 @@ package http
 
 import (
 	"encoding/json"
+	"io/fs"
 	"net/http"
+	"strings"
 
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
 	"github.com/brattonross/roastedbot/pkg/twitch"
 )
 
+// Options configures the bot service.
+type Options struct {
+	// AllowedOrigins are the origins that browsers may call the service from.
+	// An origin of "*" allows every origin.
+	AllowedOrigins []string
+	// BaseURL is the URL that browsers reach the service at, such as
+	// "https://bot.example.com". It defaults to the dashboard's own origin.
+	BaseURL string
+	// Dashboard holds the files of the built dashboard. The dashboard is not served if it is nil.
+	Dashboard fs.FS
+	Log       *log.Logger
+	// Metrics serves Prometheus metrics on /metrics without authentication.
+	// Metrics are not served if it is nil.
+	Metrics http.Handler
+	// Login lets dashboard users log in with twitch. Login is disabled if it is nil.
+	Login *auth.OAuth
+	// Sessions stores the sessions of users that have logged in.
+	Sessions *auth.Sessions
+	// Tokens authenticates requests that carry an API token.
+	Tokens *auth.Tokens
+}
+
 // NewHandler creates a new handler for the bot service.
-func NewHandler(client *twitch.Client) http.Handler {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/channels", channels(client))
-	return mux
+func NewHandler(client *twitch.Client, opts Options) http.Handler {
+	if opts.Log == nil {
+		opts.Log = log.StandardLogger()
+	}
+
+	r := mux.NewRouter()
+	r.MethodNotAllowedHandler = http.HandlerFunc(methodNotAllowed)
+	r.HandleFunc("/channels", require(auth.ScopeRead, channels(client)))
+
+	a := &api{client: client}
+	v1 := r.PathPrefix("/api/v1").Subrouter()
+	v1.NotFoundHandler = http.HandlerFunc(notFound)
+	a.routes(v1)
+
+	root := mux.NewRouter()
+	root.HandleFunc("/healthz", healthz(client)).Methods(http.MethodGet)
+	root.HandleFunc("/readyz", readyz(client)).Methods(http.MethodGet)
+	if opts.Metrics != nil {
+		root.Handle("/metrics", opts.Metrics).Methods(http.MethodGet)
+	}
+	if opts.Login != nil {
+		if opts.Sessions == nil {
+			opts.Sessions = auth.NewSessions(sessionDuration)
+		}
+		l := &login{
+			log:      opts.Log,
+			oauth:    opts.Login,
+			sessions: opts.Sessions,
+		}
+		l.routes(root)
+	}
+	protected := authenticate(
+		logChanges(opts.Log, r),
+		tokenAuth(opts.Tokens),
+		sessionAuth(client, opts.Sessions, opts.AllowedOrigins),
+	)
+	if opts.Dashboard != nil {
+		base := strings.TrimSuffix(opts.BaseURL, "/")
+		config := dashboardConfig{APIBaseURL: base + "/api/v1"}
+		if opts.Login != nil {
+			config.LoginURL = base + "/auth/login"
+		}
+		root.HandleFunc("/config.json", dashboardConfigHandler(config)).Methods(http.MethodGet)
+		root.PathPrefix("/api/").Handler(protected)
+		root.Handle("/channels", protected)
+		root.PathPrefix("/").Handler(dashboard(opts.Dashboard))
+	} else {
+		root.PathPrefix("/").Handler(protected)
+	}
+
+	return cors(opts.AllowedOrigins, root)
 }
 
 func channels(client *twitch.Client) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
 
-		json.NewEncoder(w).Encode(client.Channels())
+		p := principal(r)
+		chans := []twitch.Channel{}
+		for _, ch := range client.Channels() {
+			if p.CanAccess(ch.Name) {
+				chans = append(chans, ch)
+			}
+		}
+		json.NewEncoder(w).Encode(chans)
 	}
 }
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"sort"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+type channelView struct {
+	Name           string              `json:"name"`
+	RoomState      twitch.RoomState    `json:"roomState"`
+	UserState      twitch.UserState    `json:"userState"`
+	ReplyMode      string              `json:"replyMode"`
+	RestrictedMode string              `json:"restrictedMode"`
+	Restriction    *twitch.Restriction `json:"restriction,omitempty"`
+	QueueLength    int                 `json:"queueLength"`
+	Modules        []moduleView        `json:"modules"`
+}
+
+type moduleView struct {
+	Name     string        `json:"name"`
+	Enabled  bool          `json:"enabled"`
+	Commands []commandView `json:"commands"`
+}
+
+type commandView struct {
+	Name     string     `json:"name"`
+	Use      string     `json:"use"`
+	Enabled  bool       `json:"enabled"`
+	Cooldown string     `json:"cooldown"`
+	LastUsed *time.Time `json:"lastUsed,omitempty"`
+	Response string     `json:"response,omitempty"`
+}
+
+type customCommandView struct {
+	Name     string `json:"name"`
+	Response string `json:"response"`
+	Cooldown string `json:"cooldown"`
+}
+
+type principalView struct {
+	Name     string       `json:"name"`
+	Scopes   []auth.Scope `json:"scopes"`
+	Channels []string     `json:"channels"`
+}
+
+func newChannelView(ch *twitch.Channel) channelView {
+	v := channelView{
+		Name:           ch.Name,
+		RoomState:      ch.RoomState(),
+		UserState:      ch.UserState(),
+		ReplyMode:      ch.ReplyMode(),
+		RestrictedMode: ch.RestrictedMode(),
+		QueueLength:    ch.QueueLength(),
+		Modules:        []moduleView{},
+	}
+	if r := ch.Restriction(); r.Reason != "" {
+		v.Restriction = &r
+	}
+	for _, m := range ch.Modules() {
+		v.Modules = append(v.Modules, newModuleView(ch, &m))
+	}
+	sort.Slice(v.Modules, func(i, j int) bool { return v.Modules[i].Name < v.Modules[j].Name })
+	return v
+}
+
+func newModuleView(ch *twitch.Channel, m *twitch.Module) moduleView {
+	v := moduleView{
+		Name:     m.Name,
+		Enabled:  ch.IsModuleEnabled(m.Name),
+		Commands: []commandView{},
+	}
+	for _, c := range m.Commands() {
+		v.Commands = append(v.Commands, newCommandView(m, c))
+	}
+	sort.Slice(v.Commands, func(i, j int) bool { return v.Commands[i].Name < v.Commands[j].Name })
+	return v
+}
+
+func newCommandView(m *twitch.Module, c twitch.Command) commandView {
+	v := commandView{
+		Name:     c.Name,
+		Use:      c.Use,
+		Enabled:  m.IsCommandEnabled(c.Name),
+		Cooldown: c.Cooldown.String(),
+		Response: c.Response,
+	}
+	if !c.LastUsed.IsZero() {
+		lastUsed := c.LastUsed
+		v.LastUsed = &lastUsed
+	}
+	return v
+}
@@ -17,7 +17,7 @@ var UptimeCommand = &Command{
 
 // Execute the command.
 func executeUptime(cl *Client, args []string, channel string, user twitch.User, message twitch.Message) {
-	uptime := time.Since(cl.start)
+	uptime := cl.Uptime()
 	resp := fmt.Sprintf(
 		"%s has been running for %d hours, %d minutes, and %d seconds",
 		cl.Username,
@@ -36,5 +36,5 @@ func executeUptime(cl *Client, args []string, channel string, user twitch.User,
 			uptime/time.Second%60,
 		)
 	}
-	cl.Say(channel, resp)
+	cl.Reply(channel, message, resp)
 }
@@ -0,0 +1,128 @@
+package twitch
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+)
+
+// ParseUserNotice converts a USERNOTICE message into a typed event.
+// It returns nil if the notice is not of a supported kind.
+func ParseUserNotice(channel string, user twitch.User, message twitch.Message) event.Event {
+	login := tag(message, "login")
+	if login == "" {
+		login = user.Username
+	}
+	displayName := tag(message, "display-name")
+	if displayName == "" {
+		displayName = login
+	}
+	anonymous := strings.HasPrefix(tag(message, "msg-id"), "anon")
+
+	switch tag(message, "msg-id") {
+	case "sub", "resub":
+		months := intTag(message, "msg-param-cumulative-months")
+		if months == 0 {
+			months = intTag(message, "msg-param-months")
+		}
+		return event.Subscription{
+			Channel:     channel,
+			Username:    login,
+			DisplayName: displayName,
+			Plan:        tag(message, "msg-param-sub-plan"),
+			Months:      months,
+			Streak:      intTag(message, "msg-param-streak-months"),
+			Resub:       tag(message, "msg-id") == "resub",
+			Text:        message.Text,
+			Time:        messageTime(message),
+		}
+	case "subgift", "anonsubgift":
+		e := event.GiftSubscription{
+			Channel:              channel,
+			Recipient:            tag(message, "msg-param-recipient-user-name"),
+			RecipientDisplayName: tag(message, "msg-param-recipient-display-name"),
+			Plan:                 tag(message, "msg-param-sub-plan"),
+			Anonymous:            anonymous,
+			Time:                 messageTime(message),
+		}
+		if !anonymous {
+			e.Gifter = login
+			e.GifterDisplayName = displayName
+		}
+		return e
+	case "submysterygift", "anonsubmysterygift":
+		e := event.MysteryGift{
+			Channel:   channel,
+			Count:     intTag(message, "msg-param-mass-gift-count"),
+			Plan:      tag(message, "msg-param-sub-plan"),
+			Anonymous: anonymous,
+			Time:      messageTime(message),
+		}
+		if !anonymous {
+			e.Gifter = login
+			e.GifterDisplayName = displayName
+		}
+		return e
+	case "raid":
+		raider := tag(message, "msg-param-login")
+		if raider == "" {
+			raider = login
+		}
+		name := tag(message, "msg-param-displayName")
+		if name == "" {
+			name = displayName
+		}
+		return event.Raid{
+			Channel:     channel,
+			Raider:      raider,
+			DisplayName: name,
+			Viewers:     intTag(message, "msg-param-viewerCount"),
+			Time:        messageTime(message),
+		}
+	}
+	return nil
+}
+
+// ParseCheer converts a PRIVMSG carrying a bits tag into a Cheer event.
+// It returns nil if the message does not contain any bits.
+func ParseCheer(channel string, user twitch.User, message twitch.Message) event.Event {
+	bits := intTag(message, "bits")
+	if bits <= 0 {
+		return nil
+	}
+	return event.Cheer{
+		Channel:     channel,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Bits:        bits,
+		Text:        message.Text,
+		Time:        messageTime(message),
+	}
+}
+
+// tag returns the unescaped value of a message tag.
+func tag(message twitch.Message, key string) string {
+	value := message.Tags[key]
+	value = strings.Replace(value, "\\s", " ", -1)
+	value = strings.Replace(value, "\\:", ";", -1)
+	return value
+}
+
+func intTag(message twitch.Message, key string) int {
+	i, err := strconv.Atoi(tag(message, key))
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+func messageTime(message twitch.Message) time.Time {
+	if message.Time.IsZero() {
+		return time.Now()
+	}
+	return message.Time
+}
@@ -0,0 +1,112 @@
+package twitch
+
+import (
+	"testing"
+
+	twitch "github.com/gempir/go-twitch-irc"
+
+	"github.com/brattonross/roastedbot/pkg/event"
+)
+
+func TestParseUserNotice_Resub(t *testing.T) {
+	channel, user, message := twitch.ParseMessage("@badges=subscriber/6;display-name=Ronni;login=ronni;msg-id=resub;msg-param-cumulative-months=6;msg-param-streak-months=2;msg-param-sub-plan=Prime;tmi-sent-ts=1507246572675 :tmi.twitch.tv USERNOTICE #dallas :Great stream -- keep it up!")
+
+	e, ok := ParseUserNotice(channel, *user, *message).(event.Subscription)
+	if !ok {
+		t.Fatal("expected a Subscription event")
+	}
+	if e.Channel != "dallas" {
+		t.Errorf("expected channel to be dallas, got %s", e.Channel)
+	}
+	if e.Username != "ronni" {
+		t.Errorf("expected username to be ronni, got %s", e.Username)
+	}
+	if !e.Resub {
+		t.Error("expected event to be a resub")
+	}
+	if e.Months != 6 {
+		t.Errorf("expected months to be 6, got %d", e.Months)
+	}
+	if e.Plan != "Prime" {
+		t.Errorf("expected plan to be Prime, got %s", e.Plan)
+	}
+}
+
+func TestParseUserNotice_SubGift(t *testing.T) {
+	channel, user, message := twitch.ParseMessage("@display-name=TWW2;login=tww2;msg-id=subgift;msg-param-recipient-display-name=Mr_Woodchuck;msg-param-recipient-user-name=mr_woodchuck;msg-param-sub-plan=1000 :tmi.twitch.tv USERNOTICE #forstycup")
+
+	e, ok := ParseUserNotice(channel, *user, *message).(event.GiftSubscription)
+	if !ok {
+		t.Fatal("expected a GiftSubscription event")
+	}
+	if e.Channel != "forstycup" {
+		t.Errorf("expected channel to be forstycup, got %s", e.Channel)
+	}
+	if e.Gifter != "tww2" {
+		t.Errorf("expected gifter to be tww2, got %s", e.Gifter)
+	}
+	if e.Recipient != "mr_woodchuck" {
+		t.Errorf("expected recipient to be mr_woodchuck, got %s", e.Recipient)
+	}
+}
+
+func TestParseUserNotice_AnonymousMysteryGift(t *testing.T) {
+	channel, user, message := twitch.ParseMessage("@display-name=AnAnonymousGifter;login=ananonymousgifter;msg-id=anonsubmysterygift;msg-param-mass-gift-count=5;msg-param-sub-plan=1000 :tmi.twitch.tv USERNOTICE #forstycup")
+
+	e, ok := ParseUserNotice(channel, *user, *message).(event.MysteryGift)
+	if !ok {
+		t.Fatal("expected a MysteryGift event")
+	}
+	if !e.Anonymous {
+		t.Error("expected gift to be anonymous")
+	}
+	if e.Gifter != "" {
+		t.Errorf("expected anonymous gifter to be empty, got %s", e.Gifter)
+	}
+	if e.Count != 5 {
+		t.Errorf("expected count to be 5, got %d", e.Count)
+	}
+}
+
+func TestParseUserNotice_Raid(t *testing.T) {
+	channel, user, message := twitch.ParseMessage("@display-name=TestChannel;login=testchannel;msg-id=raid;msg-param-displayName=TestChannel;msg-param-login=testchannel;msg-param-viewerCount=15 :tmi.twitch.tv USERNOTICE #othertestchannel")
+
+	e, ok := ParseUserNotice(channel, *user, *message).(event.Raid)
+	if !ok {
+		t.Fatal("expected a Raid event")
+	}
+	if e.Raider != "testchannel" {
+		t.Errorf("expected raider to be testchannel, got %s", e.Raider)
+	}
+	if e.Viewers != 15 {
+		t.Errorf("expected viewers to be 15, got %d", e.Viewers)
+	}
+}
+
+func TestParseUserNotice_Unsupported(t *testing.T) {
+	channel, user, message := twitch.ParseMessage("@display-name=Someone;login=someone;msg-id=ritual;msg-param-ritual-name=new_chatter :tmi.twitch.tv USERNOTICE #channel :hi")
+
+	if e := ParseUserNotice(channel, *user, *message); e != nil {
+		t.Errorf("expected no event, got %s", e.Type())
+	}
+}
+
+func TestParseCheer(t *testing.T) {
+	channel, user, message := twitch.ParseMessage("@badges=;bits=100;display-name=Cheerer;user-id=1 :cheerer!cheerer@cheerer.tmi.twitch.tv PRIVMSG #channel :cheer100 nice")
+
+	e, ok := ParseCheer(channel, *user, *message).(event.Cheer)
+	if !ok {
+		t.Fatal("expected a Cheer event")
+	}
+	if e.Bits != 100 {
+		t.Errorf("expected bits to be 100, got %d", e.Bits)
+	}
+}
+
+func TestParseCheer_NoBits(t *testing.T) {
+	channel, user, message := twitch.ParseMessage("@badges=;display-name=Chatter;user-id=1 :chatter!chatter@chatter.tmi.twitch.tv PRIVMSG #channel :hello")
+
+	if e := ParseCheer(channel, *user, *message); e != nil {
+		t.Error("expected no event for a message without bits")
+	}
+}
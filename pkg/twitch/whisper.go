@@ -0,0 +1,160 @@
+package twitch
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+// Whisper limits for accounts that are not verified bots.
+var whisperLimits = []rateLimit{
+	{count: 3, per: time.Second},
+	{count: 100, per: time.Minute},
+}
+
+// rateLimit allows count events per duration.
+type rateLimit struct {
+	count int
+	per   time.Duration
+}
+
+// limiter enforces a set of rate limits over a sliding window.
+type limiter struct {
+	limits     []rateLimit
+	sent       []time.Time
+	sentMutex  *sync.Mutex
+	longestPer time.Duration
+}
+
+func newLimiter(limits []rateLimit) *limiter {
+	l := &limiter{
+		limits:    limits,
+		sentMutex: &sync.Mutex{},
+	}
+	for _, limit := range limits {
+		if limit.per > l.longestPer {
+			l.longestPer = limit.per
+		}
+	}
+	return l
+}
+
+// reserve records an event at the given time if the limits allow it,
+// otherwise it returns how long to wait before trying again.
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.sentMutex.Lock()
+	defer l.sentMutex.Unlock()
+
+	// Drop events that are outside of every window.
+	i := 0
+	for i < len(l.sent) && !l.sent[i].After(now.Add(-l.longestPer)) {
+		i++
+	}
+	l.sent = l.sent[i:]
+
+	var wait time.Duration
+	for _, limit := range l.limits {
+		inWindow := 0
+		for _, t := range l.sent {
+			if t.After(now.Add(-limit.per)) {
+				inWindow++
+			}
+		}
+		if inWindow < limit.count {
+			continue
+		}
+		// Wait until the oldest event in the window has left it.
+		oldest := l.sent[len(l.sent)-inWindow]
+		if w := oldest.Add(limit.per).Sub(now); w > wait {
+			wait = w
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+	l.sent = append(l.sent, now)
+	return 0
+}
+
+// Whisper sends a private message to a user.
+// Whispers are queued and sent as twitch's whisper rate limits allow.
+func (cl *Client) Whisper(username, text string) {
+	cl.whispers.push(outgoing{to: username, text: text})
+}
+
+// drainWhispers sends the queued whispers until the whisper queue is closed.
+func (cl *Client) drainWhispers() {
+	o := cl.whispers
+	for {
+		m, ok := o.peek()
+		if !ok {
+			select {
+			case <-o.wake:
+				continue
+			case <-o.done:
+				return
+			}
+		}
+
+		if delay := cl.whisperLimit.reserve(time.Now()); delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-o.done:
+				timer.Stop()
+				return
+			}
+			continue
+		}
+		o.pop()
+		cl.currentSender().Whisper(m.to, m.text)
+	}
+}
+
+// CanWhisper determines if the user can use any command by whisper,
+// in any of the Client's channels.
+func (cl *Client) CanWhisper(user twitch.User) bool {
+	for _, ch := range cl.Channels() {
+		for _, m := range ch.Modules() {
+			if !ch.IsModuleEnabled(m.Name) {
+				continue
+			}
+			for _, c := range m.Commands() {
+				if c.AllowsWhisper() && c.Permits(user) && m.IsCommandEnabled(c.Name) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// OnNewWhisper sets the callback that is called for every whisper the bot receives.
+func (cl *Client) OnNewWhisper(callback func(user twitch.User, message twitch.Message)) {
+	cl.onNewWhisper = callback
+}
+
+func (cl *Client) handleWhisper(user twitch.User, message twitch.Message) {
+	cl.connection.received(time.Now())
+	correlate(&message)
+	// Whispers do not carry the sender's login as a tag,
+	// so store it for Reply to whisper back to them.
+	message.Tags["login"] = user.Username
+	if cl.onNewWhisper != nil {
+		cl.onNewWhisper(user, message)
+	}
+}
+
+// replyWhisper whispers a response to the sender of a whisper.
+func (cl *Client) replyWhisper(message twitch.Message, text string) {
+	login := message.Tags["login"]
+	if login == "" {
+		login = strings.ToLower(message.Tags["display-name"])
+	}
+	if login == "" {
+		return
+	}
+	cl.Whisper(login, text)
+}
@@ -0,0 +1,95 @@
+package twitch
+
+import (
+	"testing"
+	"time"
+
+	twitch "github.com/gempir/go-twitch-irc"
+)
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter([]rateLimit{
+		{count: 2, per: time.Second},
+		{count: 3, per: time.Minute},
+	})
+	now := time.Now()
+
+	if d := l.reserve(now); d != 0 {
+		t.Fatalf("expected first event to be allowed, got wait of %v", d)
+	}
+	if d := l.reserve(now.Add(time.Millisecond * 100)); d != 0 {
+		t.Fatalf("expected second event to be allowed, got wait of %v", d)
+	}
+	if d := l.reserve(now.Add(time.Millisecond * 200)); d != time.Millisecond*800 {
+		t.Errorf("expected third event to wait 800ms, got %v", d)
+	}
+	if d := l.reserve(now.Add(time.Second)); d != 0 {
+		t.Fatalf("expected event after a second to be allowed, got wait of %v", d)
+	}
+	if d := l.reserve(now.Add(time.Second * 2)); d != time.Second*58 {
+		t.Errorf("expected event to wait for the minute limit, got %v", d)
+	}
+}
+
+func TestReply_Whisper(t *testing.T) {
+	cl, s := newTestClient()
+
+	cl.OnNewWhisper(func(user twitch.User, message twitch.Message) {
+		cl.Reply("", message, "hello")
+	})
+	cl.handleWhisper(twitch.User{Username: "someone"}, twitch.Message{Type: twitch.WHISPER, Text: "status"})
+
+	waitFor(t, func() bool { return len(s.sentWhispers()) == 1 })
+	if whispers := s.sentWhispers(); whispers[0] != "someone: hello" {
+		t.Errorf("expected a whisper back to the sender, got %v", whispers)
+	}
+}
+
+func TestReply_WhisperRateLimited(t *testing.T) {
+	cl, s := newTestClient()
+	cl.whisperLimit = newLimiter([]rateLimit{{count: 1, per: time.Hour}})
+	cl.whisperLimit.reserve(time.Now())
+
+	cl.OnNewWhisper(func(user twitch.User, message twitch.Message) {
+		cl.Reply("", message, "hello")
+	})
+	done := make(chan struct{})
+	go func() {
+		cl.handleWhisper(twitch.User{Username: "someone"}, twitch.Message{Type: twitch.WHISPER, Text: "status"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected handling a whisper not to wait for the whisper rate limit")
+	}
+	if cl.whispers.len() != 1 || len(s.sentWhispers()) != 0 {
+		t.Errorf("expected the reply to be queued, got %d queued and %v sent", cl.whispers.len(), s.sentWhispers())
+	}
+}
+
+func TestCanWhisper(t *testing.T) {
+	cl, _ := newTestClient()
+	cl.AddChannel("channel")
+	cl.AddModule("channel", "admin")
+	cl.AddCommand("channel", "admin", &Command{
+		Name:      "status",
+		Permitted: func(user twitch.User) bool { return user.Username == "admin" },
+		Respond:   RespondBoth,
+		Use:       "status",
+	})
+	admin, stranger := twitch.User{Username: "admin"}, twitch.User{Username: "stranger"}
+
+	if cl.CanWhisper(admin) {
+		t.Error("expected commands of a disabled module not to be usable")
+	}
+	cl.EnableModule("channel", "admin")
+	cl.EnableCommand("channel", "admin", "status")
+	if !cl.CanWhisper(admin) {
+		t.Error("expected the admin to be able to whisper a command")
+	}
+	if cl.CanWhisper(stranger) {
+		t.Error("expected a stranger not to be able to whisper any command")
+	}
+}
@@ -0,0 +1,334 @@
+package roastedbot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// Reload applies a new configuration to the running bot without reconnecting.
+// Configured channels that the bot is not in are joined, and channels that are
+// removed from the configuration are parted. The reply mode, restricted mode,
+// event messages and module and command overrides of each channel are updated
+// in place, where the running channel differs from the new configuration.
+// Changes to the login, logging and servers are reported but need a restart,
+// so the running values of those sections are kept in the Controller's Config
+// until then.
+//
+// It returns a description of each change, and the errors of any changes that
+// could not be applied. Changes are recorded in the audit log.
+func (c *Controller) Reload(next *Config) ([]string, error) {
+	c.reloadMutex.Lock()
+	defer c.reloadMutex.Unlock()
+
+	c.mutex.Lock()
+	prev := c.Config
+	c.Config = running(prev, next)
+	c.mutex.Unlock()
+
+	r := &reload{c: c, record: true, changes: []string{}}
+	r.restart(prev, next)
+	if prev.AutoPartBanned != next.AutoPartBanned {
+		c.Client.SetAutoPartBanned(next.AutoPartBanned)
+		r.changes = append(r.changes, fmt.Sprintf("autoPartBanned is now %t", next.AutoPartBanned))
+	}
+	c.alerts.SetSettings(eventSettings(next))
+
+	configured := make(map[string]bool)
+	for _, ch := range next.Channels {
+		configured[ch] = true
+	}
+	for _, ch := range prev.Channels {
+		if configured[ch] {
+			continue
+		}
+		if err := c.Client.PartChannel(ch); err != nil {
+			if !errors.Is(err, twitch.ErrNotFound) {
+				r.fail(err)
+			}
+			continue
+		}
+		r.changed(audit.Entry{Action: audit.ActionChannelPart, Channel: ch, Before: "joined", After: "parted"}, "parted #%s", ch)
+	}
+
+	joined := make(map[string]bool)
+	for _, ch := range next.Channels {
+		if _, err := c.Client.Channel(ch); err == nil {
+			continue
+		}
+		// The new channel's settings are applied as it is added.
+		if err := c.Client.JoinChannel(ch); err != nil {
+			r.fail(err)
+			continue
+		}
+		joined[ch] = true
+		r.changed(audit.Entry{Action: audit.ActionChannelJoin, Channel: ch, Before: "parted", After: "joined"}, "joined #%s", ch)
+	}
+
+	var channels []string
+	for _, ch := range c.Client.Channels() {
+		if !joined[ch.Name] {
+			channels = append(channels, ch.Name)
+		}
+	}
+	sort.Strings(channels)
+	for _, ch := range channels {
+		if !reflect.DeepEqual(settingsOf(prev, ch).Events, settingsOf(next, ch).Events) {
+			r.changes = append(r.changes, fmt.Sprintf("#%s: updated event messages", ch))
+		}
+		r.channel(ch, prev.Settings[ch], next.Settings[ch])
+	}
+	return r.changes, errors.Join(r.errs...)
+}
+
+func settingsOf(c *Config, channel string) *ChannelSettings {
+	if s := c.Settings[channel]; s != nil {
+		return s
+	}
+	return &ChannelSettings{}
+}
+
+// reload applies the differences between configurations to the Client.
+type reload struct {
+	c *Controller
+	// record adds each change to the audit log.
+	record  bool
+	changes []string
+	errs    []error
+}
+
+func (r *reload) changed(e audit.Entry, format string, args ...interface{}) {
+	r.changes = append(r.changes, fmt.Sprintf(format, args...))
+	if !r.record {
+		return
+	}
+	e.Actor = "config"
+	e.Source = audit.SourceConfig
+	if err := r.c.Client.Audit.Record(e); err != nil {
+		r.c.log.WithField("error", err).Error("failed to record audit entry")
+	}
+}
+
+func (r *reload) fail(err error) {
+	r.errs = append(r.errs, err)
+}
+
+// restart reports the changed parts of the configuration that only apply after a restart.
+func (r *reload) restart(prev, next *Config) {
+	parts := []struct {
+		name       string
+		prev, next interface{}
+	}{
+		{"username", prev.Username, next.Username},
+		{"oauth", prev.OAuth, next.OAuth},
+		{"token", prev.Token, next.Token},
+		{"log", prev.Log, next.Log},
+		{"audit", prev.Audit, next.Audit},
+		{"chatLog", prev.ChatLog, next.ChatLog},
+		{"store", prev.Store, next.Store},
+		{"api", prev.API, next.API},
+		{"http", prev.HTTP, next.HTTP},
+		{"metrics", prev.Metrics, next.Metrics},
+		{"grpc", prev.GRPC, next.GRPC},
+	}
+	for _, p := range parts {
+		if !reflect.DeepEqual(p.prev, p.next) {
+			r.changes = append(r.changes, p.name+" changed and needs a restart to apply")
+		}
+	}
+}
+
+// running returns next with the sections reported by restart kept at their
+// values in prev, which is the configuration that the bot is running with.
+func running(prev, next *Config) *Config {
+	c := *next
+	c.Username = prev.Username
+	c.OAuth = prev.OAuth
+	c.Token = prev.Token
+	c.Log = prev.Log
+	c.Audit = prev.Audit
+	c.ChatLog = prev.ChatLog
+	c.Store = prev.Store
+	c.API = prev.API
+	c.HTTP = prev.HTTP
+	c.Metrics = prev.Metrics
+	c.GRPC = prev.GRPC
+	return &c
+}
+
+// channel brings a channel in line with its settings. Only the settings that
+// either configuration mentions are changed, and settings that are removed
+// return to their defaults.
+func (r *reload) channel(channel string, prev, next *ChannelSettings) {
+	if prev == nil {
+		prev = &ChannelSettings{}
+	}
+	if next == nil {
+		next = &ChannelSettings{}
+	}
+	ch, err := r.c.Client.Channel(channel)
+	if err != nil {
+		r.fail(err)
+		return
+	}
+
+	if prev.ReplyMode != "" || next.ReplyMode != "" {
+		mode := next.ReplyMode
+		if mode == "" {
+			mode = twitch.ReplyMention
+		}
+		if ch.ReplyMode() != mode {
+			if err := ch.SetReplyMode(mode); err != nil {
+				r.fail(fmt.Errorf("channel '%s': %w", channel, err))
+			} else {
+				r.changes = append(r.changes, fmt.Sprintf("#%s: reply mode is now %s", channel, mode))
+			}
+		}
+	}
+	if prev.RestrictedMode != "" || next.RestrictedMode != "" {
+		mode := next.RestrictedMode
+		if mode == "" {
+			mode = twitch.RestrictedQueue
+		}
+		if ch.RestrictedMode() != mode {
+			if err := ch.SetRestrictedMode(mode); err != nil {
+				r.fail(fmt.Errorf("channel '%s': %w", channel, err))
+			} else {
+				r.changes = append(r.changes, fmt.Sprintf("#%s: restricted mode is now %s", channel, mode))
+			}
+		}
+	}
+
+	prevModules, nextModules := moduleSettings(prev), moduleSettings(next)
+	for _, name := range keys(prevModules, nextModules) {
+		r.module(ch, name, prevModules[name], nextModules[name])
+	}
+}
+
+func (r *reload) module(ch *twitch.Channel, name string, prev, next *ModuleSettings) {
+	if prev == nil {
+		prev = &ModuleSettings{}
+	}
+	if next == nil {
+		next = &ModuleSettings{}
+	}
+	m, err := ch.Module(name)
+	if err != nil {
+		r.fail(fmt.Errorf("channel '%s': %w", ch.Name, err))
+		return
+	}
+
+	enabled := defaultEnabled(name)
+	if next.Enabled != nil {
+		enabled = *next.Enabled
+	}
+	if overridden(prev.Enabled, next.Enabled) && ch.IsModuleEnabled(name) != enabled {
+		entry := audit.Entry{Action: audit.ActionModuleDisable, Channel: ch.Name, Target: name, Before: audit.StateEnabled, After: audit.StateDisabled}
+		err := r.c.Client.DisableModule(ch.Name, name)
+		if enabled {
+			entry = audit.Entry{Action: audit.ActionModuleEnable, Channel: ch.Name, Target: name, Before: audit.StateDisabled, After: audit.StateEnabled}
+			err = r.c.Client.EnableModule(ch.Name, name)
+		}
+		if err != nil {
+			r.fail(err)
+		} else {
+			r.changed(entry, "#%s: %s module %s", ch.Name, entry.After, name)
+		}
+	}
+
+	for _, command := range commandKeys(prev.Commands, next.Commands) {
+		r.command(ch, m, command, prev.Commands[command], next.Commands[command])
+	}
+}
+
+func (r *reload) command(ch *twitch.Channel, m *twitch.Module, name string, prev, next *CommandSettings) {
+	if prev == nil {
+		prev = &CommandSettings{}
+	}
+	if next == nil {
+		next = &CommandSettings{}
+	}
+	c, err := m.Command(name)
+	if err != nil {
+		r.fail(fmt.Errorf("channel '%s': %w", ch.Name, err))
+		return
+	}
+	target := m.Name + "/" + name
+
+	if enabled := next.Enabled == nil || *next.Enabled; overridden(prev.Enabled, next.Enabled) && m.IsCommandEnabled(name) != enabled {
+		entry := audit.Entry{Action: audit.ActionCommandDisable, Channel: ch.Name, Target: target, Before: audit.StateEnabled, After: audit.StateDisabled}
+		err := r.c.Client.DisableCommand(ch.Name, m.Name, name)
+		if enabled {
+			entry = audit.Entry{Action: audit.ActionCommandEnable, Channel: ch.Name, Target: target, Before: audit.StateDisabled, After: audit.StateEnabled}
+			err = r.c.Client.EnableCommand(ch.Name, m.Name, name)
+		}
+		if err != nil {
+			r.fail(err)
+		} else {
+			r.changed(entry, "#%s: %s command %s", ch.Name, entry.After, target)
+		}
+	}
+
+	cooldown := c.Cooldown
+	switch {
+	case next.Cooldown != nil:
+		cooldown = time.Duration(*next.Cooldown)
+	case prev.Cooldown != nil:
+		// Commands without a default, such as custom commands, keep their cooldown.
+		if d, ok := defaultCommand(m.Name, name); ok {
+			cooldown = d.Cooldown
+		}
+	}
+	if cooldown != c.Cooldown {
+		before := c.Cooldown
+		if err := r.c.Client.SetCommandCooldown(ch.Name, m.Name, name, cooldown); err != nil {
+			r.fail(err)
+			return
+		}
+		r.changed(audit.Entry{Action: audit.ActionCommandCooldown, Channel: ch.Name, Target: target, Before: before.String(), After: cooldown.String()},
+			"#%s: cooldown of %s is now %s", ch.Name, target, cooldown)
+	}
+}
+
+// overridden determines if either configuration sets a value.
+func overridden(prev, next *bool) bool {
+	return prev != nil || next != nil
+}
+
+// keys returns the sorted names of both sets of module overrides.
+func keys(a, b map[string]*ModuleSettings) []string {
+	seen := make(map[string]bool)
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	return sorted(seen)
+}
+
+// commandKeys returns the sorted names of both sets of command overrides.
+func commandKeys(a, b map[string]*CommandSettings) []string {
+	seen := make(map[string]bool)
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	return sorted(seen)
+}
+
+func sorted(set map[string]bool) []string {
+	list := make([]string, 0, len(set))
+	for k := range set {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+	return list
+}
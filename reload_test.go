@@ -0,0 +1,171 @@
+package roastedbot
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/alerts"
+	"github.com/brattonross/roastedbot/pkg/audit"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+func newTestController(config *Config) *Controller {
+	logger := log.New()
+	logger.Out = ioutil.Discard
+	c := NewController(config, logger)
+	c.loadChannels()
+	return c
+}
+
+func TestController_Reload(t *testing.T) {
+	c := newTestController(&Config{
+		Username: "bot",
+		OAuth:    "oauth:abc123",
+		Channels: []string{"foo", "bar"},
+	})
+
+	enabled, disabled := true, false
+	cooldown := Duration(30 * time.Second)
+	changes, err := c.Reload(&Config{
+		Username: "bot",
+		OAuth:    "oauth:abc123",
+		Channels: []string{"foo", "baz"},
+		Settings: map[string]*ChannelSettings{
+			"foo": {
+				ReplyMode: twitch.ReplyAction,
+				Modules: map[string]*ModuleSettings{
+					alerts.ModuleName: {Enabled: &enabled},
+					"general": {Commands: map[string]*CommandSettings{
+						"uptime": {Enabled: &disabled, Cooldown: &cooldown},
+					}},
+				},
+			},
+		},
+		HTTP: HTTPConfig{Address: ":8080"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []string{
+		"http changed and needs a restart to apply",
+		"parted #bar",
+		"joined #baz",
+		"#foo: reply mode is now action",
+		"#foo: enabled module events",
+		"#foo: disabled command general/uptime",
+		"#foo: cooldown of general/uptime is now 30s",
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes %q, got %q", expected, changes)
+	}
+
+	if _, err := c.Client.Channel("bar"); err == nil {
+		t.Error("expected bar to be parted")
+	}
+	if _, err := c.Client.Channel("baz"); err != nil {
+		t.Errorf("expected baz to be joined, got %v", err)
+	}
+	foo, _ := c.Client.Channel("foo")
+	if foo.ReplyMode() != twitch.ReplyAction {
+		t.Errorf("expected reply mode action, got %s", foo.ReplyMode())
+	}
+	if !foo.IsModuleEnabled(alerts.ModuleName) {
+		t.Error("expected the events module to be enabled")
+	}
+	general, _ := foo.Module("general")
+	uptime, _ := general.Command("uptime")
+	if general.IsCommandEnabled("uptime") || uptime.Cooldown != 30*time.Second {
+		t.Errorf("expected uptime to be disabled with a 30s cooldown, got %t and %s", general.IsCommandEnabled("uptime"), uptime.Cooldown)
+	}
+	if entries := c.Client.Audit.Query(audit.Query{Source: audit.SourceConfig}); len(entries) != 5 {
+		t.Errorf("expected 5 audit entries, got %d", len(entries))
+	}
+
+	// Removing the overrides returns the channel to its defaults, and the http
+	// change is reported until the bot restarts.
+	changes, err = c.Reload(&Config{
+		Username: "bot",
+		OAuth:    "oauth:abc123",
+		Channels: []string{"foo", "baz"},
+		HTTP:     HTTPConfig{Address: ":8080"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected = []string{
+		"http changed and needs a restart to apply",
+		"#foo: reply mode is now mention",
+		"#foo: disabled module events",
+		"#foo: enabled command general/uptime",
+		"#foo: cooldown of general/uptime is now " + twitch.UptimeCommand.Cooldown.String(),
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected changes %q, got %q", expected, changes)
+	}
+	if c.CurrentConfig().Settings != nil {
+		t.Error("expected the new configuration to be current")
+	}
+	if c.CurrentConfig().HTTP.Address != "" {
+		t.Errorf("expected the running http address to be kept, got %s", c.CurrentConfig().HTTP.Address)
+	}
+
+	// Reverting the change leaves nothing that needs a restart.
+	changes, err = c.Reload(&Config{
+		Username: "bot",
+		OAuth:    "oauth:abc123",
+		Channels: []string{"foo", "baz"},
+	})
+	if err != nil || len(changes) != 0 {
+		t.Errorf("expected no changes, got %q and %v", changes, err)
+	}
+}
+
+func TestController_EventsModule(t *testing.T) {
+	c := newTestController(&Config{
+		Username: "bot",
+		OAuth:    "oauth:abc123",
+		Channels: []string{"foo", "bar"},
+		Settings: map[string]*ChannelSettings{"foo": {Events: &alerts.Settings{MinimumBits: 100}}},
+	})
+	foo, _ := c.Client.Channel("foo")
+	bar, _ := c.Client.Channel("bar")
+	if !foo.IsModuleEnabled(alerts.ModuleName) || bar.IsModuleEnabled(alerts.ModuleName) {
+		t.Errorf("expected the events module to only be enabled in the channel with events configured, got %t and %t",
+			foo.IsModuleEnabled(alerts.ModuleName), bar.IsModuleEnabled(alerts.ModuleName))
+	}
+}
+
+func TestController_ReloadUnchanged(t *testing.T) {
+	config := &Config{Username: "bot", OAuth: "oauth:abc123", Channels: []string{"foo"}}
+	c := newTestController(config)
+	// Changes made at runtime are kept if the configuration does not mention them.
+	c.Client.DisableModule("foo", "general")
+
+	changes, err := c.Reload(&Config{Username: "bot", OAuth: "oauth:abc123", Channels: []string{"foo"}})
+	if err != nil || len(changes) != 0 {
+		t.Errorf("expected no changes, got %q and %v", changes, err)
+	}
+	foo, _ := c.Client.Channel("foo")
+	if foo.IsModuleEnabled("general") {
+		t.Error("expected the general module to stay disabled")
+	}
+}
+
+func TestController_ReloadUnknownModule(t *testing.T) {
+	c := newTestController(&Config{Username: "bot", OAuth: "oauth:abc123", Channels: []string{"foo"}})
+	_, err := c.Reload(&Config{
+		Username: "bot",
+		OAuth:    "oauth:abc123",
+		Channels: []string{"foo"},
+		Settings: map[string]*ChannelSettings{
+			"foo": {Modules: map[string]*ModuleSettings{twitch.CustomModule: {}}},
+		},
+	})
+	if err == nil {
+		t.Error("expected an error for a module that the channel does not have, got nil")
+	}
+}
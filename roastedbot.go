@@ -2,45 +2,303 @@ package roastedbot
 
 import (
 	"fmt"
+	"io"
 	"strings"
+	"sync"
 	"time"
 
 	tirc "github.com/gempir/go-twitch-irc"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/brattonross/roastedbot/pkg/admin"
+	"github.com/brattonross/roastedbot/pkg/alerts"
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/chatlog"
+	"github.com/brattonross/roastedbot/pkg/event"
 	"github.com/brattonross/roastedbot/pkg/twitch"
 )
 
 // Config for the bot.
 type Config struct {
-	Username string   `json:"username"`
-	OAuth    string   `json:"oauth"`
+	Username string `json:"username"`
+	// OAuth is the chat token of the bot's account, in the form "oauth:<token>".
+	OAuth    Secret   `json:"oauth"`
 	Channels []string `json:"channels"`
+	// AutoPartBanned makes the bot leave channels that it is permanently banned from.
+	AutoPartBanned bool `json:"autoPartBanned"`
+	// Token configures the validation and refresh of the chat token.
+	Token TokenConfig `json:"token"`
+	// Settings holds per-channel settings, keyed by channel name.
+	Settings map[string]*ChannelSettings `json:"settings"`
+	// Log configures the logger.
+	Log LogConfig `json:"log"`
+	// Audit configures the audit log of administrative actions.
+	Audit AuditConfig `json:"audit"`
+	// ChatLog configures the archive of the chat in the bot's channels.
+	ChatLog ChatLogConfig `json:"chatLog"`
+	// Store configures where the state of the bot's channels is kept.
+	Store StoreConfig `json:"store"`
+	// API configures the management API.
+	API APIConfig `json:"api"`
+	// HTTP configures the server that serves the API and the dashboard.
+	HTTP HTTPConfig `json:"http"`
+	// Metrics configures the Prometheus metrics served by the HTTP server.
+	Metrics MetricsConfig `json:"metrics"`
+	// GRPC configures the gRPC server that serves the BotAdmin service.
+	GRPC GRPCConfig `json:"grpc"`
+}
+
+// TokenConfig configures the validation and refresh of the chat token.
+type TokenConfig struct {
+	// Disabled turns off validating the token with twitch.
+	Disabled bool `json:"disabled"`
+	// RefreshToken renews the chat token before it expires, using the
+	// credentials of the twitch application that the token was issued to.
+	RefreshToken Secret `json:"refreshToken"`
+	ClientID     string `json:"clientId"`
+	ClientSecret Secret `json:"clientSecret"`
+	// Path is the file that refreshed tokens are stored in, which are used in
+	// place of oauth and refreshToken when the bot starts, unless those have
+	// changed since the tokens were refreshed. Defaults to "token.json".
+	Path string `json:"path"`
+	// ValidateURL and TokenURL replace twitch's endpoints, such as with a local stand-in.
+	ValidateURL string `json:"validateUrl"`
+	TokenURL    string `json:"tokenUrl"`
+	// Interval is how often the token is validated. Defaults to an hour.
+	Interval Duration `json:"interval"`
+}
+
+// HTTPConfig configures the HTTP server. Zero values use the server's defaults.
+type HTTPConfig struct {
+	// Disabled turns the HTTP server off.
+	Disabled bool `json:"disabled"`
+	// Address is the address to listen on. Defaults to ":9001".
+	Address string `json:"address"`
+	// TLS serves HTTPS instead of HTTP.
+	TLS          *TLSConfig `json:"tls"`
+	ReadTimeout  Duration   `json:"readTimeout"`
+	WriteTimeout Duration   `json:"writeTimeout"`
+	IdleTimeout  Duration   `json:"idleTimeout"`
+	// ShutdownGrace is how long requests are given to finish when the bot stops.
+	ShutdownGrace Duration `json:"shutdownGrace"`
+}
+
+// TLSConfig configures HTTPS. Either a certificate and key or
+// a self-signed certificate for development must be set.
+type TLSConfig struct {
+	CertFile   string `json:"certFile"`
+	KeyFile    string `json:"keyFile"`
+	SelfSigned bool   `json:"selfSigned"`
+}
+
+// AuditConfig configures the audit log.
+type AuditConfig struct {
+	// Path is the file that the audit log is stored in. Defaults to "audit.jsonl".
+	Path string `json:"path"`
+}
+
+// StoreConfig configures the store of the channels' state.
+type StoreConfig struct {
+	// Enabled restores the state when the bot starts and stores it while it
+	// runs. The state is not stored by default.
+	Enabled bool `json:"enabled"`
+	// Path is the file that the state is stored in. Defaults to "state.json".
+	Path string `json:"path"`
+}
+
+// ChatLogConfig configures the chat log archive.
+type ChatLogConfig struct {
+	// Enabled turns the archive on.
+	Enabled bool `json:"enabled"`
+	// Dir is the directory that the logs are stored in. Defaults to "logs".
+	Dir string `json:"dir"`
+	// RetentionDays is how many days logs are kept for. Logs are kept forever if it is zero.
+	RetentionDays int `json:"retentionDays"`
+	// Compress gzips the logs of previous days.
+	Compress bool `json:"compress"`
+	// Search indexes the logs so that they can be searched.
+	Search bool `json:"search"`
+}
+
+// MetricsConfig configures the Prometheus metrics.
+type MetricsConfig struct {
+	// Disabled stops the HTTP server from serving metrics on /metrics.
+	Disabled bool `json:"disabled"`
+}
+
+// GRPCConfig configures the gRPC server. It uses the same API tokens as the HTTP API.
+type GRPCConfig struct {
+	// Address is the address to listen on. The gRPC server is disabled if it is empty.
+	Address string `json:"address"`
+	// CertFile and KeyFile serve the service over TLS when both are set.
+	CertFile string `json:"certFile"`
+	KeyFile  string `json:"keyFile"`
+	// ShutdownGrace is how long calls are given to finish when the bot stops.
+	ShutdownGrace Duration `json:"shutdownGrace"`
+}
+
+// APIConfig configures access to the management API.
+type APIConfig struct {
+	// AllowedOrigins are the origins that browsers may call the API from.
+	AllowedOrigins []string `json:"allowedOrigins"`
+	// BaseURL is the URL that browsers reach the API at, which the dashboard
+	// uses to find the API. Defaults to the dashboard's own origin.
+	BaseURL string `json:"baseUrl"`
+	// Login lets broadcasters and their moderators log in to the dashboard
+	// with twitch. Login is disabled if it is not set.
+	Login *auth.LoginConfig `json:"login"`
+	// Tokens are the API tokens that are allowed to use the API.
+	Tokens []auth.Token `json:"tokens"`
+}
+
+// ChannelSettings configures the bot's behaviour in a single channel.
+type ChannelSettings struct {
+	// Events configures the messages sent by the events module.
+	Events *alerts.Settings `json:"events"`
+	// ReplyMode decides how command responses are sent: "mention" prefixes
+	// them with the user's name, "thread" sends them as threaded replies and
+	// "action" sends them as /me actions. Defaults to "mention".
+	ReplyMode string `json:"replyMode"`
+	// RestrictedMode decides whether messages are queued or dropped while the
+	// channel is in emote-only or subs-only mode. Defaults to "queue".
+	RestrictedMode string `json:"restrictedMode"`
+	// Modules overrides the modules of the channel, keyed by module name.
+	Modules map[string]*ModuleSettings `json:"modules"`
+}
+
+// ModuleSettings overrides the state of a module in a channel.
+type ModuleSettings struct {
+	// Enabled turns the module on or off. Modules other than events are
+	// enabled if it is not set, and the events module is enabled if the
+	// channel's events are configured.
+	Enabled *bool `json:"enabled"`
+	// Commands overrides the commands of the module, keyed by command name.
+	Commands map[string]*CommandSettings `json:"commands"`
+}
+
+// CommandSettings overrides the state of a command in a channel.
+type CommandSettings struct {
+	// Enabled turns the command on or off. Commands are enabled if it is not set.
+	Enabled *bool `json:"enabled"`
+	// Cooldown replaces the command's default cooldown.
+	Cooldown *Duration `json:"cooldown"`
+}
+
+// requiredModules are added to every channel with all of their commands enabled.
+var requiredModules = []struct {
+	name     string
+	commands []*twitch.Command
+	// disabled modules are only enabled by the channel's settings.
+	disabled bool
+}{
+	{"admin", admin.Commands, false},
+	{"general", []*twitch.Command{twitch.HelpCommand, twitch.UptimeCommand}, false},
+	// Thanking subscribers is left to channels that ask for it.
+	{alerts.ModuleName, nil, true},
+	// Chat is archived in every channel when the chat log is enabled,
+	// unless the channel disables the module.
+	{chatlog.ModuleName, nil, false},
+}
+
+// defaultEnabled determines if a module is enabled when the settings do not say.
+func defaultEnabled(module string) bool {
+	for _, m := range requiredModules {
+		if m.name == module {
+			return !m.disabled
+		}
+	}
+	return true
+}
+
+// moduleSettings returns the module overrides of a channel's settings.
+// Configuring the events of a channel enables its events module,
+// unless the module is turned off explicitly.
+func moduleSettings(s *ChannelSettings) map[string]*ModuleSettings {
+	if s.Events == nil {
+		return s.Modules
+	}
+	modules := make(map[string]*ModuleSettings, len(s.Modules)+1)
+	for name, m := range s.Modules {
+		modules[name] = m
+	}
+	events := &ModuleSettings{}
+	if m := s.Modules[alerts.ModuleName]; m != nil {
+		*events = *m
+	}
+	if events.Enabled == nil {
+		enabled := true
+		events.Enabled = &enabled
+	}
+	modules[alerts.ModuleName] = events
+	return modules
+}
+
+// defaultCommand returns the definition of a command in one of the required modules.
+func defaultCommand(module, command string) (*twitch.Command, bool) {
+	for _, m := range requiredModules {
+		if m.name != module {
+			continue
+		}
+		for _, c := range m.commands {
+			if c.Name == command {
+				return c, true
+			}
+		}
+	}
+	return nil, false
 }
 
 // Controller is the application controller.
 type Controller struct {
 	Client *twitch.Client
+	// Config is the configuration that the bot is running with. It is
+	// replaced by Reload, so it should be read with CurrentConfig.
 	Config *Config
 
-	log    *log.Logger
+	alerts      *alerts.Alerts
+	log         *log.Logger
+	mutex       *sync.Mutex
+	reloadMutex *sync.Mutex
+}
+
+// CurrentConfig returns the configuration that the bot is running with.
+func (c *Controller) CurrentConfig() *Config {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.Config
 }
 
 // NewController creates a new bot controller.
-func NewController(config *Config, log *log.Logger) *Controller {
-	irc := tirc.NewClient(config.Username, config.OAuth)
+func NewController(config *Config, logger *log.Logger) *Controller {
+	irc := tirc.NewClient(config.Username, string(config.OAuth))
 	// TODO: DB driver
 	client := twitch.NewClient(config.Username, irc)
+	client.SetAutoPartBanned(config.AutoPartBanned)
+	client.Log = logger
 	client.OnConnect(func() {
-		log.Info("connected to twitch")
+		logger.Info("connected to twitch")
 	})
-
-	return &Controller{
-		client,
-		config,
-		log,
+	client.Events.Subscribe(func(e event.Event) {
+		r := e.(event.ChannelRestricted)
+		logger.WithField("channel", r.Channel).
+			WithField("reason", r.Reason).
+			WithField("until", r.Until).
+			Warn("bot is restricted from chatting in channel")
+	}, event.TypeChannelRestricted)
+
+	a := alerts.New(client, eventSettings(config), logger)
+	a.Subscribe()
+
+	c := &Controller{
+		Client:      client,
+		Config:      config,
+		alerts:      a,
+		log:         logger,
+		mutex:       &sync.Mutex{},
+		reloadMutex: &sync.Mutex{},
 	}
+	client.OnChannelAdded(c.setupChannel)
+	return c
 }
 
 // Connect joins any channels that the bot is configured
@@ -54,66 +312,105 @@ func (c *Controller) Connect() error {
 	}()
 
 	c.loadChannels()
-	for _, channel := range c.Client.Channels() {
-		c.addRequiredModules(channel.Name)
-	}
 	c.Client.JoinChannels()
 
 	c.Client.OnNewMessage(c.onNewMessage)
+	c.Client.OnNewWhisper(c.onNewWhisper)
 
 	return c.Client.Connect()
 }
 
+// Console runs the bot without connecting to twitch, for trying it locally.
+// The bot's messages are written to w, and it is given messages with the
+// Client's Receive and ReceiveWhisper. It must be called before Restore.
+func (c *Controller) Console(w io.Writer) {
+	c.Client.Console(w)
+	c.Client.OnNewMessage(c.onNewMessage)
+	c.Client.OnNewWhisper(c.onNewWhisper)
+}
+
 // Disconnect will disconnect the client from twitch.
 func (c *Controller) Disconnect() error {
 	return c.Client.Disconnect()
 }
 
+// setupChannel prepares a channel that has been added to the client.
+func (c *Controller) setupChannel(channel string) {
+	c.addRequiredModules(channel)
+	c.applySettings(channel)
+}
+
 func (c *Controller) addRequiredModules(channel string) {
-	adminModule, err := c.Client.AddModule(channel, "admin")
-	if err != nil {
-		c.log.Errorf("failed to add admin module: %v", err)
-	} else {
-		adminModule.AddCommand(admin.EnableCommand)
-		adminModule.EnableCommand(admin.EnableCommand.Name)
-		c.Client.EnableModule(channel, "admin")
+	for _, required := range requiredModules {
+		m, err := c.Client.AddModule(channel, required.name)
+		if err != nil {
+			c.log.Errorf("failed to add %s module: %v", required.name, err)
+			continue
+		}
+		for _, command := range required.commands {
+			m.AddCommand(command)
+			m.EnableCommand(command.Name)
+		}
+		if !required.disabled {
+			c.Client.EnableModule(channel, required.name)
+		}
 	}
+}
 
-	general, err := c.Client.AddModule(channel, "general")
-	if err != nil {
-		c.log.Errorf("failed to add general module: %v", err)
-	} else {
-		general.AddCommand(twitch.HelpCommand)
-		general.EnableCommand(twitch.HelpCommand.Name)
-
-		general.AddCommand(twitch.UptimeCommand)
-		general.EnableCommand(twitch.UptimeCommand.Name)
-
-		c.Client.EnableModule(channel, "general")
+// eventSettings returns the events module's settings of each channel.
+func eventSettings(config *Config) map[string]*alerts.Settings {
+	settings := make(map[string]*alerts.Settings)
+	for channel, s := range config.Settings {
+		if s != nil && s.Events != nil {
+			settings[channel] = s.Events
+		}
 	}
+	return settings
 }
 
 // LoadChannels loads the channels that the bot should join on start.
 func (c *Controller) loadChannels() {
-	for _, ch := range c.Config.Channels {
+	for _, ch := range c.CurrentConfig().Channels {
+		if _, err := c.Client.Channel(ch); err == nil {
+			// The channel was restored from the store.
+			continue
+		}
 		if err := c.Client.AddChannel(ch); err != nil {
 			c.log.Errorf("failed to load channel: %v", err)
 		}
 	}
 }
 
+// applySettings applies the configured settings of a channel that has been added.
+func (c *Controller) applySettings(channel string) {
+	r := &reload{c: c}
+	r.channel(channel, nil, c.CurrentConfig().Settings[channel])
+	for _, err := range r.errs {
+		c.log.WithField("channel", channel).Errorf("failed to apply settings: %v", err)
+	}
+}
+
 func (c *Controller) onNewMessage(channel string, user tirc.User, message tirc.Message) {
 	username := strings.ToLower(c.Client.Username)
 	if strings.ToLower(user.Username) == username {
 		return
 	}
 
+	logger := c.Client.Logger(message)
 	ch, err := c.Client.Channel(channel)
 	if err != nil {
-		log.WithField("channel", channel).Error("cannot handle message: channel is not configured")
+		logger.WithField("channel", channel).Error("cannot handle message: channel is not configured")
 		return
 	}
 
+	c.Client.Events.Publish(event.MessageReceived{
+		Channel:     channel,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Text:        message.Text,
+		Time:        message.Time,
+	})
+
 	args := strings.Split(message.Text, " ")
 	if len(args) < 1 {
 		return
@@ -147,58 +444,130 @@ func (c *Controller) onNewMessage(channel string, user tirc.User, message tirc.M
 		args = args[:len(args)-1]
 	}
 
-	log.WithFields(log.Fields{
+	logger.WithFields(log.Fields{
 		"channel": channel,
 		"text":    message.Text,
 		"user":    user.DisplayName,
 	}).Info("handling message")
 
+	c.handleCommand(ch, args, user, message)
+}
+
+func (c *Controller) onNewWhisper(user tirc.User, message tirc.Message) {
+	args := strings.Fields(message.Text)
+	if len(args) < 1 {
+		return
+	}
+
+	// Whispers can target a channel with a leading #channel argument,
+	// otherwise they target the channel of the user who sent them.
+	channel := strings.ToLower(user.Username)
+	if strings.HasPrefix(args[0], "#") {
+		channel = strings.ToLower(args[0][1:])
+		args = args[1:]
+	}
+	if len(args) < 1 {
+		return
+	}
+
+	ch, err := c.Client.Channel(channel)
+	if err != nil {
+		// Strangers are ignored rather than told how to target a channel.
+		if c.Client.CanWhisper(user) {
+			c.Client.Reply(channel, message, "Specify a channel to use, e.g. #channel help")
+		}
+		return
+	}
+
+	c.Client.Logger(message).WithFields(log.Fields{
+		"channel": channel,
+		"text":    message.Text,
+		"user":    user.DisplayName,
+	}).Info("handling whisper")
+
+	c.handleCommand(ch, args, user, message)
+}
+
+// handleCommand executes the command matching the args, if there is one.
+// Commands are only executed if they can be invoked the way that the message was sent.
+func (c *Controller) handleCommand(ch *twitch.Channel, args []string, user tirc.User, message tirc.Message) {
+	channel := ch.Name
 	command, module := ch.MatchCommand(args)
 	if command == nil {
 		return
 	}
+	logger := c.Client.Logger(message).WithFields(log.Fields{
+		"channel": channel,
+		"command": command.Name,
+		"module":  module.Name,
+		"user":    user.DisplayName,
+	})
+
+	whisper := message.Type == tirc.WHISPER
+	if (whisper && !command.AllowsWhisper()) || (!whisper && !command.AllowsChat()) {
+		logger.WithField("whisper", whisper).Info("command cannot be invoked this way")
+		c.publishSkipped(ch, module, command, user, event.SkippedNotAllowed)
+		return
+	}
+	if !command.Permits(user) {
+		logger.Info("user is not permitted to use the command")
+		c.publishSkipped(ch, module, command, user, event.SkippedNotAllowed)
+		return
+	}
 
 	if !module.IsCommandEnabled(command.Name) {
-		log.WithFields(log.Fields{
-			"channel": channel,
-			"command": command.Name,
-			"module":  module.Name,
-			"user":    user.DisplayName,
-		}).Info("command is not enabled")
+		logger.Info("command is not enabled")
+		c.publishSkipped(ch, module, command, user, event.SkippedDisabled)
 		return
 	}
 	if command.IsOnCooldown() {
-		log.WithFields(log.Fields{
-			"channel": channel,
-			"command": command.Name,
-			"module":  module.Name,
-			"user":    user.DisplayName,
-		}).Info("command is on cooldown")
+		logger.Info("command is on cooldown")
+		c.publishSkipped(ch, module, command, user, event.SkippedCooldown)
 		return
 	}
 
 	go func() {
-		log.WithFields(log.Fields{
-			"channel": channel,
-			"command": command.Name,
-			"module":  module.Name,
-			"user":    user.DisplayName,
-		}).Info("executing command")
+		logger.Info("executing command")
 		start := time.Now()
 
-		defer log.WithFields(log.Fields{
-			"channel": channel,
-			"command": command.Name,
-			"delta":   fmt.Sprintf("%dms", time.Now().Sub(start)/time.Millisecond),
-			"module":  module.Name,
-			"user":    user.DisplayName,
-		}).Info("finished executing command")
-
-		command.Execute(c.Client, args, channel, user, message)
+		if err := command.Execute(c.Client, args, channel, user, message); err != nil {
+			logger.WithField("error", err).Error("failed to execute command")
+			c.Client.Events.Publish(event.CommandFailed{
+				Channel:  channel,
+				Module:   module.Name,
+				Command:  command.Name,
+				Username: user.Username,
+				Error:    err.Error(),
+				Time:     time.Now(),
+			})
+			return
+		}
 		command.LastUsed = time.Now()
+
+		delta := time.Since(start)
+		logger.WithField("delta", fmt.Sprintf("%dms", delta/time.Millisecond)).Info("finished executing command")
+		c.Client.Events.Publish(event.CommandExecuted{
+			Channel:  channel,
+			Module:   module.Name,
+			Command:  command.Name,
+			Username: user.Username,
+			Duration: delta,
+			Time:     time.Now(),
+		})
 	}()
 }
 
+func (c *Controller) publishSkipped(ch *twitch.Channel, module *twitch.Module, command *twitch.Command, user tirc.User, reason string) {
+	c.Client.Events.Publish(event.CommandSkipped{
+		Channel:  ch.Name,
+		Module:   module.Name,
+		Command:  command.Name,
+		Username: user.Username,
+		Reason:   reason,
+		Time:     time.Now(),
+	})
+}
+
 func isMention(s, username string) bool {
 	if strings.HasPrefix(s, "@") {
 		s = s[1:]
@@ -0,0 +1,51 @@
+package roastedbot
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces secrets when they are printed.
+const redacted = "[redacted]"
+
+// Secret is a configuration value, such as a token, that is
+// redacted when it is printed, logged or marshaled.
+type Secret string
+
+// String implements fmt.Stringer.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString implements fmt.GoStringer.
+func (s Secret) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// oauthPattern matches twitch chat tokens wherever they appear.
+var oauthPattern = regexp.MustCompile(`oauth:[A-Za-z0-9]+`)
+
+var oauthTokenPattern = regexp.MustCompile(`^oauth:[A-Za-z0-9]+$`)
+
+// ValidateOAuth checks that the token has the "oauth:<token>" form that twitch chat expects.
+// The error does not include the token.
+func ValidateOAuth(token Secret) error {
+	switch {
+	case token == "":
+		return errors.New("oauth is not set")
+	case !strings.HasPrefix(string(token), "oauth:"):
+		return errors.New(`oauth must start with "oauth:"`)
+	case !oauthTokenPattern.MatchString(string(token)):
+		return errors.New(`oauth must be "oauth:" followed by the token's letters and digits`)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package roastedbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSecret(t *testing.T) {
+	c := Config{Username: "bot", OAuth: "oauth:abc123"}
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{string(b), fmt.Sprintf("%v", c), fmt.Sprintf("%+v", c), fmt.Sprintf("%#v", c), c.OAuth.String()} {
+		if strings.Contains(s, "abc123") {
+			t.Errorf("expected the token to be redacted, got %s", s)
+		}
+	}
+	if Secret("").String() != "" {
+		t.Error("expected an empty secret to print as empty")
+	}
+}
+
+func TestValidateOAuth(t *testing.T) {
+	if err := ValidateOAuth("oauth:abc123"); err != nil {
+		t.Errorf("expected a valid token, got %v", err)
+	}
+	for _, token := range []Secret{"", "abc123", "oauth:", "oauth:oauth:abc123", "oauth:abc 123"} {
+		err := ValidateOAuth(token)
+		if err == nil {
+			t.Errorf("expected an error for %q", string(token))
+		} else if strings.Contains(err.Error(), "abc") {
+			t.Errorf("expected the error not to contain the token, got %v", err)
+		}
+	}
+}
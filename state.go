@@ -0,0 +1,164 @@
+package roastedbot
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/store"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// State returns the state of the bot's channels to be kept in the store.
+func (c *Controller) State() *store.State {
+	s := store.New()
+	for _, joined := range c.Client.Channels() {
+		ch, err := c.Client.Channel(joined.Name)
+		if err != nil {
+			continue
+		}
+		stored := store.Channel{
+			Name:           ch.Name,
+			ReplyMode:      ch.ReplyMode(),
+			RestrictedMode: ch.RestrictedMode(),
+		}
+		for _, m := range ch.Modules() {
+			module := store.Module{Name: m.Name, Enabled: ch.IsModuleEnabled(m.Name)}
+			for _, command := range m.Commands() {
+				module.Commands = append(module.Commands, store.Command{
+					Name:     command.Name,
+					Enabled:  m.IsCommandEnabled(command.Name),
+					Cooldown: store.Duration(command.Cooldown),
+					Response: command.Response,
+				})
+			}
+			sort.Slice(module.Commands, func(i, j int) bool { return module.Commands[i].Name < module.Commands[j].Name })
+			stored.Modules = append(stored.Modules, module)
+		}
+		sort.Slice(stored.Modules, func(i, j int) bool { return stored.Modules[i].Name < stored.Modules[j].Name })
+		s.Channels = append(s.Channels, stored)
+	}
+	sort.Slice(s.Channels, func(i, j int) bool { return s.Channels[i].Name < s.Channels[j].Name })
+	return s
+}
+
+// Restore adds the configured channels and returns them to the state that was
+// stored when the bot last ran. Stored channels that are no longer configured
+// are skipped. The configured settings of each channel are applied on top of
+// its stored state, so that settings in the configuration take precedence.
+// Restore should be called before Connect.
+func (c *Controller) Restore(s *store.State) error {
+	c.loadChannels()
+	var errs []error
+	for _, stored := range s.Channels {
+		ch, err := c.Client.Channel(stored.Name)
+		if err != nil {
+			c.log.WithField("channel", stored.Name).Info("stored channel is not configured, skipping its state")
+			continue
+		}
+		errs = append(errs, restoreChannel(c.Client, ch, stored)...)
+		c.applySettings(ch.Name)
+	}
+	return errors.Join(errs...)
+}
+
+func restoreChannel(cl *twitch.Client, ch *twitch.Channel, s store.Channel) []error {
+	var errs []error
+	fail := func(err error) {
+		errs = append(errs, fmt.Errorf("channel '%s': %w", ch.Name, err))
+	}
+	if s.ReplyMode != "" {
+		if err := ch.SetReplyMode(s.ReplyMode); err != nil {
+			fail(err)
+		}
+	}
+	if s.RestrictedMode != "" {
+		if err := ch.SetRestrictedMode(s.RestrictedMode); err != nil {
+			fail(err)
+		}
+	}
+
+	for _, module := range s.Modules {
+		if module.Name == twitch.CustomModule {
+			if len(module.Commands) == 0 {
+				continue
+			}
+			for _, command := range module.Commands {
+				if err := cl.SetCustomCommand(ch.Name, command.Name, command.Response, time.Duration(command.Cooldown)); err != nil {
+					fail(err)
+				}
+			}
+		}
+		m, err := ch.Module(module.Name)
+		if err != nil {
+			fail(err)
+			continue
+		}
+		if module.Enabled {
+			ch.EnableModule(module.Name)
+		} else {
+			ch.DisableModule(module.Name)
+		}
+		for _, command := range module.Commands {
+			if err := m.SetCooldown(command.Name, time.Duration(command.Cooldown)); err != nil {
+				fail(err)
+				continue
+			}
+			if command.Enabled {
+				m.EnableCommand(command.Name)
+			} else {
+				m.DisableCommand(command.Name)
+			}
+		}
+	}
+	return errs
+}
+
+// SaveState stores the state of the bot's channels at path.
+func (c *Controller) SaveState(path string) error {
+	return store.Save(path, c.State())
+}
+
+// StartSavingState stores the state of the bot's channels at path each interval
+// if it has changed, until the returned function is called, which stores it once more.
+func (c *Controller) StartSavingState(path string, interval time.Duration) (stop func()) {
+	saved := c.State()
+	save := func(force bool) {
+		s := c.State()
+		if !force && reflect.DeepEqual(s, saved) {
+			return
+		}
+		if err := store.Save(path, s); err != nil {
+			c.log.WithField("error", err).Error("failed to store channel state")
+			return
+		}
+		saved = s
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				save(false)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+			save(true)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package roastedbot
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brattonross/roastedbot/pkg/alerts"
+	"github.com/brattonross/roastedbot/pkg/store"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+func TestController_StateRestore(t *testing.T) {
+	config := &Config{Username: "bot", OAuth: "oauth:abc123", Channels: []string{"foo", "bar"}}
+	c := newTestController(config)
+	c.Client.EnableModule("foo", alerts.ModuleName)
+	c.Client.DisableCommand("foo", "general", "uptime")
+	c.Client.SetCommandCooldown("foo", "general", "help", time.Minute)
+	c.Client.SetCustomCommand("foo", "discord", "join us", 5*time.Second)
+	foo, _ := c.Client.Channel("foo")
+	foo.SetReplyMode(twitch.ReplyAction)
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := c.SaveState(path); err != nil {
+		t.Fatal(err)
+	}
+	s, err := store.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Channels) != 2 || s.Channels[0].Name != "bar" || s.Channels[1].Name != "foo" {
+		t.Fatalf("expected the state of bar and foo, got %+v", s.Channels)
+	}
+
+	// The configured reply mode takes precedence over the stored one, and
+	// stored channels that are no longer configured are skipped.
+	restored := NewController(&Config{
+		Username: "bot",
+		OAuth:    "oauth:abc123",
+		Channels: []string{"foo"},
+		Settings: map[string]*ChannelSettings{"foo": {ReplyMode: twitch.ReplyMention}},
+	}, c.log)
+	if err := restored.Restore(s); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := restored.Client.Channel("bar"); err == nil {
+		t.Error("expected bar not to be restored")
+	}
+	foo, err = restored.Client.Channel("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foo.ReplyMode() != twitch.ReplyMention {
+		t.Errorf("expected the configured reply mode, got %s", foo.ReplyMode())
+	}
+	if !foo.IsModuleEnabled(alerts.ModuleName) {
+		t.Error("expected the events module to stay enabled")
+	}
+	general, _ := foo.Module("general")
+	help, _ := general.Command("help")
+	if general.IsCommandEnabled("uptime") || help.Cooldown != time.Minute {
+		t.Errorf("expected uptime to be disabled and help to have a 1m cooldown, got %t and %s", general.IsCommandEnabled("uptime"), help.Cooldown)
+	}
+	custom, err := restored.Client.CustomCommands("foo")
+	if err != nil || len(custom) != 1 || custom[0].Response != "join us" || custom[0].Cooldown != 5*time.Second {
+		t.Errorf("expected the custom command to be restored, got %+v and %v", custom, err)
+	}
+}
+
+func TestController_RestoreUnknownModule(t *testing.T) {
+	c := newTestController(&Config{Username: "bot", OAuth: "oauth:abc123", Channels: []string{"foo"}})
+	s := store.New()
+	s.Channels = []store.Channel{{Name: "foo", Modules: []store.Module{{Name: "missing"}}}}
+	if err := c.Restore(s); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected an error for the missing module, got %v", err)
+	}
+}
@@ -0,0 +1 @@
+module github.com/gempir/go-twitch-irc
@@ -0,0 +1,177 @@
+package roastedbot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/brattonross/roastedbot/pkg/auth"
+	"github.com/brattonross/roastedbot/pkg/channelname"
+	"github.com/brattonross/roastedbot/pkg/twitch"
+)
+
+// Validate checks every value of the configuration, returning all of the
+// problems as ConfigErrors, or nil if there are none.
+func (c *Config) Validate() error {
+	v := &validator{}
+	if c.Username == "" {
+		v.fail("username", "is not set")
+	}
+	if err := ValidateOAuth(c.OAuth); err != nil {
+		v.fail("oauth", strings.TrimPrefix(err.Error(), "oauth "))
+	}
+
+	seen := make(map[string]bool)
+	for i, ch := range c.Channels {
+		path := fmt.Sprintf("channels[%d]", i)
+		v.channel(path, ch)
+		if seen[ch] {
+			v.fail(path, fmt.Sprintf("channel '%s' is listed more than once", ch))
+		}
+		seen[ch] = true
+	}
+	channels := make([]string, 0, len(c.Settings))
+	for ch := range c.Settings {
+		channels = append(channels, ch)
+	}
+	sort.Strings(channels)
+	for _, ch := range channels {
+		s := c.Settings[ch]
+		path := join("settings", ch)
+		v.channel(path, ch)
+		if s == nil {
+			continue
+		}
+		switch s.ReplyMode {
+		case "", twitch.ReplyMention, twitch.ReplyThread, twitch.ReplyAction:
+		default:
+			v.fail(path+".replyMode", fmt.Sprintf("unknown reply mode '%s': expected \"mention\", \"thread\" or \"action\"", s.ReplyMode))
+		}
+		switch s.RestrictedMode {
+		case "", twitch.RestrictedQueue, twitch.RestrictedDrop:
+		default:
+			v.fail(path+".restrictedMode", fmt.Sprintf("unknown restricted mode '%s': expected \"queue\" or \"drop\"", s.RestrictedMode))
+		}
+		if s.Events != nil {
+			if err := s.Events.Validate(); err != nil {
+				v.fail(path+".events", err.Error())
+			}
+		}
+		v.modules(path+".modules", s.Modules)
+	}
+
+	if c.Log.Level != "" {
+		if _, err := log.ParseLevel(c.Log.Level); err != nil {
+			v.fail("log.level", fmt.Sprintf("unknown log level '%s'", c.Log.Level))
+		}
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "", "text", "json":
+	default:
+		v.fail("log.format", fmt.Sprintf("unknown log format '%s': expected \"text\" or \"json\"", c.Log.Format))
+	}
+
+	if c.Token.RefreshToken != "" && (c.Token.ClientID == "" || c.Token.ClientSecret == "") {
+		v.fail("token.refreshToken", "requires token.clientId and token.clientSecret")
+	}
+	if c.ChatLog.RetentionDays < 0 {
+		v.fail("chatLog.retentionDays", "cannot be negative")
+	}
+	c.API.validate(v)
+	if tls := c.HTTP.TLS; tls != nil && !tls.SelfSigned && (tls.CertFile == "" || tls.KeyFile == "") {
+		v.fail("http.tls", "requires certFile and keyFile, or selfSigned")
+	}
+	if (c.GRPC.CertFile == "") != (c.GRPC.KeyFile == "") {
+		v.fail("grpc", "certFile and keyFile must be set together")
+	}
+	return v.err()
+}
+
+func (c *APIConfig) validate(v *validator) {
+	names := make(map[string]bool)
+	for i, t := range c.Tokens {
+		path := fmt.Sprintf("api.tokens[%d]", i)
+		switch {
+		case t.Name == "":
+			v.fail(path+".name", "is not set")
+		case names[t.Name]:
+			v.fail(path+".name", fmt.Sprintf("token '%s' is listed more than once", t.Name))
+		}
+		names[t.Name] = true
+		if hash, err := hex.DecodeString(t.Hash); err != nil || len(hash) != sha256.Size {
+			v.fail(path+".hash", "must be a hex encoded SHA-256 hash, as printed by -hash-token")
+		}
+		if len(t.Scopes) == 0 {
+			v.fail(path+".scopes", "must have at least one scope")
+		}
+		for j, s := range t.Scopes {
+			if !auth.ValidScope(s) {
+				v.fail(fmt.Sprintf("%s.scopes[%d]", path, j), fmt.Sprintf("unknown scope '%s': expected \"read\", \"channel-admin\" or \"global-admin\"", s))
+			}
+		}
+		for j, ch := range t.Channels {
+			v.channel(fmt.Sprintf("%s.channels[%d]", path, j), ch)
+		}
+	}
+	if c.Login != nil {
+		if c.Login.ClientID == "" {
+			v.fail("api.login.clientId", "is not set")
+		}
+		if c.Login.ClientSecret == "" {
+			v.fail("api.login.clientSecret", "is not set")
+		}
+		if c.Login.RedirectURL == "" {
+			v.fail("api.login.redirectUrl", "is not set")
+		}
+	}
+}
+
+// modules checks that module overrides name modules and commands that exist.
+// The commands of the custom module are added at runtime, so they cannot be checked.
+func (v *validator) modules(path string, modules map[string]*ModuleSettings) {
+	for _, name := range keys(modules, nil) {
+		known := name == twitch.CustomModule
+		for _, m := range requiredModules {
+			known = known || m.name == name
+		}
+		if !known {
+			v.fail(join(path, name), fmt.Sprintf("unknown module '%s'", name))
+			continue
+		}
+		m := modules[name]
+		if m == nil || name == twitch.CustomModule {
+			continue
+		}
+		for _, command := range commandKeys(m.Commands, nil) {
+			if _, ok := defaultCommand(name, command); !ok {
+				v.fail(join(join(path, name)+".commands", command), fmt.Sprintf("unknown command '%s' in module '%s'", command, name))
+			}
+		}
+	}
+}
+
+// validator collects the problems found in a configuration.
+type validator struct {
+	errs ConfigErrors
+}
+
+func (v *validator) fail(path, message string) {
+	v.errs = append(v.errs, ConfigError{Path: path, Message: message})
+}
+
+func (v *validator) channel(path, name string) {
+	if !channelname.Valid(name) {
+		v.fail(path, fmt.Sprintf("invalid channel name '%s': expected up to 25 lower case letters, digits and underscores", name))
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
@@ -0,0 +1,11 @@
+import axios from 'axios'
+
+let config = null
+
+// load fetches the runtime configuration from the bot that serves the dashboard.
+export default function load () {
+  if (!config) {
+    config = axios.get('/config.json').then(resp => resp.data)
+  }
+  return config
+}
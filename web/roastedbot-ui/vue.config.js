@@ -0,0 +1,8 @@
+module.exports = {
+  // The bot embeds the built dashboard from web/dist.
+  outputDir: '../dist',
+  devServer: {
+    // Send API requests to a locally running bot during development.
+    proxy: 'http://localhost:9001'
+  }
+}
@@ -0,0 +1,24 @@
+// Package web embeds the built dashboard into the bot.
+// The dashboard is built into web/dist by running "npm run build" in web/roastedbot-ui.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+// Dashboard returns the files of the built dashboard,
+// or nil if the dashboard was not built before the bot.
+func Dashboard() fs.FS {
+	sub, err := fs.Sub(dist, "dist")
+	if err != nil {
+		return nil
+	}
+	if _, err := fs.Stat(sub, "index.html"); err != nil {
+		return nil
+	}
+	return sub
+}